@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterKeycloak is the Schema for the clusterkeycloaks API.
+// It describes a Keycloak instance that can be shared by all namespaces. The admin credentials
+// of a ClusterKeycloak are looked up in the namespace the operator is running in.
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:root=true
+type ClusterKeycloak struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakSpec   `json:"spec,omitempty"`
+	Status KeycloakStatus `json:"status,omitempty"`
+}
+
+// ClusterKeycloakList contains a list of ClusterKeycloak.
+// +kubebuilder:object:root=true
+type ClusterKeycloakList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterKeycloak `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterKeycloak{}, &ClusterKeycloakList{})
+}
+
+func (i *ClusterKeycloak) UpdateStatusSecondaryResources(kind string, resourceName string) {
+	i.Status.SecondaryResources = UpdateStatusSecondaryResources(i.Status.SecondaryResources, kind, resourceName)
+}
+
+// ClusterKeycloakKind is the kind of the namespaced views of ClusterKeycloaks
+const ClusterKeycloakKind = "ClusterKeycloak"
+
+// ToKeycloak returns a namespaced view of the ClusterKeycloak, placed in the namespace that holds its credentials.
+// It allows the cluster scoped instance to be used wherever a Keycloak is expected. The view keeps the kind
+// ClusterKeycloak, so it is not mistaken for a Keycloak of the same name in that namespace.
+func (i *ClusterKeycloak) ToKeycloak(credentialNamespace string) Keycloak {
+	keycloak := Keycloak{
+		TypeMeta:   metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: ClusterKeycloakKind},
+		ObjectMeta: *i.ObjectMeta.DeepCopy(),
+		Spec:       *i.Spec.DeepCopy(),
+		Status:     *i.Status.DeepCopy(),
+	}
+	keycloak.Namespace = credentialNamespace
+	return keycloak
+}
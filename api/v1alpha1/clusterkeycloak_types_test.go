@@ -0,0 +1,52 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterKeycloak_ToKeycloak(t *testing.T) {
+	// given
+	cr := &ClusterKeycloak{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "sso",
+			Labels: map[string]string{"app": "sso"},
+		},
+		Spec: KeycloakSpec{
+			Unmanaged: true,
+			External:  KeycloakExternal{Enabled: true, URL: "https://keycloak.example.com"},
+		},
+	}
+
+	// when
+	keycloak := cr.ToKeycloak("operator")
+
+	// then
+	assert.Equal(t, "sso", keycloak.Name)
+	assert.Equal(t, "operator", keycloak.Namespace)
+	assert.Equal(t, ClusterKeycloakKind, keycloak.Kind)
+	assert.Equal(t, "https://keycloak.example.com", keycloak.Spec.External.URL)
+
+	keycloak.Labels["app"] = "changed"
+	assert.Equal(t, "sso", cr.Labels["app"])
+}
+
+func TestClusterKeycloakRealm_ToKeycloakRealm(t *testing.T) {
+	// given
+	cr := &ClusterKeycloakRealm{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic"},
+		Spec: KeycloakRealmSpec{
+			Realm: &KeycloakAPIRealm{Realm: "basic"},
+		},
+	}
+
+	// when
+	realm := cr.ToKeycloakRealm()
+
+	// then
+	assert.Equal(t, "basic", realm.Name)
+	assert.Empty(t, realm.Namespace)
+	assert.Equal(t, "basic", realm.Spec.Realm.Realm)
+}
@@ -0,0 +1,31 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterKeycloakClientTemplate is the Schema for the clusterkeycloakclienttemplates API.
+// It is shared by the KeycloakClients of all namespaces.
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:root=true
+type ClusterKeycloakClientTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KeycloakClientTemplateSpec `json:"spec,omitempty"`
+}
+
+// ClusterKeycloakClientTemplateList contains a list of ClusterKeycloakClientTemplate
+// +kubebuilder:object:root=true
+type ClusterKeycloakClientTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterKeycloakClientTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterKeycloakClientTemplate{}, &ClusterKeycloakClientTemplateList{})
+}
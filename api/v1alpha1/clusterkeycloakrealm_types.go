@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterKeycloakRealm is the Schema for the clusterkeycloakrealms API.
+// Its instance selector is used to look up ClusterKeycloak Custom Resources.
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:root=true
+type ClusterKeycloakRealm struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakRealmSpec   `json:"spec,omitempty"`
+	Status KeycloakRealmStatus `json:"status,omitempty"`
+}
+
+// ClusterKeycloakRealmList contains a list of ClusterKeycloakRealm
+// +kubebuilder:object:root=true
+type ClusterKeycloakRealmList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterKeycloakRealm `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterKeycloakRealm{}, &ClusterKeycloakRealmList{})
+}
+
+func (i *ClusterKeycloakRealm) UpdateStatusSecondaryResources(kind string, resourceName string) {
+	i.Status.SecondaryResources = UpdateStatusSecondaryResources(i.Status.SecondaryResources, kind, resourceName)
+}
+
+// ToKeycloakRealm returns a namespaced view of the ClusterKeycloakRealm so it can be used wherever a KeycloakRealm is expected.
+func (i *ClusterKeycloakRealm) ToKeycloakRealm() KeycloakRealm {
+	return KeycloakRealm{
+		ObjectMeta: *i.ObjectMeta.DeepCopy(),
+		Spec:       *i.Spec.DeepCopy(),
+		Status:     *i.Status.DeepCopy(),
+	}
+}
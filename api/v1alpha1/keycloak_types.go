@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,6 +25,35 @@ type KeycloakExternal struct {
 	// The URL to use for the keycloak admin API. Needs to be set if external is true.
 	// +optional
 	URL string `json:"url,omitempty"`
+	// Timeout of the requests to the keycloak admin API, e.g. 30s. Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// URL of the HTTP or HTTPS proxy the requests to the keycloak are sent through, e.g. http://proxy:3128.
+	// Defaults to the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables of the operator.
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+	// PEM encoded CA certificates the certificate of the keycloak is verified with instead of the CAs of the system.
+	// Defaults to the tls.crt of the sso-x509-https-secret Secret, if it exists.
+	// +optional
+	CABundle *KeycloakCABundle `json:"caBundle,omitempty"`
+	// Name of a kubernetes.io/tls Secret with the certificate (tls.crt) and private key (tls.key) the operator
+	// authenticates with when the keycloak requires mutual TLS.
+	// +optional
+	ClientCertificateSecret string `json:"clientCertificateSecret,omitempty"`
+	// If set to true, the certificate of the keycloak is not verified. This is insecure and only meant for testing.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// KeycloakCABundle is a key of a Secret or ConfigMap with PEM encoded CA certificates. They are read from the
+// namespace of the Keycloak, for a ClusterKeycloak from the operator namespace.
+type KeycloakCABundle struct {
+	// Key of a Secret with the CA certificates, either secretKeyRef or configMapKeyRef needs to be set.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Key of a ConfigMap with the CA certificates, either secretKeyRef or configMapKeyRef needs to be set.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
 }
 
 // KeycloakStatus defines the observed state of Keycloak.
@@ -37,12 +67,45 @@ type KeycloakStatus struct {
 	Ready bool `json:"ready"`
 	// A map of all the secondary resources types and names created for this CR. e.g "Deployment": [ "DeploymentName1", "DeploymentName2" ].
 	SecondaryResources map[string][]string `json:"secondaryResources,omitempty"`
-	// Version of Keycloak or RHSSO running on the cluster.
+	// Version of Keycloak or RHSSO running on the cluster, as reported by its server info.
 	Version string `json:"version"`
 	// External URL for accessing Keycloak instance from outside the cluster. Is identical to external.URL if it's specified, otherwise is computed (e.g. from Ingress).
 	ExternalURL string `json:"externalURL,omitempty"`
 	// The secret where the admin credentials are to be found.
 	CredentialSecret string `json:"credentialSecret"`
+	// Conditions of the Keycloak, e.g. KeycloakReachable.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Time of the last successful login of the controller to the Keycloak admin API.
+	// +optional
+	LastContactTime *metav1.Time `json:"lastContactTime,omitempty"`
+	// Features and providers of the Keycloak, as reported by its server info. Clients are validated against them.
+	// +optional
+	ServerInfo *KeycloakServerInfo `json:"serverInfo,omitempty"`
+}
+
+// KeycloakServerInfo are the capabilities of a Keycloak, as reported by the server info endpoint of its admin API.
+type KeycloakServerInfo struct {
+	// Enabled features, e.g. authorization or token-exchange.
+	// +optional
+	EnabledFeatures []string `json:"enabledFeatures,omitempty"`
+	// Disabled features, e.g. authorization or token-exchange.
+	// +optional
+	DisabledFeatures []string `json:"disabledFeatures,omitempty"`
+	// IDs of the available protocol mapper types by protocol, e.g. oidc-audience-mapper for openid-connect.
+	// +optional
+	ProtocolMapperTypes map[string][]string `json:"protocolMapperTypes,omitempty"`
+	// IDs of the available authenticators of authentication flows, e.g. auth-username-password-form.
+	// +optional
+	Authenticators []string `json:"authenticators,omitempty"`
+	// IDs of the available client authenticators, e.g. client-secret or client-jwt.
+	// +optional
+	ClientAuthenticators []string `json:"clientAuthenticators,omitempty"`
+	// Time the server info was queried.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
 }
 
 type StatusPhase string
@@ -54,6 +117,21 @@ var (
 	PhaseInitialising StatusPhase = "initialising"
 )
 
+const (
+	// ConditionReferencesResolved is true when all roles, clients, authorization resources, scopes, policies and
+	// authentication flows referenced by name could be resolved in Keycloak.
+	ConditionReferencesResolved = "ReferencesResolved"
+
+	ReasonReferencesResolved   = "ReferencesResolved"
+	ReasonUnresolvedReferences = "UnresolvedReferences"
+
+	// ConditionKeycloakReachable is true when the last login of the controller to the Keycloak admin API succeeded.
+	ConditionKeycloakReachable = "KeycloakReachable"
+
+	ReasonLoginSucceeded = "LoginSucceeded"
+	ReasonLoginFailed    = "LoginFailed"
+)
+
 // Keycloak is the Schema for the keycloaks API.
 // +genclient
 // +k8s:openapi-gen=true
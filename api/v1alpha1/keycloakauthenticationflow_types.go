@@ -0,0 +1,218 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AuthenticationFlowRealmBinding is a realm flow binding a KeycloakAuthenticationFlow can be bound to.
+// +kubebuilder:validation:Enum=browser;directGrant;resetCredentials
+type AuthenticationFlowRealmBinding string
+
+const (
+	AuthenticationFlowRealmBindingBrowser          AuthenticationFlowRealmBinding = "browser"
+	AuthenticationFlowRealmBindingDirectGrant      AuthenticationFlowRealmBinding = "directGrant"
+	AuthenticationFlowRealmBindingResetCredentials AuthenticationFlowRealmBinding = "resetCredentials"
+)
+
+// KeycloakAuthenticationFlowSpec defines the desired state of KeycloakAuthenticationFlow.
+// +k8s:openapi-gen=true
+type KeycloakAuthenticationFlowSpec struct {
+	// Selector for looking up KeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	RealmSelector *metav1.LabelSelector `json:"realmSelector,omitempty"`
+	// Selector for looking up ClusterKeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	ClusterRealmSelector *metav1.LabelSelector `json:"clusterRealmSelector,omitempty"`
+	// Top level authentication flow. The flow is matched by alias.
+	// +kubebuilder:validation:Required
+	Flow *KeycloakAuthenticationFlowDefinition `json:"flow"`
+	// Realm flow bindings the flow is bound to. When the flow is unbound or deleted,
+	// the binding is reset to the built-in flow of Keycloak.
+	// +optional
+	RealmBindings []AuthenticationFlowRealmBinding `json:"realmBindings,omitempty"`
+}
+
+// KeycloakAuthenticationFlowDefinition defines a top level authentication flow with its executions.
+// +k8s:openapi-gen=true
+type KeycloakAuthenticationFlowDefinition struct {
+	// Alias of the flow.
+	// +kubebuilder:validation:Required
+	Alias string `json:"alias"`
+	// Description of the flow.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Type of the flow, basic-flow for user authentication or client-flow for client authentication.
+	// The type can't be changed after the flow is created.
+	// +kubebuilder:validation:Enum=basic-flow;client-flow
+	// +kubebuilder:default=basic-flow
+	// +optional
+	ProviderID string `json:"providerId,omitempty"`
+	// Executions of the flow.
+	// +optional
+	Executions []KeycloakAuthenticationExecution `json:"executions,omitempty"`
+}
+
+// KeycloakAuthenticationExecution is an authenticator or a sub-flow of an authentication flow.
+// +k8s:openapi-gen=true
+type KeycloakAuthenticationExecution struct {
+	// Provider ID of the authenticator, e.g. auth-cookie or auth-username-password-form.
+	// Either authenticator or subFlow needs to be set.
+	// +optional
+	Authenticator string `json:"authenticator,omitempty"`
+	// Sub-flow executed by this execution.
+	// Either authenticator or subFlow needs to be set.
+	// +optional
+	SubFlow *KeycloakAuthenticationSubFlow `json:"subFlow,omitempty"`
+	// Requirement of the execution.
+	// +kubebuilder:validation:Enum=REQUIRED;ALTERNATIVE;DISABLED;CONDITIONAL
+	// +kubebuilder:validation:Required
+	Requirement string `json:"requirement"`
+	// Priority of the execution in its flow, executions with a lower priority are executed first.
+	// Executions with the same priority are executed in the given order.
+	// +optional
+	Priority int `json:"priority,omitempty"`
+	// Config of the authenticator.
+	// +optional
+	AuthenticatorConfig *KeycloakAPIAuthenticatorConfig `json:"authenticatorConfig,omitempty"`
+}
+
+// KeycloakAuthenticationSubFlow is a sub-flow of an authentication flow.
+// +k8s:openapi-gen=true
+type KeycloakAuthenticationSubFlow struct {
+	// Alias of the sub-flow, it has to be unique in the realm.
+	// +kubebuilder:validation:Required
+	Alias string `json:"alias"`
+	// Description of the sub-flow.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Type of the sub-flow, basic-flow or form-flow.
+	// +kubebuilder:validation:Enum=basic-flow;form-flow
+	// +kubebuilder:default=basic-flow
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Form provider of a form-flow, e.g. registration-page-form.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+	// Executions of the sub-flow.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Executions []KeycloakAuthenticationExecution `json:"executions,omitempty"`
+}
+
+// KeycloakAPIAuthenticationFlow is the Keycloak AuthenticationFlowRepresentation without executions.
+// +k8s:openapi-gen=true
+type KeycloakAPIAuthenticationFlow struct {
+	// +optional
+	ID string `json:"id,omitempty"`
+	// +optional
+	Alias string `json:"alias,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// +optional
+	ProviderID string `json:"providerId,omitempty"`
+	// +optional
+	TopLevel bool `json:"topLevel"`
+	// +optional
+	BuiltIn bool `json:"builtIn"`
+}
+
+// KeycloakAPIAuthenticationExecutionInfo is the Keycloak AuthenticationExecutionInfoRepresentation.
+// Keycloak lists the executions of a flow and all of its sub-flows depth first, the level is the depth
+// of the execution.
+// +k8s:openapi-gen=true
+type KeycloakAPIAuthenticationExecutionInfo struct {
+	// +optional
+	ID string `json:"id,omitempty"`
+	// +optional
+	Requirement string `json:"requirement,omitempty"`
+	// Alias of the sub-flow for flow executions.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// +optional
+	Configurable bool `json:"configurable,omitempty"`
+	// True if the execution is a sub-flow.
+	// +optional
+	AuthenticationFlow bool `json:"authenticationFlow,omitempty"`
+	// Provider ID of the authenticator.
+	// +optional
+	ProviderID string `json:"providerId,omitempty"`
+	// ID of the authenticator config.
+	// +optional
+	AuthenticationConfig string `json:"authenticationConfig,omitempty"`
+	// ID of the sub-flow.
+	// +optional
+	FlowID string `json:"flowId,omitempty"`
+	// +optional
+	Level int `json:"level"`
+	// +optional
+	Index int `json:"index"`
+	// +optional
+	Priority int `json:"priority,omitempty"`
+}
+
+// KeycloakAPIAuthenticatorConfig is the Keycloak AuthenticatorConfigRepresentation.
+// +k8s:openapi-gen=true
+type KeycloakAPIAuthenticatorConfig struct {
+	// ID of the config, set by the operator.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Alias of the config, it has to be unique in the realm.
+	// +kubebuilder:validation:Required
+	Alias string `json:"alias"`
+	// Config of the authenticator.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// KeycloakAPIRealmFlowBindings are the flow bindings of the Keycloak RealmRepresentation,
+// fields that are not set are not changed when the realm is updated.
+// +k8s:openapi-gen=true
+type KeycloakAPIRealmFlowBindings struct {
+	// +optional
+	BrowserFlow string `json:"browserFlow,omitempty"`
+	// +optional
+	DirectGrantFlow string `json:"directGrantFlow,omitempty"`
+	// +optional
+	ResetCredentialsFlow string `json:"resetCredentialsFlow,omitempty"`
+}
+
+// KeycloakAuthenticationFlowStatus defines the observed state of KeycloakAuthenticationFlow
+// +k8s:openapi-gen=true
+type KeycloakAuthenticationFlowStatus struct {
+	// Current phase of the operator.
+	Phase StatusPhase `json:"phase"`
+	// Human-readable message indicating details about current operator phase or error.
+	Message string `json:"message"`
+	// True if all resources are in a ready state and all work is done.
+	Ready bool `json:"ready"`
+}
+
+// KeycloakAuthenticationFlow is the Schema for the keycloakauthenticationflows API.
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+type KeycloakAuthenticationFlow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakAuthenticationFlowSpec   `json:"spec,omitempty"`
+	Status KeycloakAuthenticationFlowStatus `json:"status,omitempty"`
+}
+
+// KeycloakAuthenticationFlowList contains a list of KeycloakAuthenticationFlow.
+// +kubebuilder:object:root=true
+type KeycloakAuthenticationFlowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakAuthenticationFlow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakAuthenticationFlow{}, &KeycloakAuthenticationFlowList{})
+}
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -9,11 +10,33 @@ import (
 // +k8s:openapi-gen=true
 type KeycloakClientSpec struct {
 	// Selector for looking up KeycloakRealm Custom Resources.
-	// +kubebuilder:validation:Required
-	RealmSelector *metav1.LabelSelector `json:"realmSelector"`
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	RealmSelector *metav1.LabelSelector `json:"realmSelector,omitempty"`
+	// Selector for looking up ClusterKeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	ClusterRealmSelector *metav1.LabelSelector `json:"clusterRealmSelector,omitempty"`
+	// Template with defaults for the client, they are applied to the client in every reconciliation
+	// and never stored in the resource.
+	// +optional
+	TemplateRef *ClientTemplateReference `json:"templateRef,omitempty"`
 	// Keycloak Client REST object.
 	// +kubebuilder:validation:Required
 	Client *KeycloakAPIClient `json:"client"`
+	// Ingresses and HTTPRoutes the redirect URIs, web origins, root URL and base URL of the client are derived from.
+	// They are added to the client in every reconciliation and never stored in the resource.
+	// +optional
+	RedirectURISources *RedirectURISources `json:"redirectUriSources,omitempty"`
+	// SAML settings of the client, the protocol of the client defaults to saml when they are set.
+	// They are added to the attributes of the client in every reconciliation and never stored in the resource.
+	// +optional
+	SAML *KeycloakClientSAML `json:"saml,omitempty"`
+	// Key material of a client that authenticates with a signed JWT (client-jwt) or an X.509 certificate
+	// (client-x509). The certificate is registered with the client in every reconciliation, so Keycloak's
+	// copy follows the Secret.
+	// +optional
+	ClientAuthenticationKey *ClientAuthenticationKey `json:"clientAuthenticationKey,omitempty"`
 	// Client Roles
 	// +optional
 	// +listType=map
@@ -56,6 +79,143 @@ type ClientMappingsRepresentation struct {
 	Mappings []RoleRepresentation `json:"mappings,omitempty"`
 }
 
+// RedirectURISources are the Ingresses and Gateway API HTTPRoutes in the namespace of a KeycloakClient the redirect URIs
+// and web origins of the client are derived from. Every host of the objects is a web origin, the redirect URIs are the
+// origins with the redirect paths appended. The first origin is the root URL of the client.
+// +k8s:openapi-gen=true
+type RedirectURISources struct {
+	// Ingresses the hosts are taken from.
+	// +optional
+	Ingresses []RedirectURISourceReference `json:"ingresses,omitempty"`
+	// Gateway API HTTPRoutes the hosts are taken from.
+	// +optional
+	HTTPRoutes []RedirectURISourceReference `json:"httpRoutes,omitempty"`
+	// Scheme of the URIs. Defaults to https for hosts with TLS configured in the Ingress and for HTTPRoutes,
+	// to http otherwise.
+	// +optional
+	// +kubebuilder:validation:Enum=http;https
+	Scheme string `json:"scheme,omitempty"`
+	// Paths appended to the origins to get the redirect URIs, defaults to /*.
+	// +optional
+	RedirectPaths []string `json:"redirectPaths,omitempty"`
+	// Base URL of the client, relative to the root URL.
+	// +optional
+	BasePath string `json:"basePath,omitempty"`
+}
+
+// RedirectURISourceReference refers to Ingresses or HTTPRoutes by name or by label selector.
+// +k8s:openapi-gen=true
+type RedirectURISourceReference struct {
+	// Name of the object, either name or selector needs to be set.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Selector for the objects, either name or selector needs to be set.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// KeycloakClientSAML are the SAML settings of a client. Settings that are not given are taken from the metadata of
+// the service provider, if any, or left to the attributes of the client.
+// +k8s:openapi-gen=true
+type KeycloakClientSAML struct {
+	// Assertion consumer service URL for the POST binding.
+	// +optional
+	AssertionConsumerURLPost string `json:"assertionConsumerUrlPost,omitempty"`
+	// Assertion consumer service URL for the redirect binding.
+	// +optional
+	AssertionConsumerURLRedirect string `json:"assertionConsumerUrlRedirect,omitempty"`
+	// Single logout service URL for the POST binding.
+	// +optional
+	SingleLogoutServiceURLPost string `json:"singleLogoutServiceUrlPost,omitempty"`
+	// Single logout service URL for the redirect binding.
+	// +optional
+	SingleLogoutServiceURLRedirect string `json:"singleLogoutServiceUrlRedirect,omitempty"`
+	// Name ID format of the subject.
+	// +optional
+	// +kubebuilder:validation:Enum=username;email;transient;persistent
+	NameIDFormat string `json:"nameIdFormat,omitempty"`
+	// Ignore the name ID format requested by the client and use the configured one.
+	// +optional
+	ForceNameIDFormat *bool `json:"forceNameIdFormat,omitempty"`
+	// Sign the SAML documents sent to the client.
+	// +optional
+	SignDocuments *bool `json:"signDocuments,omitempty"`
+	// Sign the assertions sent to the client.
+	// +optional
+	SignAssertions *bool `json:"signAssertions,omitempty"`
+	// Signature algorithm of the documents and assertions.
+	// +optional
+	// +kubebuilder:validation:Enum=RSA_SHA1;RSA_SHA256;RSA_SHA256_MGF1;RSA_SHA512;RSA_SHA512_MGF1;DSA_SHA1
+	SignatureAlgorithm string `json:"signatureAlgorithm,omitempty"`
+	// Require the client to sign its requests, the signatures are verified with the signing certificate.
+	// +optional
+	ClientSignatureRequired *bool `json:"clientSignatureRequired,omitempty"`
+	// Encrypt the assertions sent to the client with the encryption certificate.
+	// +optional
+	EncryptAssertions *bool `json:"encryptAssertions,omitempty"`
+	// Always use the POST binding for responses.
+	// +optional
+	ForcePostBinding *bool `json:"forcePostBinding,omitempty"`
+	// Name of a kubernetes.io/tls Secret in the namespace of the resource with the signing certificate and
+	// private key of the client.
+	// +optional
+	SigningKeySecret string `json:"signingKeySecret,omitempty"`
+	// Name of a kubernetes.io/tls Secret in the namespace of the resource with the encryption certificate of
+	// the client, only tls.crt is used.
+	// +optional
+	EncryptionCertificateSecret string `json:"encryptionCertificateSecret,omitempty"`
+	// SAML metadata of the service provider the endpoints, name ID format and certificates of the client
+	// are taken from.
+	// +optional
+	ServiceProviderMetadata *SAMLMetadataSource `json:"serviceProviderMetadata,omitempty"`
+	// Name of a ConfigMap in the namespace of the resource the SAML metadata of the identity provider is
+	// published to, in the key idp-metadata.xml.
+	// +optional
+	IdentityProviderMetadataConfigMap string `json:"identityProviderMetadataConfigMap,omitempty"`
+}
+
+// SAMLMetadataSource is a URL or ConfigMap key the SAML metadata is read from.
+// +k8s:openapi-gen=true
+type SAMLMetadataSource struct {
+	// URL of the metadata, either url or configMapKeyRef needs to be set.
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Key of a ConfigMap in the namespace of the resource with the metadata, either url or configMapKeyRef
+	// needs to be set.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// ClientAuthenticationKey refers to the Secret with the certificate a client authenticates with. For client-jwt the
+// certificate or its public key as JWKS is registered with the client, for client-x509 its subject DN.
+// +k8s:openapi-gen=true
+type ClientAuthenticationKey struct {
+	// Name of a kubernetes.io/tls Secret in the namespace of the resource with the certificate (tls.crt) and
+	// private key (tls.key) of the client.
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+	// Generate a private key and a self-signed certificate and store them in the Secret when it doesn't exist.
+	// The Secret is owned by the resource, the key ID of the current key is stored in its kid key.
+	// +optional
+	Generate bool `json:"generate,omitempty"`
+	// Algorithm of generated keys, RS256 by default. Also restricts the algorithm of the signed JWTs of
+	// client-jwt clients when set.
+	// +optional
+	// +kubebuilder:validation:Enum=RS256;ES256;ES384
+	Algorithm string `json:"algorithm,omitempty"`
+	// Period after which a generated key is replaced by a new one. Generated keys are not rotated without it.
+	// +optional
+	RotationPeriod *metav1.Duration `json:"rotationPeriod,omitempty"`
+	// Time the previous key stays registered after a rotation, so workloads can switch to the new key.
+	// The previous certificate is kept in the previous.crt key of the Secret. Requires useJwks, as only
+	// one certificate can be registered otherwise.
+	// +optional
+	RotationOverlap *metav1.Duration `json:"rotationOverlap,omitempty"`
+	// Register the public key as JWKS instead of the certificate, client-jwt only.
+	// +optional
+	UseJWKS bool `json:"useJwks,omitempty"`
+}
+
 type KeycloakAPIClient struct {
 	// Client ID. If not specified, automatically generated.
 	// +optional
@@ -138,9 +298,12 @@ type KeycloakAPIClient struct {
 	// Node registration timeout.
 	// +optional
 	NodeReRegistrationTimeout int `json:"nodeReRegistrationTimeout,omitempty"`
-	// Protocol Mappers.
+	// Protocol Mappers, matched by name. When set, even to an empty list, mappers of the client that are missing are
+	// deleted, except the mappers Keycloak adds for service accounts. When not set, the mappers of the client are not
+	// managed, e.g. because they are maintained in the admin console.
 	// +optional
-	ProtocolMappers []KeycloakProtocolMapper `json:"protocolMappers,omitempty"`
+	// +nullable
+	ProtocolMappers []KeycloakProtocolMapper `json:"protocolMappers"`
 	// True to use a Template Config.
 	// +optional
 	UseTemplateConfig bool `json:"useTemplateConfig,omitempty"`
@@ -167,10 +330,11 @@ type KeycloakAPIClient struct {
 	// True if fine-grained authorization support is enabled for this client.
 	// +optional
 	AuthorizationServicesEnabled bool `json:"authorizationServicesEnabled,omitempty"`
-	// Authorization settings for this resource server.
+	// Authorization settings for this resource server. Scopes, resources and policies (including permissions) are
+	// matched by name, those that are missing in the settings are deleted from the resource server.
 	// +optional
 	AuthorizationSettings *KeycloakResourceServer `json:"authorizationSettings,omitempty"`
-	// Authentication Flow Binding Overrides.
+	// Authentication Flow Binding Overrides, e.g. browser or direct_grant. Flows are given by their ID or alias.
 	// +optional
 	AuthenticationFlowBindingOverrides map[string]string `json:"authenticationFlowBindingOverrides,omitempty"`
 }
@@ -361,6 +525,11 @@ type KeycloakClientStatus struct {
 	Ready bool `json:"ready"`
 	// A map of all the secondary resources types and names created for this CR. e.g "Deployment": [ "DeploymentName1", "DeploymentName2" ]
 	SecondaryResources map[string][]string `json:"secondaryResources,omitempty"`
+	// Conditions of the resource, e.g. ReferencesResolved.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // KeycloakClient is the Schema for the keycloakclients API.
@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// The client scope is added as default client scope to new clients of the realm
+	ClientScopeRealmAssignmentDefault = "default"
+	// The client scope is added as optional client scope to new clients of the realm
+	ClientScopeRealmAssignmentOptional = "optional"
+	// The client scope is not added to new clients of the realm
+	ClientScopeRealmAssignmentNone = "none"
+)
+
+// KeycloakClientScopeSpec defines the desired state of KeycloakClientScope.
+// +k8s:openapi-gen=true
+type KeycloakClientScopeSpec struct {
+	// Selector for looking up KeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	RealmSelector *metav1.LabelSelector `json:"realmSelector,omitempty"`
+	// Selector for looking up ClusterKeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	ClusterRealmSelector *metav1.LabelSelector `json:"clusterRealmSelector,omitempty"`
+	// Keycloak Client Scope REST object. The client scope is matched by name.
+	// +kubebuilder:validation:Required
+	ClientScope *KeycloakAPIClientScope `json:"clientScope"`
+	// Roles of the realm and of clients that are added to tokens issued with this client scope.
+	// +optional
+	ScopeMappings *MappingsRepresentation `json:"scopeMappings,omitempty"`
+	// Assignment of the client scope to new clients of the realm, one of default, optional or none.
+	// Defaults to none.
+	// +kubebuilder:validation:Enum=default;optional;none
+	// +optional
+	RealmAssignment string `json:"realmAssignment,omitempty"`
+}
+
+// KeycloakClientScopeStatus defines the observed state of KeycloakClientScope
+// +k8s:openapi-gen=true
+type KeycloakClientScopeStatus struct {
+	// Current phase of the operator.
+	Phase StatusPhase `json:"phase"`
+	// Human-readable message indicating details about current operator phase or error.
+	Message string `json:"message"`
+	// True if all resources are in a ready state and all work is done.
+	Ready bool `json:"ready"`
+	// Conditions of the resource, e.g. ReferencesResolved.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// KeycloakClientScope is the Schema for the keycloakclientscopes API.
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+type KeycloakClientScope struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakClientScopeSpec   `json:"spec,omitempty"`
+	Status KeycloakClientScopeStatus `json:"status,omitempty"`
+}
+
+// KeycloakClientScopeList contains a list of KeycloakClientScope.
+// +kubebuilder:object:root=true
+type KeycloakClientScopeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakClientScope `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakClientScope{}, &KeycloakClientScopeList{})
+}
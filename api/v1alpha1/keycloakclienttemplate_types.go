@@ -0,0 +1,64 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Kind of the templates in the namespace of a KeycloakClient
+	ClientTemplateKind = "KeycloakClientTemplate"
+	// Kind of the templates that are shared by the KeycloakClients of all namespaces
+	ClusterClientTemplateKind = "ClusterKeycloakClientTemplate"
+)
+
+// KeycloakClientTemplateSpec defines the defaults of the KeycloakClients that refer to the template.
+// +k8s:openapi-gen=true
+type KeycloakClientTemplateSpec struct {
+	// Defaults for the Keycloak Client REST object of the KeycloakClients, any field of a client may be given.
+	// Fields that are not set in the KeycloakClient are taken from the template. The attributes, access and
+	// authenticationFlowBindingOverrides maps are merged, the redirectUris, webOrigins, defaultRoles,
+	// defaultClientScopes, optionalClientScopes and protocolMappers (matched by name) of the template are
+	// appended, all other fields of the KeycloakClient replace the fields of the template.
+	// +optional
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Client *KeycloakAPIClient `json:"client,omitempty"`
+}
+
+// ClientTemplateReference refers to the KeycloakClientTemplate or ClusterKeycloakClientTemplate of a KeycloakClient.
+// +k8s:openapi-gen=true
+type ClientTemplateReference struct {
+	// Kind of the template, a KeycloakClientTemplate in the namespace of the KeycloakClient or a
+	// ClusterKeycloakClientTemplate.
+	// +optional
+	// +kubebuilder:validation:Enum=KeycloakClientTemplate;ClusterKeycloakClientTemplate
+	// +kubebuilder:default=KeycloakClientTemplate
+	Kind string `json:"kind,omitempty"`
+	// Name of the template.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// KeycloakClientTemplate is the Schema for the keycloakclienttemplates API.
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+type KeycloakClientTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KeycloakClientTemplateSpec `json:"spec,omitempty"`
+}
+
+// KeycloakClientTemplateList contains a list of KeycloakClientTemplate.
+// +kubebuilder:object:root=true
+type KeycloakClientTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakClientTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakClientTemplate{}, &KeycloakClientTemplateList{})
+}
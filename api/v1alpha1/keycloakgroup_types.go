@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_grouprepresentation
+type KeycloakAPIGroup struct {
+	// Group ID.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Group name, unique among the groups with the same parent.
+	Name string `json:"name"`
+	// Path of the group, e.g. /parent/child.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Group attributes.
+	// +optional
+	Attributes map[string][]string `json:"attributes,omitempty"`
+	// Names of the realm roles mapped to the members of the group.
+	// +optional
+	RealmRoles []string `json:"realmRoles,omitempty"`
+	// Names of the client roles mapped to the members of the group, by clientId.
+	// +optional
+	ClientRoles map[string][]string `json:"clientRoles,omitempty"`
+	// Subgroups of the group, with the same fields as the group itself.
+	// The schema is not validated as CRDs don't support recursive types.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	SubGroups []KeycloakAPIGroup `json:"subGroups,omitempty"`
+}
+
+// KeycloakGroupSpec defines the desired state of KeycloakGroup.
+// +k8s:openapi-gen=true
+type KeycloakGroupSpec struct {
+	// Selector for looking up KeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	RealmSelector *metav1.LabelSelector `json:"realmSelector,omitempty"`
+	// Selector for looking up ClusterKeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	ClusterRealmSelector *metav1.LabelSelector `json:"clusterRealmSelector,omitempty"`
+	// Keycloak Group REST object. The group and its subgroups are matched by path.
+	// +kubebuilder:validation:Required
+	Group *KeycloakAPIGroup `json:"group"`
+	// True if new users of the realm become members of the group.
+	// +optional
+	DefaultGroup bool `json:"defaultGroup,omitempty"`
+}
+
+// KeycloakGroupStatus defines the observed state of KeycloakGroup
+// +k8s:openapi-gen=true
+type KeycloakGroupStatus struct {
+	// Current phase of the operator.
+	Phase StatusPhase `json:"phase"`
+	// Human-readable message indicating details about current operator phase or error.
+	Message string `json:"message"`
+	// True if all resources are in a ready state and all work is done.
+	Ready bool `json:"ready"`
+}
+
+// KeycloakGroup is the Schema for the keycloakgroups API.
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+type KeycloakGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakGroupSpec   `json:"spec,omitempty"`
+	Status KeycloakGroupStatus `json:"status,omitempty"`
+}
+
+// KeycloakGroupList contains a list of KeycloakGroup.
+// +kubebuilder:object:root=true
+type KeycloakGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakGroup{}, &KeycloakGroupList{})
+}
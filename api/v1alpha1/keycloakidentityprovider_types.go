@@ -0,0 +1,138 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Config key of the client secret of an OIDC identity provider
+const IdentityProviderClientSecretConfigKey = "clientSecret"
+
+// KeycloakIdentityProviderSpec defines the desired state of KeycloakIdentityProvider.
+// +k8s:openapi-gen=true
+type KeycloakIdentityProviderSpec struct {
+	// Selector for looking up KeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	RealmSelector *metav1.LabelSelector `json:"realmSelector,omitempty"`
+	// Selector for looking up ClusterKeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	ClusterRealmSelector *metav1.LabelSelector `json:"clusterRealmSelector,omitempty"`
+	// Keycloak Identity Provider REST object. The identity provider is matched by alias.
+	// +kubebuilder:validation:Required
+	IdentityProvider *KeycloakAPIIdentityProvider `json:"identityProvider"`
+	// Secret key with the client secret of the identity provider in the namespace of the KeycloakIdentityProvider.
+	// The client secret is sent to Keycloak as clientSecret config and never stored in the resource.
+	// +optional
+	ClientSecret *corev1.SecretKeySelector `json:"clientSecret,omitempty"`
+	// Mappers of the identity provider, matched by name.
+	// +optional
+	Mappers []KeycloakAPIIdentityProviderMapper `json:"mappers,omitempty"`
+}
+
+// KeycloakAPIIdentityProvider is the Keycloak IdentityProviderRepresentation.
+// +k8s:openapi-gen=true
+type KeycloakAPIIdentityProvider struct {
+	// Identity Provider Alias.
+	// +kubebuilder:validation:Required
+	Alias string `json:"alias"`
+	// Identity Provider Internal ID.
+	// +optional
+	InternalID string `json:"internalId,omitempty"`
+	// Identity Provider Display Name.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+	// Identity Provider ID, e.g. oidc, saml, github or microsoft.
+	// +kubebuilder:validation:Required
+	ProviderID string `json:"providerId"`
+	// Identity Provider enabled flag.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled bool `json:"enabled"`
+	// Identity Provider Trust Email.
+	// +optional
+	TrustEmail bool `json:"trustEmail"`
+	// Identity Provider Store to Token.
+	// +optional
+	StoreToken bool `json:"storeToken"`
+	// Adds Read Token role when creating this Identity Provider.
+	// +optional
+	AddReadTokenRoleOnCreate bool `json:"addReadTokenRoleOnCreate"`
+	// Users can only link existing accounts with this Identity Provider.
+	// +optional
+	LinkOnly bool `json:"linkOnly"`
+	// Hides the Identity Provider on the login page.
+	// +optional
+	HideOnLogin bool `json:"hideOnLogin,omitempty"`
+	// Alias of the authentication flow that is triggered after the first login with this Identity Provider.
+	// +kubebuilder:default="first broker login"
+	// +optional
+	FirstBrokerLoginFlowAlias string `json:"firstBrokerLoginFlowAlias,omitempty"`
+	// Alias of the authentication flow that is triggered after each login with this Identity Provider.
+	// +optional
+	PostBrokerLoginFlowAlias string `json:"postBrokerLoginFlowAlias,omitempty"`
+	// Identity Provider config, e.g. clientId, authorizationUrl and tokenUrl of an OIDC provider
+	// or singleSignOnServiceUrl of a SAML provider. Use clientSecret of the spec for the client secret.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// KeycloakAPIIdentityProviderMapper is the Keycloak IdentityProviderMapperRepresentation.
+// +k8s:openapi-gen=true
+type KeycloakAPIIdentityProviderMapper struct {
+	// Identity Provider Mapper ID.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Identity Provider Mapper Name.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Alias of the Identity Provider, set by the operator.
+	// +optional
+	IdentityProviderAlias string `json:"identityProviderAlias,omitempty"`
+	// Identity Provider Mapper type, e.g. oidc-user-attribute-idp-mapper or hardcoded-role-idp-mapper.
+	// +kubebuilder:validation:Required
+	IdentityProviderMapper string `json:"identityProviderMapper"`
+	// Identity Provider Mapper config.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// KeycloakIdentityProviderStatus defines the observed state of KeycloakIdentityProvider
+// +k8s:openapi-gen=true
+type KeycloakIdentityProviderStatus struct {
+	// Current phase of the operator.
+	Phase StatusPhase `json:"phase"`
+	// Human-readable message indicating details about current operator phase or error.
+	Message string `json:"message"`
+	// True if all resources are in a ready state and all work is done.
+	Ready bool `json:"ready"`
+	// Resource version of the client secret that was last sent to Keycloak.
+	// +optional
+	ClientSecretVersion string `json:"clientSecretVersion,omitempty"`
+}
+
+// KeycloakIdentityProvider is the Schema for the keycloakidentityproviders API.
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+type KeycloakIdentityProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakIdentityProviderSpec   `json:"spec,omitempty"`
+	Status KeycloakIdentityProviderStatus `json:"status,omitempty"`
+}
+
+// KeycloakIdentityProviderList contains a list of KeycloakIdentityProvider.
+// +kubebuilder:object:root=true
+type KeycloakIdentityProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakIdentityProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakIdentityProvider{}, &KeycloakIdentityProviderList{})
+}
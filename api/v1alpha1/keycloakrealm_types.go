@@ -31,14 +31,14 @@ type KeycloakAPIRealm struct {
 	Enabled bool `json:"enabled"`
 	// Client scopes
 	// +optional
-	ClientScopes []KeycloakClientScope `json:"clientScopes,omitempty"`
+	ClientScopes []KeycloakAPIClientScope `json:"clientScopes,omitempty"`
 
 	// Default role
 	// +optional
 	DefaultRole *RoleRepresentation `json:"defaultRole,omitempty"`
 }
 
-type KeycloakClientScope struct {
+type KeycloakAPIClientScope struct {
 	// +optional
 	Attributes map[string]string `json:"attributes,omitempty"`
 	// +optional
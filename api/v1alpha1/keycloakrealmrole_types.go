@@ -0,0 +1,58 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeycloakRealmRoleSpec defines the desired state of KeycloakRealmRole.
+// +k8s:openapi-gen=true
+type KeycloakRealmRoleSpec struct {
+	// Selector for looking up KeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	RealmSelector *metav1.LabelSelector `json:"realmSelector,omitempty"`
+	// Selector for looking up ClusterKeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	ClusterRealmSelector *metav1.LabelSelector `json:"clusterRealmSelector,omitempty"`
+	// Keycloak Realm Role REST object. The role is matched by name.
+	// Composite realm roles are referenced by name, composite client roles by clientId and name.
+	// +kubebuilder:validation:Required
+	Role *RoleRepresentation `json:"role"`
+}
+
+// KeycloakRealmRoleStatus defines the observed state of KeycloakRealmRole
+// +k8s:openapi-gen=true
+type KeycloakRealmRoleStatus struct {
+	// Current phase of the operator.
+	Phase StatusPhase `json:"phase"`
+	// Human-readable message indicating details about current operator phase or error.
+	Message string `json:"message"`
+	// True if all resources are in a ready state and all work is done.
+	Ready bool `json:"ready"`
+}
+
+// KeycloakRealmRole is the Schema for the keycloakrealmroles API.
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+type KeycloakRealmRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakRealmRoleSpec   `json:"spec,omitempty"`
+	Status KeycloakRealmRoleStatus `json:"status,omitempty"`
+}
+
+// KeycloakRealmRoleList contains a list of KeycloakRealmRole.
+// +kubebuilder:object:root=true
+type KeycloakRealmRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakRealmRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakRealmRole{}, &KeycloakRealmRoleList{})
+}
@@ -1,5 +1,68 @@
 package v1alpha1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeycloakUserSpec defines the desired state of KeycloakUser.
+// +k8s:openapi-gen=true
+type KeycloakUserSpec struct {
+	// Selector for looking up KeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	RealmSelector *metav1.LabelSelector `json:"realmSelector,omitempty"`
+	// Selector for looking up ClusterKeycloakRealm Custom Resources.
+	// Either realmSelector or clusterRealmSelector needs to be set.
+	// +optional
+	ClusterRealmSelector *metav1.LabelSelector `json:"clusterRealmSelector,omitempty"`
+	// Keycloak User REST object. The user is matched by username.
+	// Groups are given by path, credentials are ignored.
+	// +kubebuilder:validation:Required
+	User *KeycloakAPIUser `json:"user"`
+	// Key of a Secret in the namespace of the KeycloakUser with the initial password of the user.
+	// A random password is generated if not set. The username and the password are published
+	// to the Secret credential-<realm>-<username>-<keycloak namespace>.
+	// +optional
+	InitialPasswordSecret *corev1.SecretKeySelector `json:"initialPasswordSecret,omitempty"`
+}
+
+// KeycloakUserStatus defines the observed state of KeycloakUser
+// +k8s:openapi-gen=true
+type KeycloakUserStatus struct {
+	// Current phase of the operator.
+	Phase StatusPhase `json:"phase"`
+	// Human-readable message indicating details about current operator phase or error.
+	Message string `json:"message"`
+	// True if all resources are in a ready state and all work is done.
+	Ready bool `json:"ready"`
+}
+
+// KeycloakUser is the Schema for the keycloakusers API.
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+type KeycloakUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakUserSpec   `json:"spec,omitempty"`
+	Status KeycloakUserStatus `json:"status,omitempty"`
+}
+
+// KeycloakUserList contains a list of KeycloakUser.
+// +kubebuilder:object:root=true
+type KeycloakUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakUser{}, &KeycloakUserList{})
+}
+
 type KeycloakAPIUser struct {
 	// User ID.
 	ID string `json:"id,omitempty"`
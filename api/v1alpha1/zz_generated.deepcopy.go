@@ -22,11 +22,37 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientAuthenticationKey) DeepCopyInto(out *ClientAuthenticationKey) {
+	*out = *in
+	if in.RotationPeriod != nil {
+		in, out := &in.RotationPeriod, &out.RotationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RotationOverlap != nil {
+		in, out := &in.RotationOverlap, &out.RotationOverlap
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientAuthenticationKey.
+func (in *ClientAuthenticationKey) DeepCopy() *ClientAuthenticationKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientAuthenticationKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientMappingsRepresentation) DeepCopyInto(out *ClientMappingsRepresentation) {
 	*out = *in
@@ -49,6 +75,197 @@ func (in *ClientMappingsRepresentation) DeepCopy() *ClientMappingsRepresentation
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientTemplateReference) DeepCopyInto(out *ClientTemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientTemplateReference.
+func (in *ClientTemplateReference) DeepCopy() *ClientTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeycloak) DeepCopyInto(out *ClusterKeycloak) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeycloak.
+func (in *ClusterKeycloak) DeepCopy() *ClusterKeycloak {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeycloak)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeycloak) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeycloakClientTemplate) DeepCopyInto(out *ClusterKeycloakClientTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeycloakClientTemplate.
+func (in *ClusterKeycloakClientTemplate) DeepCopy() *ClusterKeycloakClientTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeycloakClientTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeycloakClientTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeycloakClientTemplateList) DeepCopyInto(out *ClusterKeycloakClientTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterKeycloakClientTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeycloakClientTemplateList.
+func (in *ClusterKeycloakClientTemplateList) DeepCopy() *ClusterKeycloakClientTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeycloakClientTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeycloakClientTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeycloakList) DeepCopyInto(out *ClusterKeycloakList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterKeycloak, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeycloakList.
+func (in *ClusterKeycloakList) DeepCopy() *ClusterKeycloakList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeycloakList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeycloakList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeycloakRealm) DeepCopyInto(out *ClusterKeycloakRealm) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeycloakRealm.
+func (in *ClusterKeycloakRealm) DeepCopy() *ClusterKeycloakRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeycloakRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeycloakRealm) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeycloakRealmList) DeepCopyInto(out *ClusterKeycloakRealmList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterKeycloakRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeycloakRealmList.
+func (in *ClusterKeycloakRealmList) DeepCopy() *ClusterKeycloakRealmList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeycloakRealmList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeycloakRealmList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FederatedIdentity) DeepCopyInto(out *FederatedIdentity) {
 	*out = *in
@@ -69,7 +286,7 @@ func (in *Keycloak) DeepCopyInto(out *Keycloak) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -91,6 +308,58 @@ func (in *Keycloak) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIAuthenticationExecutionInfo) DeepCopyInto(out *KeycloakAPIAuthenticationExecutionInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIAuthenticationExecutionInfo.
+func (in *KeycloakAPIAuthenticationExecutionInfo) DeepCopy() *KeycloakAPIAuthenticationExecutionInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIAuthenticationExecutionInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIAuthenticationFlow) DeepCopyInto(out *KeycloakAPIAuthenticationFlow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIAuthenticationFlow.
+func (in *KeycloakAPIAuthenticationFlow) DeepCopy() *KeycloakAPIAuthenticationFlow {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIAuthenticationFlow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIAuthenticatorConfig) DeepCopyInto(out *KeycloakAPIAuthenticatorConfig) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIAuthenticatorConfig.
+func (in *KeycloakAPIAuthenticatorConfig) DeepCopy() *KeycloakAPIAuthenticatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIAuthenticatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakAPIClient) DeepCopyInto(out *KeycloakAPIClient) {
 	*out = *in
@@ -170,35 +439,52 @@ func (in *KeycloakAPIClient) DeepCopy() *KeycloakAPIClient {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakAPIRealm) DeepCopyInto(out *KeycloakAPIRealm) {
+func (in *KeycloakAPIClientScope) DeepCopyInto(out *KeycloakAPIClientScope) {
 	*out = *in
-	if in.ClientScopes != nil {
-		in, out := &in.ClientScopes, &out.ClientScopes
-		*out = make([]KeycloakClientScope, len(*in))
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProtocolMappers != nil {
+		in, out := &in.ProtocolMappers, &out.ProtocolMappers
+		*out = make([]KeycloakProtocolMapper, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.DefaultRole != nil {
-		in, out := &in.DefaultRole, &out.DefaultRole
-		*out = new(RoleRepresentation)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIRealm.
-func (in *KeycloakAPIRealm) DeepCopy() *KeycloakAPIRealm {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIClientScope.
+func (in *KeycloakAPIClientScope) DeepCopy() *KeycloakAPIClientScope {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakAPIRealm)
+	out := new(KeycloakAPIClientScope)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakAPIUser) DeepCopyInto(out *KeycloakAPIUser) {
+func (in *KeycloakAPIGroup) DeepCopyInto(out *KeycloakAPIGroup) {
 	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 	if in.RealmRoles != nil {
 		in, out := &in.RealmRoles, &out.RealmRoles
 		*out = make([]string, len(*in))
@@ -219,12 +505,140 @@ func (in *KeycloakAPIUser) DeepCopyInto(out *KeycloakAPIUser) {
 			(*out)[key] = outVal
 		}
 	}
-	if in.RequiredActions != nil {
-		in, out := &in.RequiredActions, &out.RequiredActions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.SubGroups != nil {
+		in, out := &in.SubGroups, &out.SubGroups
+		*out = make([]KeycloakAPIGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Groups != nil {
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIGroup.
+func (in *KeycloakAPIGroup) DeepCopy() *KeycloakAPIGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIIdentityProvider) DeepCopyInto(out *KeycloakAPIIdentityProvider) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIIdentityProvider.
+func (in *KeycloakAPIIdentityProvider) DeepCopy() *KeycloakAPIIdentityProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIIdentityProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIIdentityProviderMapper) DeepCopyInto(out *KeycloakAPIIdentityProviderMapper) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIIdentityProviderMapper.
+func (in *KeycloakAPIIdentityProviderMapper) DeepCopy() *KeycloakAPIIdentityProviderMapper {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIIdentityProviderMapper)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIRealm) DeepCopyInto(out *KeycloakAPIRealm) {
+	*out = *in
+	if in.ClientScopes != nil {
+		in, out := &in.ClientScopes, &out.ClientScopes
+		*out = make([]KeycloakAPIClientScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultRole != nil {
+		in, out := &in.DefaultRole, &out.DefaultRole
+		*out = new(RoleRepresentation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIRealm.
+func (in *KeycloakAPIRealm) DeepCopy() *KeycloakAPIRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIRealmFlowBindings) DeepCopyInto(out *KeycloakAPIRealmFlowBindings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIRealmFlowBindings.
+func (in *KeycloakAPIRealmFlowBindings) DeepCopy() *KeycloakAPIRealmFlowBindings {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIRealmFlowBindings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIUser) DeepCopyInto(out *KeycloakAPIUser) {
+	*out = *in
+	if in.RealmRoles != nil {
+		in, out := &in.RealmRoles, &out.RealmRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientRoles != nil {
+		in, out := &in.ClientRoles, &out.ClientRoles
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.RequiredActions != nil {
+		in, out := &in.RequiredActions, &out.RequiredActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
 		in, out := &in.Groups, &out.Groups
 		*out = make([]string, len(*in))
 		copy(*out, *in)
@@ -267,26 +681,51 @@ func (in *KeycloakAPIUser) DeepCopy() *KeycloakAPIUser {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakClient) DeepCopyInto(out *KeycloakClient) {
+func (in *KeycloakAuthenticationExecution) DeepCopyInto(out *KeycloakAuthenticationExecution) {
+	*out = *in
+	if in.SubFlow != nil {
+		in, out := &in.SubFlow, &out.SubFlow
+		*out = new(KeycloakAuthenticationSubFlow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthenticatorConfig != nil {
+		in, out := &in.AuthenticatorConfig, &out.AuthenticatorConfig
+		*out = new(KeycloakAPIAuthenticatorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAuthenticationExecution.
+func (in *KeycloakAuthenticationExecution) DeepCopy() *KeycloakAuthenticationExecution {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAuthenticationExecution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAuthenticationFlow) DeepCopyInto(out *KeycloakAuthenticationFlow) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClient.
-func (in *KeycloakClient) DeepCopy() *KeycloakClient {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAuthenticationFlow.
+func (in *KeycloakAuthenticationFlow) DeepCopy() *KeycloakAuthenticationFlow {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakClient)
+	out := new(KeycloakAuthenticationFlow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeycloakClient) DeepCopyObject() runtime.Object {
+func (in *KeycloakAuthenticationFlow) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -294,179 +733,834 @@ func (in *KeycloakClient) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakClientList) DeepCopyInto(out *KeycloakClientList) {
+func (in *KeycloakAuthenticationFlowDefinition) DeepCopyInto(out *KeycloakAuthenticationFlowDefinition) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]KeycloakClient, len(*in))
+	if in.Executions != nil {
+		in, out := &in.Executions, &out.Executions
+		*out = make([]KeycloakAuthenticationExecution, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientList.
-func (in *KeycloakClientList) DeepCopy() *KeycloakClientList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAuthenticationFlowDefinition.
+func (in *KeycloakAuthenticationFlowDefinition) DeepCopy() *KeycloakAuthenticationFlowDefinition {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakClientList)
+	out := new(KeycloakAuthenticationFlowDefinition)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeycloakClientList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakClientScope) DeepCopyInto(out *KeycloakClientScope) {
+func (in *KeycloakAuthenticationFlowList) DeepCopyInto(out *KeycloakAuthenticationFlowList) {
 	*out = *in
-	if in.Attributes != nil {
-		in, out := &in.Attributes, &out.Attributes
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ProtocolMappers != nil {
-		in, out := &in.ProtocolMappers, &out.ProtocolMappers
-		*out = make([]KeycloakProtocolMapper, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakAuthenticationFlow, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScope.
-func (in *KeycloakClientScope) DeepCopy() *KeycloakClientScope {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAuthenticationFlowList.
+func (in *KeycloakAuthenticationFlowList) DeepCopy() *KeycloakAuthenticationFlowList {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakClientScope)
+	out := new(KeycloakAuthenticationFlowList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakAuthenticationFlowList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakClientSpec) DeepCopyInto(out *KeycloakClientSpec) {
+func (in *KeycloakAuthenticationFlowSpec) DeepCopyInto(out *KeycloakAuthenticationFlowSpec) {
 	*out = *in
 	if in.RealmSelector != nil {
 		in, out := &in.RealmSelector, &out.RealmSelector
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Client != nil {
-		in, out := &in.Client, &out.Client
-		*out = new(KeycloakAPIClient)
+	if in.ClusterRealmSelector != nil {
+		in, out := &in.ClusterRealmSelector, &out.ClusterRealmSelector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]RoleRepresentation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ScopeMappings != nil {
-		in, out := &in.ScopeMappings, &out.ScopeMappings
-		*out = new(MappingsRepresentation)
+	if in.Flow != nil {
+		in, out := &in.Flow, &out.Flow
+		*out = new(KeycloakAuthenticationFlowDefinition)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ServiceAccountRealmRoles != nil {
-		in, out := &in.ServiceAccountRealmRoles, &out.ServiceAccountRealmRoles
-		*out = make([]string, len(*in))
+	if in.RealmBindings != nil {
+		in, out := &in.RealmBindings, &out.RealmBindings
+		*out = make([]AuthenticationFlowRealmBinding, len(*in))
 		copy(*out, *in)
 	}
-	if in.ServiceAccountClientRoles != nil {
-		in, out := &in.ServiceAccountClientRoles, &out.ServiceAccountClientRoles
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAuthenticationFlowSpec.
+func (in *KeycloakAuthenticationFlowSpec) DeepCopy() *KeycloakAuthenticationFlowSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(KeycloakAuthenticationFlowSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientSpec.
-func (in *KeycloakClientSpec) DeepCopy() *KeycloakClientSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAuthenticationFlowStatus) DeepCopyInto(out *KeycloakAuthenticationFlowStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAuthenticationFlowStatus.
+func (in *KeycloakAuthenticationFlowStatus) DeepCopy() *KeycloakAuthenticationFlowStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakClientSpec)
+	out := new(KeycloakAuthenticationFlowStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakClientStatus) DeepCopyInto(out *KeycloakClientStatus) {
+func (in *KeycloakAuthenticationSubFlow) DeepCopyInto(out *KeycloakAuthenticationSubFlow) {
 	*out = *in
-	if in.SecondaryResources != nil {
-		in, out := &in.SecondaryResources, &out.SecondaryResources
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
+	if in.Executions != nil {
+		in, out := &in.Executions, &out.Executions
+		*out = make([]KeycloakAuthenticationExecution, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientStatus.
-func (in *KeycloakClientStatus) DeepCopy() *KeycloakClientStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAuthenticationSubFlow.
+func (in *KeycloakAuthenticationSubFlow) DeepCopy() *KeycloakAuthenticationSubFlow {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakClientStatus)
+	out := new(KeycloakAuthenticationSubFlow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakCredential) DeepCopyInto(out *KeycloakCredential) {
+func (in *KeycloakCABundle) DeepCopyInto(out *KeycloakCABundle) {
 	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakCredential.
-func (in *KeycloakCredential) DeepCopy() *KeycloakCredential {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakCABundle.
+func (in *KeycloakCABundle) DeepCopy() *KeycloakCABundle {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakCredential)
+	out := new(KeycloakCABundle)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakExternal) DeepCopyInto(out *KeycloakExternal) {
+func (in *KeycloakClient) DeepCopyInto(out *KeycloakClient) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakExternal.
-func (in *KeycloakExternal) DeepCopy() *KeycloakExternal {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClient.
+func (in *KeycloakClient) DeepCopy() *KeycloakClient {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClient) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientList) DeepCopyInto(out *KeycloakClientList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakClient, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientList.
+func (in *KeycloakClientList) DeepCopy() *KeycloakClientList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClientList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientSAML) DeepCopyInto(out *KeycloakClientSAML) {
+	*out = *in
+	if in.ForceNameIDFormat != nil {
+		in, out := &in.ForceNameIDFormat, &out.ForceNameIDFormat
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SignDocuments != nil {
+		in, out := &in.SignDocuments, &out.SignDocuments
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SignAssertions != nil {
+		in, out := &in.SignAssertions, &out.SignAssertions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ClientSignatureRequired != nil {
+		in, out := &in.ClientSignatureRequired, &out.ClientSignatureRequired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EncryptAssertions != nil {
+		in, out := &in.EncryptAssertions, &out.EncryptAssertions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForcePostBinding != nil {
+		in, out := &in.ForcePostBinding, &out.ForcePostBinding
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServiceProviderMetadata != nil {
+		in, out := &in.ServiceProviderMetadata, &out.ServiceProviderMetadata
+		*out = new(SAMLMetadataSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientSAML.
+func (in *KeycloakClientSAML) DeepCopy() *KeycloakClientSAML {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientSAML)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientScope) DeepCopyInto(out *KeycloakClientScope) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScope.
+func (in *KeycloakClientScope) DeepCopy() *KeycloakClientScope {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClientScope) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientScopeList) DeepCopyInto(out *KeycloakClientScopeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakClientScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScopeList.
+func (in *KeycloakClientScopeList) DeepCopy() *KeycloakClientScopeList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientScopeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClientScopeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientScopeSpec) DeepCopyInto(out *KeycloakClientScopeSpec) {
+	*out = *in
+	if in.RealmSelector != nil {
+		in, out := &in.RealmSelector, &out.RealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterRealmSelector != nil {
+		in, out := &in.ClusterRealmSelector, &out.ClusterRealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientScope != nil {
+		in, out := &in.ClientScope, &out.ClientScope
+		*out = new(KeycloakAPIClientScope)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScopeMappings != nil {
+		in, out := &in.ScopeMappings, &out.ScopeMappings
+		*out = new(MappingsRepresentation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScopeSpec.
+func (in *KeycloakClientScopeSpec) DeepCopy() *KeycloakClientScopeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientScopeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientScopeStatus) DeepCopyInto(out *KeycloakClientScopeStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScopeStatus.
+func (in *KeycloakClientScopeStatus) DeepCopy() *KeycloakClientScopeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientScopeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientSpec) DeepCopyInto(out *KeycloakClientSpec) {
+	*out = *in
+	if in.RealmSelector != nil {
+		in, out := &in.RealmSelector, &out.RealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterRealmSelector != nil {
+		in, out := &in.ClusterRealmSelector, &out.ClusterRealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(ClientTemplateReference)
+		**out = **in
+	}
+	if in.Client != nil {
+		in, out := &in.Client, &out.Client
+		*out = new(KeycloakAPIClient)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RedirectURISources != nil {
+		in, out := &in.RedirectURISources, &out.RedirectURISources
+		*out = new(RedirectURISources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = new(KeycloakClientSAML)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientAuthenticationKey != nil {
+		in, out := &in.ClientAuthenticationKey, &out.ClientAuthenticationKey
+		*out = new(ClientAuthenticationKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleRepresentation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScopeMappings != nil {
+		in, out := &in.ScopeMappings, &out.ScopeMappings
+		*out = new(MappingsRepresentation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccountRealmRoles != nil {
+		in, out := &in.ServiceAccountRealmRoles, &out.ServiceAccountRealmRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountClientRoles != nil {
+		in, out := &in.ServiceAccountClientRoles, &out.ServiceAccountClientRoles
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientSpec.
+func (in *KeycloakClientSpec) DeepCopy() *KeycloakClientSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientStatus) DeepCopyInto(out *KeycloakClientStatus) {
+	*out = *in
+	if in.SecondaryResources != nil {
+		in, out := &in.SecondaryResources, &out.SecondaryResources
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientStatus.
+func (in *KeycloakClientStatus) DeepCopy() *KeycloakClientStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientTemplate) DeepCopyInto(out *KeycloakClientTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientTemplate.
+func (in *KeycloakClientTemplate) DeepCopy() *KeycloakClientTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClientTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientTemplateList) DeepCopyInto(out *KeycloakClientTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakClientTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientTemplateList.
+func (in *KeycloakClientTemplateList) DeepCopy() *KeycloakClientTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClientTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientTemplateSpec) DeepCopyInto(out *KeycloakClientTemplateSpec) {
+	*out = *in
+	if in.Client != nil {
+		in, out := &in.Client, &out.Client
+		*out = new(KeycloakAPIClient)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientTemplateSpec.
+func (in *KeycloakClientTemplateSpec) DeepCopy() *KeycloakClientTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakCredential) DeepCopyInto(out *KeycloakCredential) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakCredential.
+func (in *KeycloakCredential) DeepCopy() *KeycloakCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakExternal) DeepCopyInto(out *KeycloakExternal) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(KeycloakCABundle)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakExternal.
+func (in *KeycloakExternal) DeepCopy() *KeycloakExternal {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakExternal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakGroup) DeepCopyInto(out *KeycloakGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakGroup.
+func (in *KeycloakGroup) DeepCopy() *KeycloakGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakGroupList) DeepCopyInto(out *KeycloakGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakGroupList.
+func (in *KeycloakGroupList) DeepCopy() *KeycloakGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakGroupSpec) DeepCopyInto(out *KeycloakGroupSpec) {
+	*out = *in
+	if in.RealmSelector != nil {
+		in, out := &in.RealmSelector, &out.RealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterRealmSelector != nil {
+		in, out := &in.ClusterRealmSelector, &out.ClusterRealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Group != nil {
+		in, out := &in.Group, &out.Group
+		*out = new(KeycloakAPIGroup)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakGroupSpec.
+func (in *KeycloakGroupSpec) DeepCopy() *KeycloakGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakGroupStatus) DeepCopyInto(out *KeycloakGroupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakGroupStatus.
+func (in *KeycloakGroupStatus) DeepCopy() *KeycloakGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakIdentityProvider) DeepCopyInto(out *KeycloakIdentityProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakIdentityProvider.
+func (in *KeycloakIdentityProvider) DeepCopy() *KeycloakIdentityProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakIdentityProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakIdentityProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakIdentityProviderList) DeepCopyInto(out *KeycloakIdentityProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakIdentityProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakIdentityProviderList.
+func (in *KeycloakIdentityProviderList) DeepCopy() *KeycloakIdentityProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakIdentityProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakIdentityProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakIdentityProviderSpec) DeepCopyInto(out *KeycloakIdentityProviderSpec) {
+	*out = *in
+	if in.RealmSelector != nil {
+		in, out := &in.RealmSelector, &out.RealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterRealmSelector != nil {
+		in, out := &in.ClusterRealmSelector, &out.ClusterRealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdentityProvider != nil {
+		in, out := &in.IdentityProvider, &out.IdentityProvider
+		*out = new(KeycloakAPIIdentityProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientSecret != nil {
+		in, out := &in.ClientSecret, &out.ClientSecret
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mappers != nil {
+		in, out := &in.Mappers, &out.Mappers
+		*out = make([]KeycloakAPIIdentityProviderMapper, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakIdentityProviderSpec.
+func (in *KeycloakIdentityProviderSpec) DeepCopy() *KeycloakIdentityProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakIdentityProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakIdentityProviderStatus) DeepCopyInto(out *KeycloakIdentityProviderStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakIdentityProviderStatus.
+func (in *KeycloakIdentityProviderStatus) DeepCopy() *KeycloakIdentityProviderStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakExternal)
+	out := new(KeycloakIdentityProviderStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -635,6 +1729,110 @@ func (in *KeycloakRealmList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmRole) DeepCopyInto(out *KeycloakRealmRole) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmRole.
+func (in *KeycloakRealmRole) DeepCopy() *KeycloakRealmRole {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakRealmRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmRoleList) DeepCopyInto(out *KeycloakRealmRoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakRealmRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmRoleList.
+func (in *KeycloakRealmRoleList) DeepCopy() *KeycloakRealmRoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmRoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakRealmRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmRoleSpec) DeepCopyInto(out *KeycloakRealmRoleSpec) {
+	*out = *in
+	if in.RealmSelector != nil {
+		in, out := &in.RealmSelector, &out.RealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterRealmSelector != nil {
+		in, out := &in.ClusterRealmSelector, &out.ClusterRealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Role != nil {
+		in, out := &in.Role, &out.Role
+		*out = new(RoleRepresentation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmRoleSpec.
+func (in *KeycloakRealmRoleSpec) DeepCopy() *KeycloakRealmRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmRoleStatus) DeepCopyInto(out *KeycloakRealmRoleStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmRoleStatus.
+func (in *KeycloakRealmRoleStatus) DeepCopy() *KeycloakRealmRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakRealmSpec) DeepCopyInto(out *KeycloakRealmSpec) {
 	*out = *in
@@ -789,10 +1987,64 @@ func (in *KeycloakScope) DeepCopy() *KeycloakScope {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakServerInfo) DeepCopyInto(out *KeycloakServerInfo) {
+	*out = *in
+	if in.EnabledFeatures != nil {
+		in, out := &in.EnabledFeatures, &out.EnabledFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisabledFeatures != nil {
+		in, out := &in.DisabledFeatures, &out.DisabledFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProtocolMapperTypes != nil {
+		in, out := &in.ProtocolMapperTypes, &out.ProtocolMapperTypes
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Authenticators != nil {
+		in, out := &in.Authenticators, &out.Authenticators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientAuthenticators != nil {
+		in, out := &in.ClientAuthenticators, &out.ClientAuthenticators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakServerInfo.
+func (in *KeycloakServerInfo) DeepCopy() *KeycloakServerInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakServerInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakSpec) DeepCopyInto(out *KeycloakSpec) {
 	*out = *in
-	out.External = in.External
+	in.External.DeepCopyInto(&out.External)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakSpec.
@@ -823,6 +2075,22 @@ func (in *KeycloakStatus) DeepCopyInto(out *KeycloakStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastContactTime != nil {
+		in, out := &in.LastContactTime, &out.LastContactTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ServerInfo != nil {
+		in, out := &in.ServerInfo, &out.ServerInfo
+		*out = new(KeycloakServerInfo)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakStatus.
@@ -835,6 +2103,65 @@ func (in *KeycloakStatus) DeepCopy() *KeycloakStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakUser) DeepCopyInto(out *KeycloakUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakUser.
+func (in *KeycloakUser) DeepCopy() *KeycloakUser {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakUserList) DeepCopyInto(out *KeycloakUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakUserList.
+func (in *KeycloakUserList) DeepCopy() *KeycloakUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakUserRole) DeepCopyInto(out *KeycloakUserRole) {
 	*out = *in
@@ -850,6 +2177,56 @@ func (in *KeycloakUserRole) DeepCopy() *KeycloakUserRole {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakUserSpec) DeepCopyInto(out *KeycloakUserSpec) {
+	*out = *in
+	if in.RealmSelector != nil {
+		in, out := &in.RealmSelector, &out.RealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterRealmSelector != nil {
+		in, out := &in.ClusterRealmSelector, &out.ClusterRealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.User != nil {
+		in, out := &in.User, &out.User
+		*out = new(KeycloakAPIUser)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitialPasswordSecret != nil {
+		in, out := &in.InitialPasswordSecret, &out.InitialPasswordSecret
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakUserSpec.
+func (in *KeycloakUserSpec) DeepCopy() *KeycloakUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakUserStatus) DeepCopyInto(out *KeycloakUserStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakUserStatus.
+func (in *KeycloakUserStatus) DeepCopy() *KeycloakUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MappingsRepresentation) DeepCopyInto(out *MappingsRepresentation) {
 	*out = *in
@@ -879,6 +2256,60 @@ func (in *MappingsRepresentation) DeepCopy() *MappingsRepresentation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedirectURISourceReference) DeepCopyInto(out *RedirectURISourceReference) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedirectURISourceReference.
+func (in *RedirectURISourceReference) DeepCopy() *RedirectURISourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RedirectURISourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedirectURISources) DeepCopyInto(out *RedirectURISources) {
+	*out = *in
+	if in.Ingresses != nil {
+		in, out := &in.Ingresses, &out.Ingresses
+		*out = make([]RedirectURISourceReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HTTPRoutes != nil {
+		in, out := &in.HTTPRoutes, &out.HTTPRoutes
+		*out = make([]RedirectURISourceReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RedirectPaths != nil {
+		in, out := &in.RedirectPaths, &out.RedirectPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedirectURISources.
+func (in *RedirectURISources) DeepCopy() *RedirectURISources {
+	if in == nil {
+		return nil
+	}
+	out := new(RedirectURISources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleRepresentation) DeepCopyInto(out *RoleRepresentation) {
 	*out = *in
@@ -1019,6 +2450,26 @@ func (in *RolesRepresentation) DeepCopy() *RolesRepresentation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLMetadataSource) DeepCopyInto(out *SAMLMetadataSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLMetadataSource.
+func (in *SAMLMetadataSource) DeepCopy() *SAMLMetadataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLMetadataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScopeMappingRepresentation) DeepCopyInto(out *ScopeMappingRepresentation) {
 	*out = *in
@@ -0,0 +1,181 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	keycloakv1alpha1 "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+)
+
+// ClusterKeycloakReconciler reconciles a ClusterKeycloak object
+type ClusterKeycloakReconciler struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Namespace the admin credentials of all ClusterKeycloaks are stored in,
+	// usually the namespace the operator is running in.
+	OperatorNamespace string
+	context           context.Context
+	cancel            context.CancelFunc
+	recorder          record.EventRecorder
+}
+
+var logCkc = logf.Log.WithName("controller_clusterkeycloak")
+
+const (
+	ClusterKeycloakRequeueDelay   = 150 * time.Second
+	ClusterKeycloakControllerName = "clusterkeycloak-controller"
+)
+
+// blank assignment to verify that ClusterKeycloakReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ClusterKeycloakReconciler{}
+
+//+kubebuilder:rbac:groups=keycloak.org,resources=clusterkeycloaks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=keycloak.org,resources=clusterkeycloaks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=keycloak.org,resources=clusterkeycloaks/finalizers,verbs=update
+
+// Reconcile makes sure the admin credentials of a ClusterKeycloak exist in the operator namespace
+// and publishes where they are to be found in the status.
+func (r *ClusterKeycloakReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	_ = logf.FromContext(ctx)
+
+	reqLogger := logCkc.WithValues("Request.Name", request.Name)
+	reqLogger.Info("Reconciling ClusterKeycloak")
+
+	// Fetch the ClusterKeycloak instance
+	instance := &keycloakv1alpha1.ClusterKeycloak{}
+
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			deleted := &keycloakv1alpha1.ClusterKeycloak{ObjectMeta: metav1.ObjectMeta{Name: request.Name}}
+			common.Contacts.Forget(deleted.ToKeycloak(r.OperatorNamespace))
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+	currentState := common.NewClusterState()
+
+	if r.OperatorNamespace == "" {
+		return r.ManageError(ctx, instance, errors.Errorf("operator namespace is unknown, set OPERATOR_NAMESPACE to use cluster scoped keycloaks"))
+	}
+
+	if instance.Spec.Unmanaged {
+		return r.ManageSuccess(ctx, instance, currentState)
+	}
+
+	if instance.Spec.External.Enabled {
+		return r.ManageError(ctx, instance, common.PermanentErrorf("if external.enabled is true, unmanaged also needs to be true"))
+	}
+
+	// Read current state of the credentials in the operator namespace
+	keycloak := instance.ToKeycloak(r.OperatorNamespace)
+	err = currentState.Read(ctx, &keycloak, r.Client)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+
+	desiredState := r.ReconcileIt(currentState, &keycloak)
+
+	// Run the actions to reach the desired state, the cluster keycloak owns the admin secret
+	actionRunner := common.NewClusterActionRunner(ctx, r.Client, r.Scheme, instance)
+	err = actionRunner.RunAll(desiredState)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+
+	return r.ManageSuccess(ctx, instance, currentState)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterKeycloakReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	r.context = ctx
+	r.cancel = cancel
+	r.recorder = mgr.GetEventRecorderFor(ClusterKeycloakControllerName)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&keycloakv1alpha1.ClusterKeycloak{}).
+		WithOptions(controllerOptions()).
+		Owns(&corev1.Secret{}).
+		Complete(withErrorBackoff(mgr.GetClient(), &keycloakv1alpha1.ClusterKeycloak{}, withReconcileTimeout(r)))
+}
+
+func (r *ClusterKeycloakReconciler) ManageError(ctx context.Context, instance *keycloakv1alpha1.ClusterKeycloak, issue error) (reconcile.Result, error) {
+	r.recorder.Event(instance, "Warning", "ProcessingError", issue.Error())
+
+	instance.Status.Message = issue.Error()
+	instance.Status.Ready = false
+	instance.Status.Phase = keycloakv1alpha1.PhaseFailing
+	setKeycloakReachableCondition(&instance.Status, instance.ToKeycloak(r.OperatorNamespace), instance.Generation)
+
+	err := r.Client.Status().Update(ctx, instance)
+	if err != nil {
+		logCkc.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{}, issue
+}
+
+func (r *ClusterKeycloakReconciler) ManageSuccess(ctx context.Context, instance *keycloakv1alpha1.ClusterKeycloak, currentState *common.ClusterState) (reconcile.Result, error) {
+	instance.Status.Ready = true
+	instance.Status.Message = ""
+	instance.Status.Phase = keycloakv1alpha1.PhaseReconciling
+
+	if instance.Spec.External.URL != "" { //nolint
+		instance.Status.ExternalURL = instance.Spec.External.URL
+	}
+
+	// Let the clients know where the admin credentials are stored
+	if currentState.KeycloakAdminSecret != nil {
+		instance.Status.CredentialSecret = currentState.KeycloakAdminSecret.Name
+		instance.UpdateStatusSecondaryResources(currentState.KeycloakAdminSecret.Kind, currentState.KeycloakAdminSecret.Name)
+	}
+
+	// the url and the credentials of the status are used to log in
+	keycloak := instance.ToKeycloak(r.OperatorNamespace)
+	checkKeycloakReachable(ctx, keycloak)
+	setKeycloakReachableCondition(&instance.Status, keycloak, instance.Generation)
+	refreshServerInfo(ctx, &instance.Status, keycloak)
+
+	err := r.Client.Status().Update(ctx, instance)
+	if err != nil {
+		logCkc.Error(err, "unable to update status")
+		return reconcile.Result{}, err
+	}
+
+	logCkc.Info("desired cluster state met")
+	return reconcile.Result{RequeueAfter: ClusterKeycloakRequeueDelay}, nil
+}
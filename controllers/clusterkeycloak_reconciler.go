@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	keycloakv1alpha1 "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+)
+
+// ReconcileIt works on the namespaced view of the ClusterKeycloak, i.e. the admin secret
+// is managed in the namespace of the operator.
+func (i *ClusterKeycloakReconciler) ReconcileIt(clusterState *common.ClusterState, keycloak *keycloakv1alpha1.Keycloak) common.DesiredClusterState {
+	desired := common.DesiredClusterState{}
+
+	desired = desired.AddAction(getKeycloakAdminSecretDesiredState(clusterState, keycloak))
+
+	return desired
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterKeycloakReconciler_Test_Creates_Admin_Secret_In_Operator_Namespace(t *testing.T) {
+	// given
+	cr := &v1alpha1.ClusterKeycloak{
+		ObjectMeta: metav1.ObjectMeta{Name: "sso"},
+	}
+	keycloak := cr.ToKeycloak("operator")
+	reconciler := &ClusterKeycloakReconciler{OperatorNamespace: "operator"}
+
+	// when
+	desiredState := reconciler.ReconcileIt(common.NewClusterState(), &keycloak)
+
+	// then
+	assert.Len(t, desiredState, 1)
+	action, ok := desiredState[0].(common.GenericCreateAction)
+	assert.True(t, ok)
+	assert.Equal(t, "operator", action.Ref.GetNamespace())
+	assert.Equal(t, "credential-sso", action.Ref.GetName())
+}
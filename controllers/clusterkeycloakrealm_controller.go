@@ -0,0 +1,218 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/pkg/errors"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+)
+
+// ClusterKeycloakRealmReconciler reconciles a ClusterKeycloakRealm object
+type ClusterKeycloakRealmReconciler struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Namespace the admin credentials of all ClusterKeycloaks are stored in
+	OperatorNamespace string
+	context           context.Context
+	cancel            context.CancelFunc
+	recorder          record.EventRecorder
+}
+
+const (
+	ClusterRealmControllerName = "controller_clusterkeycloakrealm"
+)
+
+var logCkcr = logf.Log.WithName(ClusterRealmControllerName)
+
+// blank assignment to verify that ClusterKeycloakRealmReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ClusterKeycloakRealmReconciler{}
+
+//+kubebuilder:rbac:groups=keycloak.org,resources=clusterkeycloakrealms,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=keycloak.org,resources=clusterkeycloakrealms/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=keycloak.org,resources=clusterkeycloakrealms/finalizers,verbs=update
+
+// Reconcile checks that the ClusterKeycloaks selected by the ClusterKeycloakRealm are available.
+func (r *ClusterKeycloakRealmReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	_ = logf.FromContext(ctx)
+	reqLogger := logCkcr.WithValues("Request.Name", request.Name)
+	reqLogger.Info("Reconciling ClusterKeycloakRealm")
+
+	// Fetch the ClusterKeycloakRealm instance
+	instance := &kc.ClusterKeycloakRealm{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if instance.Spec.Unmanaged {
+		return reconcile.Result{Requeue: false}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+	}
+
+	// If no selector is set we can't figure out which ClusterKeycloak instance this realm should
+	// be added to. Skip reconcile until a selector has been set.
+	if instance.Spec.InstanceSelector == nil {
+		logCkcr.Info(fmt.Sprintf("cluster realm %v has no instance selector and will be ignored", instance.Name))
+		return reconcile.Result{Requeue: false}, nil
+	}
+
+	if r.OperatorNamespace == "" {
+		return r.ManageError(ctx, instance, errors.Errorf("operator namespace is unknown, set OPERATOR_NAMESPACE to use cluster scoped keycloaks"))
+	}
+
+	clusterKeycloaks, err := common.GetMatchingClusterKeycloaks(ctx, r.Client, instance.Spec.InstanceSelector)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+
+	logCkcr.Info(fmt.Sprintf("found %v matching cluster keycloak(s) for cluster realm %v", len(clusterKeycloaks.Items), instance.Name))
+
+	realm := instance.ToKeycloakRealm()
+	for _, clusterKeycloak := range clusterKeycloaks.Items {
+		if clusterKeycloak.Spec.Unmanaged {
+			return r.ManageError(ctx, instance, common.PermanentErrorf("realms cannot be created for unmanaged keycloak instances"))
+		}
+
+		// Get an authenticated keycloak api client for the instance
+		keycloak := clusterKeycloak.ToKeycloak(r.OperatorNamespace)
+		keycloakFactory := common.LocalConfigKeycloakFactory{}
+		authenticated, err := keycloakFactory.AuthenticatedClient(ctx, keycloak, false)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+
+		// Compute the current state of the realm
+		realmState := common.NewRealmState(ctx, keycloak)
+
+		logCkcr.Info(fmt.Sprintf("read state for cluster keycloak %v, cluster realm %v",
+			clusterKeycloak.Name,
+			instance.Spec.Realm.Realm))
+
+		err = realmState.Read(ctx, &realm, authenticated, r.Client)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+
+		// Figure out the actions to keep the realms up to date with
+		// the desired state
+		reconciler := NewDedicatedKeycloakRealmReconciler(keycloak)
+		desiredState := reconciler.Reconcile(realmState, &realm)
+		actionRunner := common.NewClusterAndKeycloakActionRunner(ctx, r.Client, r.Scheme, instance, authenticated)
+
+		// Run all actions to keep the realms updated
+		err = actionRunner.RunAll(desiredState)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+	}
+
+	return reconcile.Result{Requeue: false}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterKeycloakRealmReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	r.context = ctx
+	r.cancel = cancel
+	r.recorder = mgr.GetEventRecorderFor(ClusterRealmControllerName)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kc.ClusterKeycloakRealm{}).
+		WithOptions(controllerOptions()).
+		Complete(withErrorBackoff(mgr.GetClient(), &kc.ClusterKeycloakRealm{}, withReconcileTimeout(r)))
+}
+
+func (r *ClusterKeycloakRealmReconciler) manageSuccess(ctx context.Context, realm *kc.ClusterKeycloakRealm, deleted bool) error {
+	realm.Status.Ready = true
+	realm.Status.Message = ""
+	realm.Status.Phase = kc.PhaseReconciling
+
+	err := r.Client.Status().Update(ctx, realm)
+	if err != nil {
+		logCkcr.Error(err, "unable to update status")
+	}
+
+	// Finalizer already set?
+	finalizerExists := false
+	for _, finalizer := range realm.Finalizers {
+		if finalizer == RealmFinalizer {
+			finalizerExists = true
+			break
+		}
+	}
+
+	// Resource created and finalizer exists: nothing to do
+	if !deleted && finalizerExists {
+		return nil
+	}
+
+	// Resource created and finalizer does not exist: add finalizer
+	if !deleted && !finalizerExists {
+		realm.Finalizers = append(realm.Finalizers, RealmFinalizer)
+		logCkcr.Info(fmt.Sprintf("added finalizer to cluster keycloak realm %v", realm.Spec.Realm.Realm))
+
+		return r.Client.Update(ctx, realm)
+	}
+
+	// Otherwise remove the finalizer
+	newFinalizers := []string{}
+	for _, finalizer := range realm.Finalizers {
+		if finalizer == RealmFinalizer {
+			logCkcr.Info(fmt.Sprintf("removed finalizer from cluster keycloak realm %v", realm.Spec.Realm.Realm))
+
+			continue
+		}
+		newFinalizers = append(newFinalizers, finalizer)
+	}
+
+	realm.Finalizers = newFinalizers
+	return r.Client.Update(ctx, realm)
+}
+
+func (r *ClusterKeycloakRealmReconciler) ManageError(ctx context.Context, realm *kc.ClusterKeycloakRealm, issue error) (reconcile.Result, error) {
+	r.recorder.Event(realm, "Warning", "ProcessingError", issue.Error())
+
+	realm.Status.Message = issue.Error()
+	realm.Status.Ready = false
+	realm.Status.Phase = kc.PhaseFailing
+
+	err := r.Client.Status().Update(ctx, realm)
+	if err != nil {
+		logCkcr.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{}, issue
+}
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// MaxConcurrentReconciles is the number of resources of a kind reconciled at the same time. The requests to each
+// Keycloak are limited separately, see common.RequestOptions.
+var MaxConcurrentReconciles = 1
+
+// controllerOptions are the options of all controllers, failed reconciliations are retried with a per-object
+// exponential backoff
+func controllerOptions() controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: MaxConcurrentReconciles,
+		RateLimiter:             workqueue.NewItemExponentialFailureRateLimiter(ErrorBackoffBaseDelay, ErrorBackoffMaxDelay),
+	}
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var logBackoff = logf.Log.WithName("error_backoff")
+
+// ErrorBackoffBaseDelay is the delay before a resource that failed with a transient error is reconciled again, it
+// doubles with every failure in a row up to ErrorBackoffMaxDelay
+var (
+	ErrorBackoffBaseDelay = time.Second
+	ErrorBackoffMaxDelay  = 5 * time.Minute
+)
+
+// errorBackoffReconciler keeps resources that failed with a permanent error from being reconciled again until their
+// spec changes. Transient errors are returned to controller-runtime, which requeues the resource with backoff.
+type errorBackoffReconciler struct {
+	client     client.Client
+	object     client.Object
+	reconciler reconcile.Reconciler
+
+	mutex sync.Mutex
+	// failed are the generations of the resources that failed with a permanent error
+	failed map[types.NamespacedName]int64
+}
+
+// withErrorBackoff wraps the reconciler of the resources of the kind of the object
+func withErrorBackoff(c client.Client, object client.Object, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return &errorBackoffReconciler{
+		client:     c,
+		object:     object,
+		reconciler: reconciler,
+		failed:     map[types.NamespacedName]int64{},
+	}
+}
+
+func (r *errorBackoffReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	obj := r.object.DeepCopyObject().(client.Object)
+	err := r.client.Get(ctx, request.NamespacedName, obj)
+	if err != nil && !kubeerrors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	found := err == nil
+	if found && obj.GetDeletionTimestamp() == nil && r.failedPermanently(request.NamespacedName, obj.GetGeneration()) {
+		logBackoff.V(1).Info("skipping resource that failed with a permanent error until its spec changes",
+			"Request.Namespace", request.Namespace, "Request.Name", request.Name, "generation", obj.GetGeneration())
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconciler.Reconcile(ctx, request)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.failed, request.NamespacedName)
+	if err != nil && common.IsPermanent(err) {
+		if found {
+			r.failed[request.NamespacedName] = obj.GetGeneration()
+		}
+		logBackoff.Info("resource failed with a permanent error, it is reconciled again when its spec changes",
+			"Request.Namespace", request.Namespace, "Request.Name", request.Name, "error", err.Error())
+		return reconcile.Result{}, nil
+	}
+	return result, err
+}
+
+func (r *errorBackoffReconciler) failedPermanently(name types.NamespacedName, generation int64) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	failed, ok := r.failed[name]
+	return ok && failed == generation
+}
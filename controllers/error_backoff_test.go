@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// clientGetter returns a copy of the client for every Get
+type clientGetter struct {
+	client.Client
+	keycloakClient *v1alpha1.KeycloakClient
+}
+
+func (g *clientGetter) Get(ctx context.Context, key types.NamespacedName, obj client.Object) error {
+	*obj.(*v1alpha1.KeycloakClient) = *g.keycloakClient.DeepCopy()
+	return nil
+}
+
+// failingReconciler fails every reconciliation with its error
+type failingReconciler struct {
+	err        error
+	reconciled int
+}
+
+func (r *failingReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	r.reconciled++
+	return reconcile.Result{}, r.err
+}
+
+func TestErrorBackoff_Permanent_Error(t *testing.T) {
+	// given
+	keycloakClient := &v1alpha1.KeycloakClient{ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "orders", Generation: 1}}
+	failing := &failingReconciler{err: errors.Wrap(common.PermanentErrorf("either realmSelector or clusterRealmSelector needs to be set"), "cannot reconcile")}
+	reconciler := withErrorBackoff(&clientGetter{keycloakClient: keycloakClient}, &v1alpha1.KeycloakClient{}, failing)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "my-app", Name: "orders"}}
+
+	// when
+	_, firstErr := reconciler.Reconcile(context.Background(), request)
+	_, secondErr := reconciler.Reconcile(context.Background(), request)
+	reconciledBeforeChange := failing.reconciled
+	keycloakClient.Generation = 2
+	_, changedErr := reconciler.Reconcile(context.Background(), request)
+
+	// then
+	// the error isn't returned, the resource would be requeued with backoff otherwise
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.NoError(t, changedErr)
+	// the resource isn't reconciled again until its spec changes
+	assert.Equal(t, 1, reconciledBeforeChange)
+	assert.Equal(t, 2, failing.reconciled)
+}
+
+func TestErrorBackoff_Transient_Error(t *testing.T) {
+	// given
+	keycloakClient := &v1alpha1.KeycloakClient{ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "orders", Generation: 1}}
+	failing := &failingReconciler{err: &common.APIError{Message: "failed to GET client", StatusCode: 502, Status: "502 Bad Gateway"}}
+	reconciler := withErrorBackoff(&clientGetter{keycloakClient: keycloakClient}, &v1alpha1.KeycloakClient{}, failing)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "my-app", Name: "orders"}}
+
+	// when
+	_, firstErr := reconciler.Reconcile(context.Background(), request)
+	_, secondErr := reconciler.Reconcile(context.Background(), request)
+
+	// then
+	// controller-runtime requeues the resource with backoff
+	assert.Error(t, firstErr)
+	assert.Error(t, secondErr)
+	assert.Equal(t, 2, failing.reconciled)
+}
@@ -21,17 +21,13 @@ import (
 	"time"
 
 	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	"github.com/christianwoehrle/keycloakclient-controller/version"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -67,22 +63,6 @@ type KeycloakReconciler struct {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *KeycloakReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// Create a new controller
-	c, err := controller.New(KeycloakControllerName, mgr, controller.Options{Reconciler: r})
-	if err != nil {
-		return err
-	}
-
-	// Watch for changes to primary resource Keycloak
-	err = c.Watch(&source.Kind{Type: &keycloakv1alpha1.Keycloak{}}, &handler.EnqueueRequestForObject{})
-	if err != nil {
-		return err
-	}
-
-	if err := common.WatchSecondaryResource(c, KeycloakControllerName, common.SecretKind, &corev1.Secret{}, &keycloakv1alpha1.Keycloak{}); err != nil {
-		return err
-	}
-
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	//client := mgr.GetClient()
@@ -92,16 +72,17 @@ func (r *KeycloakReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&keycloakv1alpha1.Keycloak{}).
+		WithOptions(controllerOptions()).
 		Owns(&keycloakv1alpha1.KeycloakRealm{}).
-		Complete(r)
+		Owns(&corev1.Secret{}).
+		Complete(withErrorBackoff(mgr.GetClient(), &keycloakv1alpha1.Keycloak{}, withReconcileTimeout(r)))
 }
 
 var logKc = logf.Log.WithName("controller_keycloak")
 
 const (
-	KeycloakRequeueDelay      = 150 * time.Second
-	KeycloakRequeueDelayError = 60 * time.Second
-	KeycloakControllerName    = "keycloak-controller"
+	KeycloakRequeueDelay   = 150 * time.Second
+	KeycloakControllerName = "keycloak-controller"
 )
 
 // newReconciler returns a new reconcile.Reconciler
@@ -131,12 +112,13 @@ func (r *KeycloakReconciler) Reconcile(ctx context.Context, request reconcile.Re
 	// Fetch the Keycloak instance
 	instance := &keycloakv1alpha1.Keycloak{}
 
-	err := r.Client.Get(r.context, request.NamespacedName, instance)
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
 	if err != nil {
 		if kubeerrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			common.Contacts.Forget(keycloakv1alpha1.Keycloak{ObjectMeta: metav1.ObjectMeta{Namespace: request.Namespace, Name: request.Name}})
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -145,17 +127,17 @@ func (r *KeycloakReconciler) Reconcile(ctx context.Context, request reconcile.Re
 	currentState := common.NewClusterState()
 
 	if instance.Spec.Unmanaged {
-		return r.ManageSuccess(instance, currentState)
+		return r.ManageSuccess(ctx, instance, currentState)
 	}
 
 	if instance.Spec.External.Enabled {
-		return r.ManageError(instance, errors.Errorf("if external.enabled is true, unmanaged also needs to be true"))
+		return r.ManageError(ctx, instance, common.PermanentErrorf("if external.enabled is true, unmanaged also needs to be true"))
 	}
 
 	// Read current state
-	err = currentState.Read(r.context, instance, r.Client)
+	err = currentState.Read(ctx, instance, r.Client)
 	if err != nil {
-		return r.ManageError(instance, err)
+		return r.ManageError(ctx, instance, err)
 	}
 
 	// Get Action to reconcile current state into desired state
@@ -163,40 +145,36 @@ func (r *KeycloakReconciler) Reconcile(ctx context.Context, request reconcile.Re
 	desiredState := r.ReconcileIt(currentState, instance)
 
 	// Run the actions to reach the desired state
-	actionRunner := common.NewClusterActionRunner(r.context, r.Client, r.Scheme, instance)
+	actionRunner := common.NewClusterActionRunner(ctx, r.Client, r.Scheme, instance)
 	err = actionRunner.RunAll(desiredState)
 	if err != nil {
-		return r.ManageError(instance, err)
+		return r.ManageError(ctx, instance, err)
 	}
 
-	return r.ManageSuccess(instance, currentState)
+	return r.ManageSuccess(ctx, instance, currentState)
 }
 
-func (r *KeycloakReconciler) ManageError(instance *keycloakv1alpha1.Keycloak, issue error) (reconcile.Result, error) {
+func (r *KeycloakReconciler) ManageError(ctx context.Context, instance *keycloakv1alpha1.Keycloak, issue error) (reconcile.Result, error) {
 	r.recorder.Event(instance, "Warning", "ProcessingError", issue.Error())
 
 	instance.Status.Message = issue.Error()
 	instance.Status.Ready = false
 	instance.Status.Phase = keycloakv1alpha1.PhaseFailing
+	setKeycloakReachableCondition(&instance.Status, *instance, instance.Generation)
 
-	r.setVersion(instance)
-
-	err := r.Client.Status().Update(r.context, instance)
+	err := r.Client.Status().Update(ctx, instance)
 	if err != nil {
 		logKc.Error(err, "unable to update status")
 	}
 
-	return reconcile.Result{
-		RequeueAfter: KeycloakRequeueDelayError,
-		Requeue:      true,
-	}, nil
+	return reconcile.Result{}, issue
 }
 
-func (r *KeycloakReconciler) ManageSuccess(instance *keycloakv1alpha1.Keycloak, currentState *common.ClusterState) (reconcile.Result, error) {
+func (r *KeycloakReconciler) ManageSuccess(ctx context.Context, instance *keycloakv1alpha1.Keycloak, currentState *common.ClusterState) (reconcile.Result, error) {
 	// Check if the resources are ready
 	resourcesReady, err := currentState.IsResourcesReady(instance)
 	if err != nil {
-		return r.ManageError(instance, err)
+		return r.ManageError(ctx, instance, err)
 	}
 
 	instance.Status.Ready = resourcesReady
@@ -218,21 +196,17 @@ func (r *KeycloakReconciler) ManageSuccess(instance *keycloakv1alpha1.Keycloak,
 		instance.Status.CredentialSecret = currentState.KeycloakAdminSecret.Name
 	}
 
-	r.setVersion(instance)
+	// the url and the credentials of the status are used to log in
+	checkKeycloakReachable(ctx, *instance)
+	setKeycloakReachableCondition(&instance.Status, *instance, instance.Generation)
+	refreshServerInfo(ctx, &instance.Status, *instance)
 
-	err = r.Client.Status().Update(r.context, instance)
+	err = r.Client.Status().Update(ctx, instance)
 	if err != nil {
 		logKc.Error(err, "unable to update status")
-		return reconcile.Result{
-			RequeueAfter: KeycloakRequeueDelayError,
-			Requeue:      true,
-		}, nil
+		return reconcile.Result{}, err
 	}
 
 	logKc.Info("desired cluster state met")
 	return reconcile.Result{RequeueAfter: KeycloakRequeueDelay}, nil
 }
-
-func (r *KeycloakReconciler) setVersion(instance *keycloakv1alpha1.Keycloak) {
-	instance.Status.Version = version.Version
-}
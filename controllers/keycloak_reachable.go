@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeycloakContactMaxAge is the time after which the keycloak controllers log in to a Keycloak themselves to find out
+// whether it is reachable, unless another controller logged in to it in the meantime
+var KeycloakContactMaxAge = 2 * time.Minute
+
+// checkKeycloakReachable logs in to the keycloak unless the last login is more recent than KeycloakContactMaxAge, the
+// result is kept in common.Contacts
+func checkKeycloakReachable(ctx context.Context, keycloak kc.Keycloak) {
+	contact, ok := common.Contacts.Get(keycloak)
+	if ok && time.Since(contact.LastAttempt) < KeycloakContactMaxAge {
+		return
+	}
+	factory := common.LocalConfigKeycloakFactory{}
+	_, _ = factory.AuthenticatedClient(ctx, keycloak, false)
+}
+
+// setKeycloakReachableCondition sets the KeycloakReachable condition and the time of the last contact from the last
+// login to the keycloak, the status is left as it is when the keycloak wasn't logged in to yet. The time of the last
+// contact is updated once per KeycloakContactMaxAge, as every status update reconciles the keycloak again.
+func setKeycloakReachableCondition(status *kc.KeycloakStatus, keycloak kc.Keycloak, generation int64) {
+	contact, ok := common.Contacts.Get(keycloak)
+	if !ok {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               kc.ConditionKeycloakReachable,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             kc.ReasonLoginSucceeded,
+		Message:            "the controller logged in to the keycloak admin api",
+	}
+	if !contact.Reachable {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = kc.ReasonLoginFailed
+		condition.Message = contact.Error
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	if contact.LastSuccess.IsZero() {
+		return
+	}
+	if status.LastContactTime == nil || contact.LastSuccess.Sub(status.LastContactTime.Time) >= KeycloakContactMaxAge {
+		lastContact := metav1.NewTime(contact.LastSuccess)
+		status.LastContactTime = &lastContact
+	}
+}
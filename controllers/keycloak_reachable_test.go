@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKeycloakReachable_Condition(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{ObjectMeta: v1.ObjectMeta{Namespace: "reachable", Name: "keycloak", Generation: 3}}
+	unknown := v1alpha1.Keycloak{ObjectMeta: v1.ObjectMeta{Namespace: "reachable", Name: "unknown"}}
+
+	// when
+	unknownStatus := v1alpha1.KeycloakStatus{}
+	setKeycloakReachableCondition(&unknownStatus, unknown, 1)
+	common.Contacts.Record(keycloak, nil)
+	setKeycloakReachableCondition(&keycloak.Status, keycloak, keycloak.Generation)
+	reachable := *meta.FindStatusCondition(keycloak.Status.Conditions, v1alpha1.ConditionKeycloakReachable)
+	lastContact := keycloak.Status.LastContactTime
+	common.Contacts.Record(keycloak, errors.New("invalid_grant"))
+	setKeycloakReachableCondition(&keycloak.Status, keycloak, keycloak.Generation)
+	unreachable := *meta.FindStatusCondition(keycloak.Status.Conditions, v1alpha1.ConditionKeycloakReachable)
+
+	// then
+	// keycloaks that weren't logged in to yet keep their status
+	assert.Empty(t, unknownStatus.Conditions)
+	assert.Nil(t, unknownStatus.LastContactTime)
+
+	assert.Equal(t, v1.ConditionTrue, reachable.Status)
+	assert.Equal(t, v1alpha1.ReasonLoginSucceeded, reachable.Reason)
+	assert.Equal(t, int64(3), reachable.ObservedGeneration)
+	assert.NotNil(t, lastContact)
+
+	assert.Equal(t, v1.ConditionFalse, unreachable.Status)
+	assert.Equal(t, v1alpha1.ReasonLoginFailed, unreachable.Reason)
+	assert.Equal(t, "invalid_grant", unreachable.Message)
+	// the last successful contact is kept
+	assert.Equal(t, lastContact, keycloak.Status.LastContactTime)
+}
@@ -19,6 +19,10 @@ func (i *KeycloakReconciler) ReconcileIt(clusterState *common.ClusterState, cr *
 }
 
 func (i *KeycloakReconciler) GetKeycloakAdminSecretDesiredState(clusterState *common.ClusterState, cr *keycloakv1alpha1.Keycloak) common.ClusterAction {
+	return getKeycloakAdminSecretDesiredState(clusterState, cr)
+}
+
+func getKeycloakAdminSecretDesiredState(clusterState *common.ClusterState, cr *keycloakv1alpha1.Keycloak) common.ClusterAction {
 	keycloakAdminSecret := model.KeycloakAdminSecret(cr)
 
 	if clusterState.KeycloakAdminSecret == nil {
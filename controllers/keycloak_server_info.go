@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeycloakServerInfoMaxAge is the time after which the keycloak controllers query the server info of a Keycloak again,
+// e.g. to notice upgrades or features that were enabled
+var KeycloakServerInfoMaxAge = 10 * time.Minute
+
+// refreshServerInfo queries the version and the capabilities of the keycloak unless the status holds ones that are
+// more recent than KeycloakServerInfoMaxAge. The status is left as it is when the keycloak can't be queried, its
+// KeycloakReachable condition tells why.
+func refreshServerInfo(ctx context.Context, status *kc.KeycloakStatus, keycloak kc.Keycloak) {
+	now := time.Now()
+	if !serverInfoOutdated(status, now) {
+		return
+	}
+	// the keycloak isn't logged in to again right after a failed login
+	if contact, ok := common.Contacts.Get(keycloak); ok && !contact.Reachable {
+		return
+	}
+
+	factory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := factory.AuthenticatedClient(ctx, keycloak, false)
+	if err != nil {
+		logKc.Info("cannot query the server info", "keycloak", keycloak.Namespace+"/"+keycloak.Name, "error", err.Error())
+		return
+	}
+	info, err := authenticated.GetServerInfo(ctx)
+	if err != nil {
+		logKc.Info("cannot query the server info", "keycloak", keycloak.Namespace+"/"+keycloak.Name, "error", err.Error())
+		return
+	}
+	setServerInfo(status, info, now)
+}
+
+func serverInfoOutdated(status *kc.KeycloakStatus, now time.Time) bool {
+	return status.ServerInfo == nil || status.ServerInfo.LastUpdateTime == nil ||
+		now.Sub(status.ServerInfo.LastUpdateTime.Time) >= KeycloakServerInfoMaxAge
+}
+
+// setServerInfo sets the version and the capabilities of the keycloak queried at the given time
+func setServerInfo(status *kc.KeycloakStatus, info *common.ServerInfo, now time.Time) {
+	lastUpdate := metav1.NewTime(now)
+	capabilities := info.Capabilities.DeepCopy()
+	capabilities.LastUpdateTime = &lastUpdate
+	status.Version = info.Version
+	status.ServerInfo = capabilities
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeycloakServerInfo_SetServerInfo(t *testing.T) {
+	// given
+	status := v1alpha1.KeycloakStatus{}
+	info := &common.ServerInfo{
+		Version: "24.0.5",
+		Capabilities: v1alpha1.KeycloakServerInfo{
+			EnabledFeatures:      []string{"authorization"},
+			ClientAuthenticators: []string{"client-jwt", "client-secret"},
+		},
+	}
+	now := time.Now()
+
+	// when
+	outdatedBefore := serverInfoOutdated(&status, now)
+	setServerInfo(&status, info, now)
+	outdatedAfter := serverInfoOutdated(&status, now.Add(time.Minute))
+	outdatedLater := serverInfoOutdated(&status, now.Add(KeycloakServerInfoMaxAge))
+
+	// then
+	// the server info is queried again once it is older than KeycloakServerInfoMaxAge
+	assert.True(t, outdatedBefore)
+	assert.Equal(t, "24.0.5", status.Version)
+	assert.Equal(t, []string{"authorization"}, status.ServerInfo.EnabledFeatures)
+	assert.Equal(t, []string{"client-jwt", "client-secret"}, status.ServerInfo.ClientAuthenticators)
+	assert.Equal(t, now.Unix(), status.ServerInfo.LastUpdateTime.Unix())
+	assert.Nil(t, info.Capabilities.LastUpdateTime)
+	assert.False(t, outdatedAfter)
+	assert.True(t, outdatedLater)
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+)
+
+// KeycloakAuthenticationFlowReconciler reconciles a KeycloakAuthenticationFlow object
+type KeycloakAuthenticationFlowReconciler struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Namespace the admin credentials of all ClusterKeycloaks are stored in
+	OperatorNamespace string
+	context           context.Context
+	cancel            context.CancelFunc
+	recorder          record.EventRecorder
+}
+
+var logKcaf = logf.Log.WithName("controller_keycloakauthenticationflow")
+
+const (
+	AuthenticationFlowFinalizer      = "authenticationflow.cleanup"
+	AuthenticationFlowControllerName = "keycloakauthenticationflow-controller"
+)
+
+// blank assignment to verify that KeycloakAuthenticationFlowReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &KeycloakAuthenticationFlowReconciler{}
+
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakauthenticationflows,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakauthenticationflows/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakauthenticationflows/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes the authentication flow in all realms selected by the KeycloakAuthenticationFlow.
+func (r *KeycloakAuthenticationFlowReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	_ = logf.FromContext(ctx)
+
+	reqLogger := logKcaf.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling KeycloakAuthenticationFlow")
+
+	// Fetch the KeycloakAuthenticationFlow instance
+	instance := &kc.KeycloakAuthenticationFlow{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// The authentication flow may be applicable to multiple keycloak instances,
+	// process all of them
+	instances, err := common.GetMatchingRealmInstances(ctx, r.Client, r.OperatorNamespace, instance.Spec.RealmSelector, instance.Spec.ClusterRealmSelector)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+	logKcaf.Info(fmt.Sprintf("found %v matching realm(s) for authentication flow %v/%v", len(instances), instance.Namespace, instance.Name))
+
+	for _, realmInstance := range instances {
+		err = r.reconcileAuthenticationFlowInRealm(ctx, instance, realmInstance.Realm, realmInstance.Keycloak)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+	}
+
+	return reconcile.Result{Requeue: false}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+}
+
+// reconcileAuthenticationFlowInRealm brings the authentication flow in the given realm of the given keycloak to the desired state
+func (r *KeycloakAuthenticationFlowReconciler) reconcileAuthenticationFlowInRealm(ctx context.Context, instance *kc.KeycloakAuthenticationFlow, realm kc.KeycloakRealm, keycloak kc.Keycloak) error {
+	// Get an authenticated keycloak api client for the instance
+	keycloakFactory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := keycloakFactory.AuthenticatedClient(ctx, keycloak, false)
+	if err != nil {
+		return err
+	}
+
+	// Compute the current state of the authentication flow
+	authenticationFlowState := common.NewAuthenticationFlowState(ctx, realm.DeepCopy(), keycloak)
+
+	logKcaf.Info(fmt.Sprintf("read authentication flow state for keycloak %v/%v, realm %v/%v, authentication flow %v/%v",
+		keycloak.Namespace,
+		keycloak.Name,
+		realm.Namespace,
+		realm.Name,
+		instance.Namespace,
+		instance.Name))
+
+	err = authenticationFlowState.Read(ctx, instance, authenticated, r.Client)
+	if err != nil {
+		return err
+	}
+
+	// Figure out the actions to keep the authentication flow up to date with
+	// the desired state
+	reconciler := NewDedicatedKeycloakAuthenticationFlowReconciler(keycloak)
+	desiredState := reconciler.ReconcileIt(authenticationFlowState, instance)
+	actionRunner := common.NewClusterAndKeycloakActionRunner(ctx, r.Client, r.Scheme, instance, authenticated)
+
+	// Run all actions to keep the authentication flow updated
+	return actionRunner.RunAll(desiredState)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeycloakAuthenticationFlowReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	r.context = ctx
+	r.cancel = cancel
+	r.recorder = mgr.GetEventRecorderFor(AuthenticationFlowControllerName)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kc.KeycloakAuthenticationFlow{}).
+		WithOptions(controllerOptions()).
+		Complete(withErrorBackoff(mgr.GetClient(), &kc.KeycloakAuthenticationFlow{}, withReconcileTimeout(r)))
+}
+
+func (r *KeycloakAuthenticationFlowReconciler) manageSuccess(ctx context.Context, authenticationFlow *kc.KeycloakAuthenticationFlow, deleted bool) error {
+	authenticationFlow.Status.Ready = true
+	authenticationFlow.Status.Message = ""
+	authenticationFlow.Status.Phase = kc.PhaseReconciling
+
+	err := r.Client.Status().Update(ctx, authenticationFlow)
+	if err != nil {
+		logKcaf.Error(err, "unable to update status")
+	}
+
+	// Finalizer already set?
+	finalizerExists := false
+	for _, finalizer := range authenticationFlow.Finalizers {
+		if finalizer == AuthenticationFlowFinalizer {
+			finalizerExists = true
+			break
+		}
+	}
+
+	// Resource created and finalizer exists: nothing to do
+	if !deleted && finalizerExists {
+		return nil
+	}
+
+	// Resource created and finalizer does not exist: add finalizer
+	if !deleted && !finalizerExists {
+		authenticationFlow.Finalizers = append(authenticationFlow.Finalizers, AuthenticationFlowFinalizer)
+		logKcaf.Info(fmt.Sprintf("added finalizer to keycloak authentication flow %v/%v",
+			authenticationFlow.Namespace,
+			authenticationFlow.Spec.Flow.Alias))
+
+		return r.Client.Update(ctx, authenticationFlow)
+	}
+
+	// Otherwise remove the finalizer
+	newFinalizers := []string{}
+	for _, finalizer := range authenticationFlow.Finalizers {
+		if finalizer == AuthenticationFlowFinalizer {
+			logKcaf.Info(fmt.Sprintf("removed finalizer from keycloak authentication flow %v/%v",
+				authenticationFlow.Namespace,
+				authenticationFlow.Spec.Flow.Alias))
+
+			continue
+		}
+		newFinalizers = append(newFinalizers, finalizer)
+	}
+
+	authenticationFlow.Finalizers = newFinalizers
+	return r.Client.Update(ctx, authenticationFlow)
+}
+
+func (r *KeycloakAuthenticationFlowReconciler) ManageError(ctx context.Context, authenticationFlow *kc.KeycloakAuthenticationFlow, issue error) (reconcile.Result, error) {
+	r.recorder.Event(authenticationFlow, "Warning", "ProcessingError", issue.Error())
+
+	authenticationFlow.Status.Message = issue.Error()
+	authenticationFlow.Status.Ready = false
+	authenticationFlow.Status.Phase = kc.PhaseFailing
+
+	err := r.Client.Status().Update(ctx, authenticationFlow)
+	if err != nil {
+		logKcaf.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{}, issue
+}
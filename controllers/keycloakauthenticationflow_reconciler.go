@@ -0,0 +1,297 @@
+package controllers
+
+import (
+	"fmt"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
+)
+
+// Built-in flows of Keycloak a realm binding is reset to when the flow is unbound
+var realmFlowBindingDefaults = map[kc.AuthenticationFlowRealmBinding]string{
+	kc.AuthenticationFlowRealmBindingBrowser:          "browser",
+	kc.AuthenticationFlowRealmBindingDirectGrant:      "direct grant",
+	kc.AuthenticationFlowRealmBindingResetCredentials: "reset credentials",
+}
+
+var realmFlowBindings = []kc.AuthenticationFlowRealmBinding{
+	kc.AuthenticationFlowRealmBindingBrowser,
+	kc.AuthenticationFlowRealmBindingDirectGrant,
+	kc.AuthenticationFlowRealmBindingResetCredentials,
+}
+
+// expectedAuthenticationExecution is an execution of the resource at the position it is listed by Keycloak
+type expectedAuthenticationExecution struct {
+	// Alias of the flow the execution belongs to
+	FlowAlias string
+	Level     int
+	Execution kc.KeycloakAuthenticationExecution
+}
+
+type DedicatedKeycloakAuthenticationFlowReconciler struct { // nolint
+	Keycloak kc.Keycloak
+}
+
+func NewDedicatedKeycloakAuthenticationFlowReconciler(keycloak kc.Keycloak) *DedicatedKeycloakAuthenticationFlowReconciler {
+	return &DedicatedKeycloakAuthenticationFlowReconciler{
+		Keycloak: keycloak,
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) ReconcileIt(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow) common.DesiredClusterState {
+	desired := common.DesiredClusterState{}
+
+	desired.AddAction(i.pingKeycloak())
+	if cr.DeletionTimestamp != nil {
+		if state.Flow != nil {
+			// a flow can't be deleted as long as it is bound to the realm
+			if bindings := desiredRealmFlowBindings(state, cr.Spec.Flow.Alias, nil); bindings != nil {
+				desired.AddAction(i.getUpdatedRealmFlowBindingsState(state, cr, bindings))
+			}
+			desired.AddAction(i.getDeletedAuthenticationFlowState(state, cr))
+		}
+		return desired
+	}
+
+	if state.Flow == nil {
+		desired.AddAction(i.getCreatedAuthenticationFlowState(state, cr))
+		if len(cr.Spec.Flow.Executions) > 0 {
+			desired.AddAction(i.getReplacedAuthenticationExecutionsState(state, cr))
+		}
+	} else {
+		if state.Flow.Description != cr.Spec.Flow.Description {
+			desired.AddAction(i.getUpdatedAuthenticationFlowState(state, cr))
+		}
+		i.ReconcileExecutions(state, cr, &desired)
+	}
+
+	if bindings := desiredRealmFlowBindings(state, cr.Spec.Flow.Alias, cr.Spec.RealmBindings); bindings != nil {
+		desired.AddAction(i.getUpdatedRealmFlowBindingsState(state, cr, bindings))
+	}
+
+	return desired
+}
+
+// ReconcileExecutions updates requirements and authenticator configs of the executions. Keycloak can't move
+// executions between flows, so all executions are replaced when executions or sub-flows are added, removed or
+// reordered.
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) ReconcileExecutions(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow, desired *common.DesiredClusterState) {
+	expected := flattenAuthenticationExecutions(cr.Spec.Flow.Alias, cr.Spec.Flow.Executions, 0)
+	if !authenticationExecutionsMatch(state.Executions, expected) {
+		desired.AddAction(i.getReplacedAuthenticationExecutionsState(state, cr))
+		return
+	}
+
+	for index, execution := range expected {
+		existing := state.Executions[index]
+		if existing.Requirement != execution.Execution.Requirement {
+			updated := existing.DeepCopy()
+			updated.Requirement = execution.Execution.Requirement
+			desired.AddAction(i.getUpdatedAuthenticationExecutionState(state, cr, execution.FlowAlias, updated))
+		}
+
+		config := execution.Execution.AuthenticatorConfig
+		switch {
+		case config == nil && existing.AuthenticationConfig != "":
+			desired.AddAction(i.getDeletedAuthenticatorConfigState(state, cr, existing.AuthenticationConfig))
+		case config != nil && existing.AuthenticationConfig == "":
+			created := config.DeepCopy()
+			created.ID = ""
+			desired.AddAction(i.getCreatedAuthenticatorConfigState(state, cr, existing.ID, created))
+		case config != nil:
+			current := state.AuthenticatorConfigs[existing.AuthenticationConfig]
+			if current == nil || current.Alias != config.Alias || len(current.Config) != len(config.Config) || !configContains(current.Config, config.Config) {
+				updated := config.DeepCopy()
+				updated.ID = existing.AuthenticationConfig
+				desired.AddAction(i.getUpdatedAuthenticatorConfigState(state, cr, updated))
+			}
+		}
+	}
+}
+
+// flattenAuthenticationExecutions lists the executions and the executions of their sub-flows depth first
+// in the order of their priority, like Keycloak lists the executions of a flow
+func flattenAuthenticationExecutions(flowAlias string, executions []kc.KeycloakAuthenticationExecution, level int) []expectedAuthenticationExecution {
+	var flattened []expectedAuthenticationExecution
+	for _, execution := range model.SortAuthenticationExecutions(executions) {
+		flattened = append(flattened, expectedAuthenticationExecution{
+			FlowAlias: flowAlias,
+			Level:     level,
+			Execution: execution,
+		})
+		if execution.SubFlow != nil {
+			flattened = append(flattened, flattenAuthenticationExecutions(execution.SubFlow.Alias, execution.SubFlow.Executions, level+1)...)
+		}
+	}
+	return flattened
+}
+
+// authenticationExecutionsMatch checks that the existing executions are the expected authenticators and
+// sub-flows at the expected positions
+func authenticationExecutionsMatch(existing []kc.KeycloakAPIAuthenticationExecutionInfo, expected []expectedAuthenticationExecution) bool {
+	if len(existing) != len(expected) {
+		return false
+	}
+	for index, execution := range expected {
+		if existing[index].Level != execution.Level {
+			return false
+		}
+		if execution.Execution.SubFlow != nil {
+			if !existing[index].AuthenticationFlow || existing[index].DisplayName != execution.Execution.SubFlow.Alias {
+				return false
+			}
+		} else if existing[index].AuthenticationFlow || existing[index].ProviderID != execution.Execution.Authenticator {
+			return false
+		}
+	}
+	return true
+}
+
+// desiredRealmFlowBindings returns the realm flow bindings that need to be changed, so the flow is bound
+// to the given bindings only, or nil if nothing needs to be changed
+func desiredRealmFlowBindings(state *common.AuthenticationFlowState, flowAlias string, bound []kc.AuthenticationFlowRealmBinding) *kc.KeycloakAPIRealmFlowBindings {
+	if state.RealmFlowBindings == nil {
+		return nil
+	}
+
+	changed := false
+	bindings := &kc.KeycloakAPIRealmFlowBindings{}
+	for _, binding := range realmFlowBindings {
+		current := getRealmFlowBinding(state.RealmFlowBindings, binding)
+		if containsRealmFlowBinding(bound, binding) {
+			if current != flowAlias {
+				setRealmFlowBinding(bindings, binding, flowAlias)
+				changed = true
+			}
+		} else if current == flowAlias {
+			setRealmFlowBinding(bindings, binding, realmFlowBindingDefaults[binding])
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return bindings
+}
+
+func getRealmFlowBinding(bindings *kc.KeycloakAPIRealmFlowBindings, binding kc.AuthenticationFlowRealmBinding) string {
+	switch binding {
+	case kc.AuthenticationFlowRealmBindingBrowser:
+		return bindings.BrowserFlow
+	case kc.AuthenticationFlowRealmBindingDirectGrant:
+		return bindings.DirectGrantFlow
+	case kc.AuthenticationFlowRealmBindingResetCredentials:
+		return bindings.ResetCredentialsFlow
+	}
+	return ""
+}
+
+func setRealmFlowBinding(bindings *kc.KeycloakAPIRealmFlowBindings, binding kc.AuthenticationFlowRealmBinding, flowAlias string) {
+	switch binding {
+	case kc.AuthenticationFlowRealmBindingBrowser:
+		bindings.BrowserFlow = flowAlias
+	case kc.AuthenticationFlowRealmBindingDirectGrant:
+		bindings.DirectGrantFlow = flowAlias
+	case kc.AuthenticationFlowRealmBindingResetCredentials:
+		bindings.ResetCredentialsFlow = flowAlias
+	}
+}
+
+func containsRealmFlowBinding(list []kc.AuthenticationFlowRealmBinding, binding kc.AuthenticationFlowRealmBinding) bool {
+	for _, item := range list {
+		if item == binding {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) pingKeycloak() common.ClusterAction {
+	return common.PingAction{
+		Msg: "check if keycloak is available",
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) getCreatedAuthenticationFlowState(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow) common.ClusterAction {
+	return common.CreateAuthenticationFlowAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("create authentication flow %v/%v", cr.Namespace, cr.Spec.Flow.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) getUpdatedAuthenticationFlowState(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow) common.ClusterAction {
+	return common.UpdateAuthenticationFlowAction{
+		FlowID: state.Flow.ID,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("update authentication flow %v/%v", cr.Namespace, cr.Spec.Flow.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) getDeletedAuthenticationFlowState(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow) common.ClusterAction {
+	return common.DeleteAuthenticationFlowAction{
+		FlowID: state.Flow.ID,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("delete authentication flow %v/%v", cr.Namespace, cr.Spec.Flow.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) getReplacedAuthenticationExecutionsState(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow) common.ClusterAction {
+	return common.ReplaceAuthenticationExecutionsAction{
+		Existing: state.Executions,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("replace executions of authentication flow %v/%v", cr.Namespace, cr.Spec.Flow.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) getUpdatedAuthenticationExecutionState(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow, flowAlias string, execution *kc.KeycloakAPIAuthenticationExecutionInfo) common.ClusterAction {
+	return common.UpdateAuthenticationExecutionAction{
+		FlowAlias: flowAlias,
+		Execution: execution,
+		Ref:       cr,
+		Realm:     state.Realm.Spec.Realm.Realm,
+		Msg:       fmt.Sprintf("update execution %v of authentication flow %v/%v", execution.DisplayName, cr.Namespace, flowAlias),
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) getCreatedAuthenticatorConfigState(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow, executionID string, config *kc.KeycloakAPIAuthenticatorConfig) common.ClusterAction {
+	return common.CreateAuthenticatorConfigAction{
+		ExecutionID: executionID,
+		Config:      config,
+		Ref:         cr,
+		Realm:       state.Realm.Spec.Realm.Realm,
+		Msg:         fmt.Sprintf("create authenticator config %v of authentication flow %v/%v", config.Alias, cr.Namespace, cr.Spec.Flow.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) getUpdatedAuthenticatorConfigState(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow, config *kc.KeycloakAPIAuthenticatorConfig) common.ClusterAction {
+	return common.UpdateAuthenticatorConfigAction{
+		Config: config,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("update authenticator config %v of authentication flow %v/%v", config.Alias, cr.Namespace, cr.Spec.Flow.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) getDeletedAuthenticatorConfigState(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow, configID string) common.ClusterAction {
+	return common.DeleteAuthenticatorConfigAction{
+		ConfigID: configID,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("delete authenticator config %v of authentication flow %v/%v", configID, cr.Namespace, cr.Spec.Flow.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakAuthenticationFlowReconciler) getUpdatedRealmFlowBindingsState(state *common.AuthenticationFlowState, cr *kc.KeycloakAuthenticationFlow, bindings *kc.KeycloakAPIRealmFlowBindings) common.ClusterAction {
+	return common.UpdateRealmFlowBindingsAction{
+		Bindings: bindings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("update flow bindings of realm %v for authentication flow %v/%v", state.Realm.Spec.Realm.Realm, cr.Namespace, cr.Spec.Flow.Alias),
+	}
+}
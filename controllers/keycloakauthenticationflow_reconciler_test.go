@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+
+	"github.com/stretchr/testify/assert"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getDummyAuthenticationFlow() *v1alpha1.KeycloakAuthenticationFlow {
+	return &v1alpha1.KeycloakAuthenticationFlow{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakAuthenticationFlowSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			RealmBindings: []v1alpha1.AuthenticationFlowRealmBinding{v1alpha1.AuthenticationFlowRealmBindingBrowser},
+			Flow: &v1alpha1.KeycloakAuthenticationFlowDefinition{
+				Alias: "custom-browser",
+				Executions: []v1alpha1.KeycloakAuthenticationExecution{
+					{
+						Requirement: "ALTERNATIVE",
+						Priority:    20,
+						SubFlow: &v1alpha1.KeycloakAuthenticationSubFlow{
+							Alias: "custom-browser-forms",
+							Executions: []v1alpha1.KeycloakAuthenticationExecution{
+								{Authenticator: "auth-username-password-form", Requirement: "REQUIRED"},
+							},
+						},
+					},
+					{
+						Authenticator: "auth-cookie",
+						Requirement:   "ALTERNATIVE",
+						Priority:      10,
+						AuthenticatorConfig: &v1alpha1.KeycloakAPIAuthenticatorConfig{
+							Alias:  "cookie",
+							Config: map[string]string{"key": "value"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getDummyAuthenticationFlowState() *common.AuthenticationFlowState {
+	return &common.AuthenticationFlowState{
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		AuthenticatorConfigs: map[string]*v1alpha1.KeycloakAPIAuthenticatorConfig{},
+		RealmFlowBindings: &v1alpha1.KeycloakAPIRealmFlowBindings{
+			BrowserFlow:          "browser",
+			DirectGrantFlow:      "direct grant",
+			ResetCredentialsFlow: "reset credentials",
+		},
+	}
+}
+
+// getDummyExistingAuthenticationFlowState returns the state of the dummy flow as listed by Keycloak
+func getDummyExistingAuthenticationFlowState() *common.AuthenticationFlowState {
+	state := getDummyAuthenticationFlowState()
+	state.Flow = &v1alpha1.KeycloakAPIAuthenticationFlow{ID: "flow-id", Alias: "custom-browser", TopLevel: true}
+	state.RealmFlowBindings.BrowserFlow = "custom-browser"
+	state.Executions = []v1alpha1.KeycloakAPIAuthenticationExecutionInfo{
+		{ID: "cookie-id", ProviderID: "auth-cookie", Requirement: "ALTERNATIVE", AuthenticationConfig: "config-id", Level: 0, Index: 0},
+		{ID: "forms-id", DisplayName: "custom-browser-forms", AuthenticationFlow: true, Requirement: "ALTERNATIVE", Level: 0, Index: 1},
+		{ID: "password-id", ProviderID: "auth-username-password-form", Requirement: "REQUIRED", Level: 1, Index: 0},
+	}
+	state.AuthenticatorConfigs["config-id"] = &v1alpha1.KeycloakAPIAuthenticatorConfig{
+		ID:     "config-id",
+		Alias:  "cookie",
+		Config: map[string]string{"key": "value"},
+	}
+	return state
+}
+
+func TestKeycloakAuthenticationFlowReconciler_Test_Creating_Flow(t *testing.T) {
+	// given
+	cr := getDummyAuthenticationFlow()
+	currentState := getDummyAuthenticationFlowState()
+
+	// when
+	reconciler := NewDedicatedKeycloakAuthenticationFlowReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 4)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.CreateAuthenticationFlowAction{}, desiredState[1])
+	assert.IsType(t, common.ReplaceAuthenticationExecutionsAction{}, desiredState[2])
+	bindings := desiredState[3].(common.UpdateRealmFlowBindingsAction).Bindings
+	assert.Equal(t, &v1alpha1.KeycloakAPIRealmFlowBindings{BrowserFlow: "custom-browser"}, bindings)
+}
+
+func TestKeycloakAuthenticationFlowReconciler_Test_Unchanged_Flow(t *testing.T) {
+	// given
+	cr := getDummyAuthenticationFlow()
+	currentState := getDummyExistingAuthenticationFlowState()
+
+	// when
+	reconciler := NewDedicatedKeycloakAuthenticationFlowReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// executions are matched in the order of their priority
+	assert.Len(t, desiredState, 1)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+}
+
+func TestKeycloakAuthenticationFlowReconciler_Test_Updating_Requirement_And_Config(t *testing.T) {
+	// given
+	cr := getDummyAuthenticationFlow()
+	cr.Spec.Flow.Executions[0].SubFlow.Executions[0].Requirement = "CONDITIONAL"
+	cr.Spec.Flow.Executions[1].AuthenticatorConfig.Config["key"] = "other"
+	currentState := getDummyExistingAuthenticationFlowState()
+
+	// when
+	reconciler := NewDedicatedKeycloakAuthenticationFlowReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the requirement is updated in the sub-flow the execution belongs to
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.Equal(t, "config-id", desiredState[1].(common.UpdateAuthenticatorConfigAction).Config.ID)
+	execution := desiredState[2].(common.UpdateAuthenticationExecutionAction)
+	assert.Equal(t, "custom-browser-forms", execution.FlowAlias)
+	assert.Equal(t, "password-id", execution.Execution.ID)
+	assert.Equal(t, "CONDITIONAL", execution.Execution.Requirement)
+	assert.Equal(t, "REQUIRED", currentState.Executions[2].Requirement)
+}
+
+func TestKeycloakAuthenticationFlowReconciler_Test_Replacing_Executions(t *testing.T) {
+	// given
+	cr := getDummyAuthenticationFlow()
+	cr.Spec.Flow.Executions[0].SubFlow.Executions = append(cr.Spec.Flow.Executions[0].SubFlow.Executions,
+		v1alpha1.KeycloakAuthenticationExecution{Authenticator: "auth-otp-form", Requirement: "REQUIRED"})
+	currentState := getDummyExistingAuthenticationFlowState()
+
+	// when
+	reconciler := NewDedicatedKeycloakAuthenticationFlowReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.Len(t, desiredState[1].(common.ReplaceAuthenticationExecutionsAction).Existing, 3)
+}
+
+func TestKeycloakAuthenticationFlowReconciler_Test_Delete_Flow(t *testing.T) {
+	// given
+	cr := getDummyAuthenticationFlow()
+	cr.DeletionTimestamp = &v13.Time{}
+	currentState := getDummyExistingAuthenticationFlowState()
+
+	// when
+	reconciler := NewDedicatedKeycloakAuthenticationFlowReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the realm is bound to the built-in browser flow again before the flow is deleted
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.Equal(t, "browser", desiredState[1].(common.UpdateRealmFlowBindingsAction).Bindings.BrowserFlow)
+	assert.Equal(t, "flow-id", desiredState[2].(common.DeleteAuthenticationFlowAction).FlowID)
+}
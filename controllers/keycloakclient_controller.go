@@ -19,18 +19,34 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
-	"k8s.io/apimachinery/pkg/api/errors"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"k8s.io/client-go/tools/record"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
 	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
@@ -39,11 +55,14 @@ import (
 
 // KeycloakClientReconciler reconciles a KeycloakClient object
 type KeycloakClientReconciler struct {
-	Client   client.Client
-	Scheme   *runtime.Scheme
-	context  context.Context
-	cancel   context.CancelFunc
-	recorder record.EventRecorder
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Namespace the admin credentials of all ClusterKeycloaks are stored in
+	OperatorNamespace string
+	context           context.Context
+	cancel            context.CancelFunc
+	recorder          record.EventRecorder
+	managedClients    *common.ManagedClients
 }
 
 var logKcc = logf.Log.WithName("controller_keycloakclient")
@@ -57,6 +76,12 @@ const (
 //+kubebuilder:rbac:groups=keycloak.org,resources=keycloakclients,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=keycloak.org,resources=keycloakclients/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=keycloak.org,resources=keycloakclients/finalizers,verbs=update
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakclienttemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=keycloak.org,resources=clusterkeycloakclienttemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -68,6 +93,17 @@ const (
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.12.2/pkg/reconcile
 func (r *KeycloakClientReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	ctx, span := common.StartSpan(ctx, "Reconcile KeycloakClient",
+		common.NamespaceAttribute.String(request.Namespace),
+		common.NameAttribute.String(request.Name),
+		common.KindAttribute.String("KeycloakClient"))
+	result, err := r.reconcile(ctx, request)
+	common.EndSpan(span, err)
+	return result, err
+}
+
+// reconcile reconciles the client with the span of the reconciliation in the context
+func (r *KeycloakClientReconciler) reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	_ = logf.FromContext(ctx)
 
 	reqLogger := logKcc.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
@@ -75,12 +111,13 @@ func (r *KeycloakClientReconciler) Reconcile(ctx context.Context, request ctrl.R
 
 	// Fetch the KeycloakClient instance
 	instance := &kc.KeycloakClient{}
-	err := r.Client.Get(context.TODO(), request.NamespacedName, instance)
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
 	if err != nil {
-		if errors.IsNotFound(err) {
+		if kubeerrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			r.managedClients.Set(request.String(), nil)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -90,62 +127,266 @@ func (r *KeycloakClientReconciler) Reconcile(ctx context.Context, request ctrl.R
 	r.adjustCrDefaults(instance)
 
 	// The client may be applicable to multiple keycloak instances,
-	// process all of them
-	realms, err := common.GetMatchingRealms(r.context, r.Client, instance.Spec.RealmSelector)
+	// process all of them. Cluster scoped realms and keycloaks keep their admin
+	// credentials in the operator namespace, so the client never needs access to them
+	instances, err := common.GetMatchingRealmInstances(ctx, r.Client, r.OperatorNamespace, instance.Spec.RealmSelector, instance.Spec.ClusterRealmSelector)
 	if err != nil {
-		return r.ManageError(instance, err)
+		return r.ManageError(ctx, instance, err)
 	}
-	logKcc.Info(fmt.Sprintf("found %v matching realm(s) for client %v/%v", len(realms.Items), instance.Namespace, instance.Name))
-	for _, realm := range realms.Items {
-		keycloaks, err := common.GetMatchingKeycloaks(r.context, r.Client, realm.Spec.InstanceSelector)
+	logKcc.Info(fmt.Sprintf("found %v matching realm(s) for client %v/%v", len(instances), instance.Namespace, instance.Name))
+
+	// the template and the redirect uri sources are not needed to delete the client
+	var desired *kc.KeycloakAPIClient
+	var requeueAfter time.Duration
+	if instance.DeletionTimestamp == nil {
+		desired, requeueAfter, err = r.desiredClient(ctx, instance)
 		if err != nil {
-			return r.ManageError(instance, err)
+			return r.ManageError(ctx, instance, err)
 		}
-		logKcc.Info(fmt.Sprintf("found %v matching keycloak(s) for realm %v/%v", len(keycloaks.Items), realm.Namespace, realm.Name))
+	}
 
-		for _, keycloak := range keycloaks.Items {
-			// Get an authenticated keycloak api client for the instance
-			keycloakFactory := common.LocalConfigKeycloakFactory{}
-			authenticated, err := keycloakFactory.AuthenticatedClient(keycloak, false)
-			if err != nil {
-				return r.ManageError(instance, err)
-			}
+	var unresolved []string
+	var realms []string
+	for _, realmInstance := range instances {
+		references, err := r.reconcileClientInRealm(ctx, instance, desired, realmInstance.Realm, realmInstance.Keycloak)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+		unresolved = append(unresolved, references...)
+		realms = append(realms, realmInstance.Realm.Spec.Realm.Realm)
+	}
 
-			// Compute the current state of the realm
-			logKcc.Info(fmt.Sprintf("got authenticated client for keycloak at %v", authenticated.Endpoint()))
-			clientState := common.NewClientState(r.context, realm.DeepCopy(), keycloak)
-
-			logKcc.Info(fmt.Sprintf("read client state for keycloak %v/%v, realm %v/%v, client %v/%v",
-				keycloak.Namespace,
-				keycloak.Name,
-				realm.Namespace,
-				realm.Name,
-				instance.Namespace,
-				instance.Name))
-
-			err = clientState.Read(r.context, instance, authenticated, r.Client)
-			if err != nil {
-				return r.ManageError(instance, err)
-			}
+	if instance.DeletionTimestamp != nil {
+		realms = nil
+	}
+	r.managedClients.Set(request.String(), realms)
 
-			// Figure out the actions to keep the realms up to date with
-			// the desired state
-			reconciler := NewDedicatedKeycloakClientReconciler(keycloak)
-			desiredState := reconciler.ReconcileIt(clientState, instance)
-			actionRunner := common.NewClusterAndKeycloakActionRunner(r.context, r.Client, r.Scheme, instance, authenticated)
+	setReferencesResolvedCondition(&instance.Status.Conditions, instance.Generation, unresolved)
+	if len(unresolved) > 0 {
+		// the references may be created later on, e.g. by another resource
+		return reconcile.Result{RequeueAfter: ClientRequeueDelayError}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+	}
 
-			// Run all actions to keep the realms updated
-			err = actionRunner.RunAll(desiredState)
-			if err != nil {
-				return r.ManageError(instance, err)
-			}
+	// generated client authentication keys are rotated and previous keys removed on time
+	return reconcile.Result{RequeueAfter: requeueAfter}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+
+}
+
+// desiredClient returns the client with the defaults of the template, the redirect uris derived from Ingresses and
+// HTTPRoutes, the SAML settings and the client authentication key applied, or nil when the client of the resource is
+// used as it is. The duration is the time until the client authentication key needs to be reconciled again, if any.
+func (r *KeycloakClientReconciler) desiredClient(ctx context.Context, instance *kc.KeycloakClient) (*kc.KeycloakAPIClient, time.Duration, error) {
+	if instance.Spec.TemplateRef == nil && instance.Spec.RedirectURISources == nil && instance.Spec.SAML == nil &&
+		instance.Spec.ClientAuthenticationKey == nil {
+		return nil, 0, nil
+	}
+
+	template, err := common.GetClientTemplate(ctx, r.Client, instance)
+	if err != nil {
+		return nil, 0, err
+	}
+	desired := instance.Spec.Client.DeepCopy()
+	if template != nil {
+		// the stored client tells fields set to false apart from fields that take the value of the template
+		fields, err := common.GetClientFields(ctx, r.Client, instance)
+		if err != nil {
+			return nil, 0, err
+		}
+		desired, err = model.MergeClientTemplate(fields, template)
+		if err != nil {
+			return nil, 0, err
 		}
 	}
 
-	return reconcile.Result{Requeue: false}, r.manageSuccess(instance, instance.DeletionTimestamp != nil)
+	origins, err := common.GetRedirectURIOrigins(ctx, r.Client, instance)
+	if err != nil {
+		return nil, 0, err
+	}
+	desired = model.ApplyRedirectURIOrigins(desired, instance.Spec.RedirectURISources, origins)
+
+	metadata, keys, err := common.GetClientSAMLSources(ctx, r.Client, instance)
+	if err != nil {
+		return nil, 0, err
+	}
+	desired = model.ApplyClientSAML(desired, instance.Spec.SAML, metadata, keys)
 
+	key := instance.Spec.ClientAuthenticationKey
+	if key == nil {
+		return desired, 0, nil
+	}
+	now := time.Now()
+	secret, err := r.clientAuthenticationKeySecret(ctx, instance, now)
+	if err != nil {
+		return nil, 0, err
+	}
+	desired, err = model.ApplyClientAuthenticationKey(desired, key, secret, now)
+	if err != nil {
+		return nil, 0, err
+	}
+	return desired, model.ClientAuthenticationKeyRequeueAfter(key, secret, now), nil
+}
+
+// clientAuthenticationKeySecret returns the Secret with the key the client authenticates with. With generation enabled
+// the Secret is generated when it doesn't exist and its key is rotated when it is due.
+func (r *KeycloakClientReconciler) clientAuthenticationKeySecret(ctx context.Context, instance *kc.KeycloakClient, now time.Time) (*corev1.Secret, error) {
+	key := instance.Spec.ClientAuthenticationKey
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: key.SecretName}, secret)
+	if err != nil {
+		if !kubeerrors.IsNotFound(err) || !key.Generate {
+			return nil, errors.Wrapf(err, "cannot get client authentication key secret %v", key.SecretName)
+		}
+
+		secret, err = model.GenerateClientAuthenticationSecret(instance, now)
+		if err != nil {
+			return nil, err
+		}
+		err = controllerutil.SetControllerReference(instance, secret, r.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		err = r.Client.Create(ctx, secret)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot create client authentication key secret %v", key.SecretName)
+		}
+		r.recorder.Event(instance, corev1.EventTypeNormal, "KeyGenerated", fmt.Sprintf("generated client authentication key secret %v", key.SecretName))
+		return secret, nil
+	}
+
+	if !key.Generate || !model.ClientAuthenticationKeyRotationDue(key, secret, now) {
+		return secret, nil
+	}
+	secret, err = model.RotateClientAuthenticationSecret(instance, secret, now)
+	if err != nil {
+		return nil, err
+	}
+	err = r.Client.Update(ctx, secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot rotate client authentication key secret %v", key.SecretName)
+	}
+	r.recorder.Event(instance, corev1.EventTypeNormal, "KeyRotated", fmt.Sprintf("rotated client authentication key secret %v", key.SecretName))
+	return secret, nil
 }
 
+// reconcileClientInRealm brings the client in the given realm of the given keycloak to the desired state,
+// it returns the references of the client that couldn't be resolved in the realm
+func (r *KeycloakClientReconciler) reconcileClientInRealm(ctx context.Context, instance *kc.KeycloakClient, desired *kc.KeycloakAPIClient, realm kc.KeycloakRealm, keycloak kc.Keycloak) (references []string, err error) {
+	ctx, span := common.StartSpan(ctx, "Reconcile KeycloakClient in realm", append(common.ResourceAttributes(instance),
+		common.RealmAttribute.String(realm.Spec.Realm.Realm),
+		common.KeycloakInstanceAttribute(keycloak))...)
+	defer func() { common.EndSpan(span, err) }()
+
+	if desired != nil {
+		// the desired client is reconciled using a copy of the resource, so the derived values are never stored
+		derived := instance.DeepCopy()
+		derived.Spec.Client = desired.DeepCopy()
+		derived.Spec.Client.ID = instance.Spec.Client.ID
+		derived.Spec.Client.Secret = instance.Spec.Client.Secret
+		defer keepClientChanges(instance, derived)
+		instance = derived
+	}
+
+	// clients the keycloak doesn't support are rejected before anything is changed in the realm. The error isn't
+	// permanent, the client is supported once the keycloak is upgraded or the feature is enabled.
+	if instance.DeletionTimestamp == nil {
+		err = model.ValidateClientCapabilities(instance.Spec.Client, keycloak.Status.ServerInfo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "keycloak %v/%v", keycloak.Namespace, keycloak.Name)
+		}
+	}
+
+	// Get an authenticated keycloak api client for the instance
+	keycloakFactory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := keycloakFactory.AuthenticatedClient(ctx, keycloak, false)
+	if err != nil {
+		return nil, err
+	}
+
+	logKcc.Info(fmt.Sprintf("got authenticated client for keycloak at %v", authenticated.Endpoint()))
+	reconciler := NewDedicatedKeycloakClientReconciler(keycloak)
+	err = r.runClientActions(ctx, instance, realm, keycloak, authenticated, reconciler)
+	if err != nil {
+		return nil, err
+	}
+
+	// the authorization settings are reconciled right away once the update of the client has enabled them
+	if reconciler.AuthorizationSettingsPending {
+		err = r.runClientActions(ctx, instance, realm, keycloak, authenticated, reconciler)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	references = make([]string, 0, len(reconciler.UnresolvedReferences))
+	for _, reference := range reconciler.UnresolvedReferences {
+		references = append(references, fmt.Sprintf("%v in realm %v", reference, realm.Spec.Realm.Realm))
+	}
+	return references, nil
+}
+
+// runClientActions reads the state of the client in the realm and runs the actions to bring it to the desired state
+func (r *KeycloakClientReconciler) runClientActions(ctx context.Context, instance *kc.KeycloakClient, realm kc.KeycloakRealm, keycloak kc.Keycloak, authenticated common.KeycloakInterface, reconciler *DedicatedKeycloakClientReconciler) error {
+	// Compute the current state of the realm
+	clientState := common.NewClientState(ctx, realm.DeepCopy(), keycloak)
+
+	logKcc.Info(fmt.Sprintf("read client state for keycloak %v/%v, realm %v/%v, client %v/%v",
+		keycloak.Namespace,
+		keycloak.Name,
+		realm.Namespace,
+		realm.Name,
+		instance.Namespace,
+		instance.Name))
+
+	err := clientState.Read(ctx, instance, authenticated, r.Client)
+	if err != nil {
+		return err
+	}
+
+	// Figure out the actions to keep the realms up to date with
+	// the desired state
+	desiredState := reconciler.ReconcileIt(clientState, instance)
+	actionRunner := common.NewClusterAndKeycloakActionRunner(ctx, r.Client, r.Scheme, instance, authenticated)
+
+	// Run all actions to keep the realms updated
+	return actionRunner.RunAll(desiredState)
+}
+
+// specOrLabelsChanged filters the updates of watched objects that only change their status
+var specOrLabelsChanged = builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}))
+
+// keycloakConnectionChanged passes the status updates of keycloaks that change the external url or the credential
+// secret the clients connect with. Both are copied from the spec by the keycloak controller after the spec update.
+var keycloakConnectionChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldStatus, newStatus := keycloakStatus(e.ObjectOld), keycloakStatus(e.ObjectNew)
+		if oldStatus == nil || newStatus == nil {
+			return false
+		}
+		return oldStatus.ExternalURL != newStatus.ExternalURL || oldStatus.CredentialSecret != newStatus.CredentialSecret
+	},
+}
+
+// specLabelsOrConnectionChanged filters the updates of watched keycloaks that only change the rest of their status
+var specLabelsOrConnectionChanged = builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, keycloakConnectionChanged))
+
+// keycloakStatus returns the status of a Keycloak or ClusterKeycloak and nil for other objects
+func keycloakStatus(obj client.Object) *kc.KeycloakStatus {
+	switch keycloak := obj.(type) {
+	case *kc.Keycloak:
+		return &keycloak.Status
+	case *kc.ClusterKeycloak:
+		return &keycloak.Status
+	}
+	return nil
+}
+
+const (
+	// secretReferencesField indexes the KeycloakClients by the names of the Secrets they take key material from
+	secretReferencesField = ".spec.secretReferences"
+	// configMapReferencesField indexes the KeycloakClients by the names of the ConfigMaps they read
+	configMapReferencesField = ".spec.configMapReferences"
+)
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *KeycloakClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	ctx := context.Background()
@@ -153,10 +394,327 @@ func (r *KeycloakClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.context = ctx
 	r.cancel = cancel
 	r.recorder = mgr.GetEventRecorderFor(ClientControllerName)
+	r.managedClients = common.NewManagedClients()
+
+	// the clients are indexed by the Secrets and ConfigMaps they refer to, so an event of a Secret or ConfigMap
+	// doesn't need to go through all clients of the namespace
+	err := mgr.GetFieldIndexer().IndexField(ctx, &kc.KeycloakClient{}, secretReferencesField, func(obj client.Object) []string {
+		return clientSecretReferences(obj.(*kc.KeycloakClient))
+	})
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(ctx, &kc.KeycloakClient{}, configMapReferencesField, func(obj client.Object) []string {
+		return clientConfigMapReferences(obj.(*kc.KeycloakClient))
+	})
+	if err != nil {
+		return err
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&keycloakv1alpha1.KeycloakClient{}).
-		Complete(r)
+		WithOptions(controllerOptions()).
+		Watches(&source.Kind{Type: &kc.KeycloakClientTemplate{}}, handler.EnqueueRequestsFromMapFunc(r.clientsForTemplate)).
+		Watches(&source.Kind{Type: &kc.ClusterKeycloakClientTemplate{}}, handler.EnqueueRequestsFromMapFunc(r.clientsForTemplate)).
+		Watches(&source.Kind{Type: &networkingv1.Ingress{}}, handler.EnqueueRequestsFromMapFunc(r.clientsForRedirectURISource)).
+		// only the Secrets the clients refer to and admin credentials are mapped, the ConfigMaps aren't cached as a whole
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.clientsForSecret),
+			builder.WithPredicates(predicate.NewPredicateFuncs(r.secretReferenced))).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.clientsForConfigMap),
+			builder.WithPredicates(predicate.NewPredicateFuncs(r.configMapReferenced)), builder.OnlyMetadata).
+		// the generated client secrets and client authentication keys are recreated when they are deleted
+		Owns(&corev1.Secret{}).
+		// status updates of realms don't change the clients, those of keycloaks only when they change the connection
+		Watches(&source.Kind{Type: &kc.KeycloakRealm{}}, handler.EnqueueRequestsFromMapFunc(r.clientsForRealm), specOrLabelsChanged).
+		Watches(&source.Kind{Type: &kc.ClusterKeycloakRealm{}}, handler.EnqueueRequestsFromMapFunc(r.clientsForRealm), specOrLabelsChanged).
+		Watches(&source.Kind{Type: &kc.Keycloak{}}, handler.EnqueueRequestsFromMapFunc(r.clientsForKeycloak), specLabelsOrConnectionChanged).
+		Watches(&source.Kind{Type: &kc.ClusterKeycloak{}}, handler.EnqueueRequestsFromMapFunc(r.clientsForKeycloak), specLabelsOrConnectionChanged)
+
+	// HTTPRoutes are only watched when the Gateway API is installed in the cluster
+	gvk, err := common.HTTPRouteGroupVersionKind(mgr.GetRESTMapper())
+	if err == nil {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(gvk)
+		controllerBuilder = controllerBuilder.Watches(&source.Kind{Type: route}, handler.EnqueueRequestsFromMapFunc(r.clientsForRedirectURISource))
+	} else {
+		logKcc.Info(fmt.Sprintf("not watching %v, redirect uris are only derived from them on reconciliation: %v", model.HTTPRouteGroupKind.Kind, err))
+	}
+
+	return controllerBuilder.Complete(withErrorBackoff(mgr.GetClient(), &keycloakv1alpha1.KeycloakClient{}, withReconcileTimeout(r)))
+}
+
+// clientsForRedirectURISource returns the requests for the clients in the namespace of an Ingress or HTTPRoute
+// that derive their redirect uris from it, so they are reconciled when its hosts change
+func (r *KeycloakClientReconciler) clientsForRedirectURISource(obj client.Object) []reconcile.Request {
+	clients := &kc.KeycloakClientList{}
+	err := r.Client.List(r.context, clients, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		logKcc.Error(err, "unable to list clients")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, keycloakClient := range clients.Items {
+		sources := keycloakClient.Spec.RedirectURISources
+		if sources == nil {
+			continue
+		}
+		refs := sources.Ingresses
+		if _, ok := obj.(*networkingv1.Ingress); !ok {
+			refs = sources.HTTPRoutes
+		}
+		if redirectURISourceMatches(refs, obj) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: keycloakClient.Namespace,
+					Name:      keycloakClient.Name,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+// clientsForSecret returns the requests for the clients that take their SAML key material or client authentication
+// key from the Secret and, if it keeps admin credentials, for the clients served by the keycloaks using them
+func (r *KeycloakClientReconciler) clientsForSecret(secret client.Object) []reconcile.Request {
+	requests := r.clientsReferencing(secret, secretReferencesField, clientSecretReferences)
+	if strings.HasPrefix(secret.GetName(), model.KeycloakAdminSecretPrefix) {
+		requests = append(requests, r.clientsForCredentialSecret(secret)...)
+	}
+	return requests
+}
+
+// clientsForConfigMap returns the requests for the clients that take the SAML metadata of their service provider from
+// the ConfigMap, only the metadata of the ConfigMaps is watched
+func (r *KeycloakClientReconciler) clientsForConfigMap(configMap client.Object) []reconcile.Request {
+	return r.clientsReferencing(configMap, configMapReferencesField, clientConfigMapReferences)
+}
+
+// secretReferenced filters the events of the Secrets that neither keep admin credentials nor are referenced by a client
+func (r *KeycloakClientReconciler) secretReferenced(secret client.Object) bool {
+	return strings.HasPrefix(secret.GetName(), model.KeycloakAdminSecretPrefix) ||
+		len(r.clientsReferencing(secret, secretReferencesField, clientSecretReferences)) > 0
+}
+
+// configMapReferenced filters the events of the ConfigMaps that are not referenced by a client
+func (r *KeycloakClientReconciler) configMapReferenced(configMap client.Object) bool {
+	return len(r.clientsReferencing(configMap, configMapReferencesField, clientConfigMapReferences)) > 0
+}
+
+// clientsReferencing returns the requests for the clients in the namespace of the object that refer to it by name, the
+// clients are looked up in the index of the field
+func (r *KeycloakClientReconciler) clientsReferencing(obj client.Object, field string, references func(*kc.KeycloakClient) []string) []reconcile.Request {
+	clients := &kc.KeycloakClientList{}
+	err := r.Client.List(r.context, clients, client.InNamespace(obj.GetNamespace()), client.MatchingFields{field: obj.GetName()})
+	if err != nil {
+		logKcc.Error(err, "unable to list clients")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for index := range clients.Items {
+		if containsString(references(&clients.Items[index]), obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: clients.Items[index].Namespace,
+					Name:      clients.Items[index].Name,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+// clientSecretReferences returns the names of the Secrets the client takes key material from
+func clientSecretReferences(cr *kc.KeycloakClient) []string {
+	var names []string
+	if saml := cr.Spec.SAML; saml != nil {
+		names = append(names, saml.SigningKeySecret, saml.EncryptionCertificateSecret)
+	}
+	if key := cr.Spec.ClientAuthenticationKey; key != nil {
+		names = append(names, key.SecretName)
+	}
+	return names
+}
+
+// clientConfigMapReferences returns the names of the ConfigMaps the client reads
+func clientConfigMapReferences(cr *kc.KeycloakClient) []string {
+	var names []string
+	if saml := cr.Spec.SAML; saml != nil && saml.ServiceProviderMetadata != nil && saml.ServiceProviderMetadata.ConfigMapKeyRef != nil {
+		names = append(names, saml.ServiceProviderMetadata.ConfigMapKeyRef.Name)
+	}
+	return names
+}
+
+// redirectURISourceMatches checks whether one of the references selects the object by name or labels
+func redirectURISourceMatches(refs []kc.RedirectURISourceReference, obj client.Object) bool {
+	for _, ref := range refs {
+		if ref.Name != "" {
+			if ref.Name == obj.GetName() {
+				return true
+			}
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+		if err == nil && selector.Matches(labels.Set(obj.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientsForTemplate returns the requests for the clients that refer to a KeycloakClientTemplate or
+// ClusterKeycloakClientTemplate, so they are reconciled when the template changes
+func (r *KeycloakClientReconciler) clientsForTemplate(template client.Object) []reconcile.Request {
+	kind := kc.ClientTemplateKind
+	var opts []client.ListOption
+	if _, ok := template.(*kc.ClusterKeycloakClientTemplate); ok {
+		kind = kc.ClusterClientTemplateKind
+	} else {
+		opts = append(opts, client.InNamespace(template.GetNamespace()))
+	}
+
+	clients := &kc.KeycloakClientList{}
+	err := r.Client.List(r.context, clients, opts...)
+	if err != nil {
+		logKcc.Error(err, "unable to list clients")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, keycloakClient := range clients.Items {
+		ref := keycloakClient.Spec.TemplateRef
+		if ref == nil || ref.Name != template.GetName() {
+			continue
+		}
+		if ref.Kind == kind || (ref.Kind == "" && kind == kc.ClientTemplateKind) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: keycloakClient.Namespace,
+					Name:      keycloakClient.Name,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+// clientsForRealm returns the requests for the clients whose realm selector matches the labels of a KeycloakRealm or
+// ClusterKeycloakRealm. Updates map the old and the new realm, so the clients are reconciled when the labels change.
+func (r *KeycloakClientReconciler) clientsForRealm(realm client.Object) []reconcile.Request {
+	_, clusterScoped := realm.(*kc.ClusterKeycloakRealm)
+	return r.clientsSelectingRealms(clusterScoped, []map[string]string{realm.GetLabels()})
+}
+
+// clientsForKeycloak returns the requests for the clients in the realms served by a Keycloak or ClusterKeycloak, so
+// they are reconciled when e.g. its URL changes
+func (r *KeycloakClientReconciler) clientsForKeycloak(keycloak client.Object) []reconcile.Request {
+	var realmLabels []map[string]string
+	_, clusterScoped := keycloak.(*kc.ClusterKeycloak)
+	if clusterScoped {
+		realms := &kc.ClusterKeycloakRealmList{}
+		err := r.Client.List(r.context, realms)
+		if err != nil {
+			logKcc.Error(err, "unable to list cluster realms")
+			return nil
+		}
+		for _, realm := range realms.Items {
+			if selectsLabels(realm.Spec.InstanceSelector, keycloak.GetLabels()) {
+				realmLabels = append(realmLabels, realm.Labels)
+			}
+		}
+	} else {
+		realms := &kc.KeycloakRealmList{}
+		err := r.Client.List(r.context, realms)
+		if err != nil {
+			logKcc.Error(err, "unable to list realms")
+			return nil
+		}
+		for _, realm := range realms.Items {
+			if selectsLabels(realm.Spec.InstanceSelector, keycloak.GetLabels()) {
+				realmLabels = append(realmLabels, realm.Labels)
+			}
+		}
+	}
+	if len(realmLabels) == 0 {
+		return nil
+	}
+	return r.clientsSelectingRealms(clusterScoped, realmLabels)
+}
+
+// clientsForCredentialSecret returns the requests for the clients in the realms served by the keycloaks whose admin
+// credentials are kept in the Secret, so they are reconciled when the credentials are rotated
+func (r *KeycloakClientReconciler) clientsForCredentialSecret(secret client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	keycloaks := &kc.KeycloakList{}
+	err := r.Client.List(r.context, keycloaks, client.InNamespace(secret.GetNamespace()))
+	if err != nil {
+		logKcc.Error(err, "unable to list keycloaks")
+		return nil
+	}
+	for index := range keycloaks.Items {
+		if common.KeycloakCredentialSecretName(keycloaks.Items[index]) == secret.GetName() {
+			requests = append(requests, r.clientsForKeycloak(&keycloaks.Items[index])...)
+		}
+	}
+
+	// cluster scoped keycloaks keep their admin credentials in the operator namespace
+	if r.OperatorNamespace == "" || secret.GetNamespace() != r.OperatorNamespace {
+		return requests
+	}
+	clusterKeycloaks := &kc.ClusterKeycloakList{}
+	err = r.Client.List(r.context, clusterKeycloaks)
+	if err != nil {
+		logKcc.Error(err, "unable to list cluster keycloaks")
+		return requests
+	}
+	for index := range clusterKeycloaks.Items {
+		if common.KeycloakCredentialSecretName(clusterKeycloaks.Items[index].ToKeycloak(r.OperatorNamespace)) == secret.GetName() {
+			requests = append(requests, r.clientsForKeycloak(&clusterKeycloaks.Items[index])...)
+		}
+	}
+	return requests
+}
+
+// clientsSelectingRealms returns the requests for the clients whose realm selector, or cluster realm selector, matches
+// one of the label sets
+func (r *KeycloakClientReconciler) clientsSelectingRealms(clusterScoped bool, realmLabels []map[string]string) []reconcile.Request {
+	clients := &kc.KeycloakClientList{}
+	err := r.Client.List(r.context, clients)
+	if err != nil {
+		logKcc.Error(err, "unable to list clients")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, keycloakClient := range clients.Items {
+		selector := keycloakClient.Spec.RealmSelector
+		if clusterScoped {
+			selector = keycloakClient.Spec.ClusterRealmSelector
+		}
+		for _, set := range realmLabels {
+			if selectsLabels(selector, set) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: keycloakClient.Namespace,
+						Name:      keycloakClient.Name,
+					},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// selectsLabels checks whether the match labels of the selector match the labels, the way realms and keycloaks are
+// looked up on reconciliation
+func selectsLabels(selector *metav1.LabelSelector, set map[string]string) bool {
+	if selector == nil {
+		return false
+	}
+	return labels.SelectorFromSet(selector.MatchLabels).Matches(labels.Set(set))
 }
 
 // Fills the CR with default values. Nils are not acceptable for Kubernetes.
@@ -172,19 +730,50 @@ func (r *KeycloakClientReconciler) adjustCrDefaults(cr *kc.KeycloakClient) {
 	}
 }
 
-func (r *KeycloakClientReconciler) manageSuccess(client *kc.KeycloakClient, deleted bool) error {
-	client.Status.Ready = true
-	client.Status.Message = ""
-	client.Status.Phase = v1alpha1.PhaseReconciling
+// keepClientChanges takes over the changes of the reconciliation of a copy of the resource: the ID and secret of the
+// client in Keycloak and the status
+func keepClientChanges(instance, reconciled *kc.KeycloakClient) {
+	instance.ResourceVersion = reconciled.ResourceVersion
+	instance.Spec.Client.ID = reconciled.Spec.Client.ID
+	instance.Spec.Client.Secret = reconciled.Spec.Client.Secret
+	instance.Status = reconciled.Status
+}
+
+// setReferencesResolvedCondition sets the ReferencesResolved condition of a resource that refers to roles, clients or
+// other objects of the realm by name
+func setReferencesResolvedCondition(conditions *[]metav1.Condition, generation int64, unresolved []string) {
+	condition := metav1.Condition{
+		Type:               kc.ConditionReferencesResolved,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             kc.ReasonReferencesResolved,
+		Message:            "all references could be resolved",
+	}
+	if len(unresolved) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = kc.ReasonUnresolvedReferences
+		condition.Message = "unresolved references: " + strings.Join(unresolved, ", ")
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+func (r *KeycloakClientReconciler) manageSuccess(ctx context.Context, cr *kc.KeycloakClient, deleted bool) error {
+	cr.Status.Ready = true
+	cr.Status.Message = ""
+	if condition := meta.FindStatusCondition(cr.Status.Conditions, kc.ConditionReferencesResolved); condition != nil && condition.Status == metav1.ConditionFalse {
+		cr.Status.Ready = false
+		cr.Status.Message = condition.Message
+	}
+	cr.Status.Phase = v1alpha1.PhaseReconciling
 
-	err := r.Client.Status().Update(r.context, client)
+	err := r.Client.Status().Update(ctx, cr)
 	if err != nil {
 		logKcc.Error(err, "unable to update status")
 	}
 
 	// Finalizer already set?
 	finalizerExists := false
-	for _, finalizer := range client.Finalizers {
+	for _, finalizer := range cr.Finalizers {
 		if finalizer == ClientFinalizer {
 			finalizerExists = true
 			break
@@ -196,47 +785,48 @@ func (r *KeycloakClientReconciler) manageSuccess(client *kc.KeycloakClient, dele
 		return nil
 	}
 
+	// Only the finalizers are patched, writing the whole resource would store the fields of the client that are not
+	// set with their zero values, so they could no longer be told apart from fields set to false
+	patch := client.MergeFrom(cr.DeepCopy())
+
 	// Resource created and finalizer does not exist: add finalizer
 	if !deleted && !finalizerExists {
-		client.Finalizers = append(client.Finalizers, ClientFinalizer)
+		cr.Finalizers = append(cr.Finalizers, ClientFinalizer)
 		logKcc.Info(fmt.Sprintf("added finalizer to keycloak client %v/%v",
-			client.Namespace,
-			client.Spec.Client.ClientID))
+			cr.Namespace,
+			cr.Spec.Client.ClientID))
 
-		return r.Client.Update(r.context, client)
+		return r.Client.Patch(ctx, cr, patch)
 	}
 
 	// Otherwise remove the finalizer
 	newFinalizers := []string{}
-	for _, finalizer := range client.Finalizers {
+	for _, finalizer := range cr.Finalizers {
 		if finalizer == ClientFinalizer {
 			logKcc.Info(fmt.Sprintf("removed finalizer from keycloak client %v/%v",
-				client.Namespace,
-				client.Spec.Client.ClientID))
+				cr.Namespace,
+				cr.Spec.Client.ClientID))
 
 			continue
 		}
 		newFinalizers = append(newFinalizers, finalizer)
 	}
 
-	client.Finalizers = newFinalizers
-	return r.Client.Update(r.context, client)
+	cr.Finalizers = newFinalizers
+	return r.Client.Patch(ctx, cr, patch)
 }
 
-func (r *KeycloakClientReconciler) ManageError(realm *kc.KeycloakClient, issue error) (reconcile.Result, error) {
+func (r *KeycloakClientReconciler) ManageError(ctx context.Context, realm *kc.KeycloakClient, issue error) (reconcile.Result, error) {
 	r.recorder.Event(realm, "Warning", "ProcessingError", issue.Error())
 
 	realm.Status.Message = issue.Error()
 	realm.Status.Ready = false
 	realm.Status.Phase = v1alpha1.PhaseFailing
 
-	err := r.Client.Status().Update(r.context, realm)
+	err := r.Client.Status().Update(ctx, realm)
 	if err != nil {
 		logKcc.Error(err, "unable to update status")
 	}
 
-	return reconcile.Result{
-		RequeueAfter: ClientRequeueDelayError,
-		Requeue:      true,
-	}, nil
+	return reconcile.Result{}, issue
 }
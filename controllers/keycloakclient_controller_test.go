@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func getWatchesReconciler(t *testing.T) *KeycloakClientReconciler {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	objects := []runtime.Object{
+		&v1alpha1.Keycloak{
+			ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "keycloak", Labels: map[string]string{"app": "keycloak"}},
+			Spec:       v1alpha1.KeycloakSpec{External: v1alpha1.KeycloakExternal{Enabled: true}},
+		},
+		&v1alpha1.KeycloakRealm{
+			ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "shop", Labels: map[string]string{"realm": "shop"}},
+			Spec: v1alpha1.KeycloakRealmSpec{
+				InstanceSelector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "keycloak"}},
+				Realm:            &v1alpha1.KeycloakAPIRealm{Realm: "shop"},
+			},
+		},
+		&v1alpha1.ClusterKeycloak{
+			ObjectMeta: v1.ObjectMeta{Name: "central", Labels: map[string]string{"app": "central"}},
+			Status:     v1alpha1.KeycloakStatus{CredentialSecret: "credential-central"},
+		},
+		&v1alpha1.ClusterKeycloakRealm{
+			ObjectMeta: v1.ObjectMeta{Name: "employees", Labels: map[string]string{"realm": "employees"}},
+			Spec: v1alpha1.KeycloakRealmSpec{
+				InstanceSelector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "central"}},
+				Realm:            &v1alpha1.KeycloakAPIRealm{Realm: "employees"},
+			},
+		},
+		&v1alpha1.KeycloakClient{
+			ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "orders"},
+			Spec:       v1alpha1.KeycloakClientSpec{RealmSelector: &v1.LabelSelector{MatchLabels: map[string]string{"realm": "shop"}}},
+		},
+		&v1alpha1.KeycloakClient{
+			ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "backoffice"},
+			Spec:       v1alpha1.KeycloakClientSpec{ClusterRealmSelector: &v1.LabelSelector{MatchLabels: map[string]string{"realm": "employees"}}},
+		},
+		&v1alpha1.KeycloakClient{
+			ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "saml"},
+			Spec: v1alpha1.KeycloakClientSpec{SAML: &v1alpha1.KeycloakClientSAML{
+				SigningKeySecret: "saml-signing",
+				ServiceProviderMetadata: &v1alpha1.SAMLMetadataSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "saml-metadata"},
+					Key:                  "metadata.xml",
+				}},
+			}},
+		},
+	}
+	return &KeycloakClientReconciler{
+		Client:            fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build(),
+		OperatorNamespace: "operator",
+		context:           context.Background(),
+	}
+}
+
+func clientRequest(namespace, name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+}
+
+func TestKeycloakClientWatches_Realm(t *testing.T) {
+	// given
+	reconciler := getWatchesReconciler(t)
+	shop := &v1alpha1.KeycloakRealm{ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "shop", Labels: map[string]string{"realm": "shop"}}}
+	relabeled := &v1alpha1.KeycloakRealm{ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "shop", Labels: map[string]string{"realm": "webshop"}}}
+	employees := &v1alpha1.ClusterKeycloakRealm{ObjectMeta: v1.ObjectMeta{Name: "employees", Labels: map[string]string{"realm": "employees"}}}
+
+	// when
+	shopRequests := reconciler.clientsForRealm(shop)
+	relabeledRequests := reconciler.clientsForRealm(relabeled)
+	employeesRequests := reconciler.clientsForRealm(employees)
+
+	// then
+	assert.Equal(t, []reconcile.Request{clientRequest("my-app", "orders")}, shopRequests)
+	assert.Empty(t, relabeledRequests)
+	// cluster realms are selected by the cluster realm selector only
+	assert.Equal(t, []reconcile.Request{clientRequest("my-app", "backoffice")}, employeesRequests)
+}
+
+func TestKeycloakClientWatches_Keycloak(t *testing.T) {
+	// given
+	reconciler := getWatchesReconciler(t)
+	keycloak := &v1alpha1.Keycloak{ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "keycloak", Labels: map[string]string{"app": "keycloak"}}}
+	other := &v1alpha1.Keycloak{ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "other", Labels: map[string]string{"app": "other"}}}
+	central := &v1alpha1.ClusterKeycloak{ObjectMeta: v1.ObjectMeta{Name: "central", Labels: map[string]string{"app": "central"}}}
+
+	// when
+	keycloakRequests := reconciler.clientsForKeycloak(keycloak)
+	otherRequests := reconciler.clientsForKeycloak(other)
+	centralRequests := reconciler.clientsForKeycloak(central)
+
+	// then
+	assert.Equal(t, []reconcile.Request{clientRequest("my-app", "orders")}, keycloakRequests)
+	assert.Empty(t, otherRequests)
+	assert.Equal(t, []reconcile.Request{clientRequest("my-app", "backoffice")}, centralRequests)
+}
+
+func TestKeycloakClientWatches_Keycloak_Connection_Changed(t *testing.T) {
+	// given
+	keycloak := &v1alpha1.Keycloak{ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "keycloak", Generation: 2}}
+	moved := keycloak.DeepCopy()
+	moved.Status.ExternalURL = "https://keycloak.example.com"
+	newCredential := keycloak.DeepCopy()
+	newCredential.Status.CredentialSecret = "credential-keycloak"
+	ready := keycloak.DeepCopy()
+	ready.Status.Ready = true
+	central := &v1alpha1.ClusterKeycloak{ObjectMeta: v1.ObjectMeta{Name: "central"}}
+	movedCentral := central.DeepCopy()
+	movedCentral.Status.ExternalURL = "https://central.example.com"
+
+	// when
+	movedPassed := keycloakConnectionChanged.Update(event.UpdateEvent{ObjectOld: keycloak, ObjectNew: moved})
+	newCredentialPassed := keycloakConnectionChanged.Update(event.UpdateEvent{ObjectOld: keycloak, ObjectNew: newCredential})
+	readyPassed := keycloakConnectionChanged.Update(event.UpdateEvent{ObjectOld: keycloak, ObjectNew: ready})
+	movedCentralPassed := keycloakConnectionChanged.Update(event.UpdateEvent{ObjectOld: central, ObjectNew: movedCentral})
+
+	// then
+	assert.True(t, movedPassed)
+	assert.True(t, newCredentialPassed)
+	assert.False(t, readyPassed)
+	assert.True(t, movedCentralPassed)
+}
+
+func TestKeycloakClientWatches_Credential_Secret(t *testing.T) {
+	// given
+	reconciler := getWatchesReconciler(t)
+	credential := &corev1.Secret{ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "credential-keycloak"}}
+	clusterCredential := &corev1.Secret{ObjectMeta: v1.ObjectMeta{Namespace: "operator", Name: "credential-central"}}
+	unrelated := &corev1.Secret{ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "credential-central"}}
+
+	// when
+	credentialRequests := reconciler.clientsForCredentialSecret(credential)
+	clusterCredentialRequests := reconciler.clientsForCredentialSecret(clusterCredential)
+	unrelatedRequests := reconciler.clientsForCredentialSecret(unrelated)
+
+	// then
+	assert.Equal(t, []reconcile.Request{clientRequest("my-app", "orders")}, credentialRequests)
+	assert.Equal(t, []reconcile.Request{clientRequest("my-app", "backoffice")}, clusterCredentialRequests)
+	assert.Empty(t, unrelatedRequests)
+}
+
+func TestKeycloakClientWatches_Secret_And_ConfigMap(t *testing.T) {
+	// given
+	reconciler := getWatchesReconciler(t)
+	signing := &corev1.Secret{ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "saml-signing"}}
+	credential := &corev1.Secret{ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "credential-keycloak"}}
+	unrelated := &corev1.Secret{ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "database"}}
+	metadata := &v1.PartialObjectMetadata{ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "saml-metadata"}}
+	otherNamespace := &v1.PartialObjectMetadata{ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "saml-metadata"}}
+
+	// when
+	signingRequests := reconciler.clientsForSecret(signing)
+	credentialRequests := reconciler.clientsForSecret(credential)
+	metadataRequests := reconciler.clientsForConfigMap(metadata)
+
+	// then
+	// unrelated Secrets and ConfigMaps are filtered before they are mapped
+	assert.Equal(t, []reconcile.Request{clientRequest("my-app", "saml")}, signingRequests)
+	assert.Equal(t, []reconcile.Request{clientRequest("my-app", "orders")}, credentialRequests)
+	assert.Equal(t, []reconcile.Request{clientRequest("my-app", "saml")}, metadataRequests)
+	assert.True(t, reconciler.secretReferenced(signing))
+	assert.True(t, reconciler.secretReferenced(credential))
+	assert.False(t, reconciler.secretReferenced(unrelated))
+	assert.True(t, reconciler.configMapReferenced(metadata))
+	assert.False(t, reconciler.configMapReferenced(otherNamespace))
+}
+
+func TestKeycloakClientReconciler_UnsupportedClient(t *testing.T) {
+	// given
+	reconciler := getWatchesReconciler(t)
+	instance := &v1alpha1.KeycloakClient{
+		ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "orders"},
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{ClientID: "orders", AuthorizationServicesEnabled: true},
+		},
+	}
+	realm := v1alpha1.KeycloakRealm{Spec: v1alpha1.KeycloakRealmSpec{Realm: &v1alpha1.KeycloakAPIRealm{Realm: "shop"}}}
+	keycloak := v1alpha1.Keycloak{
+		ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "keycloak"},
+		Status: v1alpha1.KeycloakStatus{
+			ServerInfo: &v1alpha1.KeycloakServerInfo{DisabledFeatures: []string{"authorization"}},
+		},
+	}
+
+	// when
+	_, err := reconciler.reconcileClientInRealm(context.TODO(), instance, nil, realm, keycloak)
+
+	// then
+	// the client is rejected before the keycloak is logged in to, it is retried until the keycloak supports it
+	assert.EqualError(t, err, "keycloak keycloak/keycloak: client orders is not supported: "+
+		"authorization services need the disabled feature authorization")
+	assert.False(t, common.IsPermanent(err))
+}
@@ -2,23 +2,38 @@ package controllers
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
 	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
 	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
 const (
 	umaRoleName = "uma_protection"
+	// type of the protocol mappers Keycloak adds to clients with a service account
+	serviceAccountMapperType = "oidc-usersessionmodel-note-mapper"
 )
 
+// user session notes of the protocol mappers Keycloak adds to clients with a service account
+var serviceAccountSessionNotes = []string{"clientId", "clientHost", "clientAddress", "client_id"}
+
 type ClientReconciler interface {
 	Reconcile(cr *kc.KeycloakClient) error
 }
 
 type DedicatedKeycloakClientReconciler struct { // nolint
 	Keycloak kc.Keycloak
+	// References of the resource that couldn't be resolved by ReconcileIt, the actions
+	// depending on them are left out
+	UnresolvedReferences []string
+	// Set by ReconcileIt when the update of the client enables its authorization services. The authorization settings
+	// only exist in Keycloak once they are enabled, so they are left to another run of ReconcileIt
+	AuthorizationSettingsPending bool
 }
 
 func NewDedicatedKeycloakClientReconciler(keycloak kc.Keycloak) *DedicatedKeycloakClientReconciler {
@@ -29,6 +44,8 @@ func NewDedicatedKeycloakClientReconciler(keycloak kc.Keycloak) *DedicatedKeyclo
 
 func (i *DedicatedKeycloakClientReconciler) ReconcileIt(state *common.ClientState, cr *kc.KeycloakClient) common.DesiredClusterState {
 	desired := common.DesiredClusterState{}
+	i.UnresolvedReferences = nil
+	i.AuthorizationSettingsPending = false
 
 	desired.AddAction(i.pingKeycloak())
 	if cr.DeletionTimestamp != nil {
@@ -36,6 +53,8 @@ func (i *DedicatedKeycloakClientReconciler) ReconcileIt(state *common.ClientStat
 		return desired
 	}
 
+	i.checkAuthenticationFlowBindingOverrides(state, cr)
+
 	if state.Client == nil {
 		if cr.Spec.Client.Secret == "" {
 			if state.ClientSecret != nil {
@@ -62,6 +81,15 @@ func (i *DedicatedKeycloakClientReconciler) ReconcileIt(state *common.ClientStat
 		desired.AddAction(i.getDeletedDeprecatedClientSecretState(state, cr))
 	}
 
+	if state.Client != nil {
+		// protocol mappers of new clients are created together with the client
+		i.ReconcileProtocolMappers(state, cr, &desired)
+	}
+
+	if state.SAMLIdentityProviderMetadata != nil {
+		i.ReconcileSAMLIdentityProviderMetadata(state, cr, &desired)
+	}
+
 	i.ReconcileRoles(state, cr, &desired)
 
 	i.ReconcileScopeMappings(state, cr, &desired)
@@ -74,6 +102,13 @@ func (i *DedicatedKeycloakClientReconciler) ReconcileIt(state *common.ClientStat
 		i.ReconcileServiceAccountRoles(state, cr, &desired)
 	}
 
+	if state.AuthorizationSettings != nil && cr.Spec.Client.AuthorizationSettings != nil {
+		i.ReconcileAuthorizationSettings(state, cr, &desired)
+	} else if state.Client != nil && !state.Client.AuthorizationServicesEnabled &&
+		cr.Spec.Client.AuthorizationServicesEnabled && cr.Spec.Client.AuthorizationSettings != nil {
+		i.AuthorizationSettingsPending = true
+	}
+
 	return desired
 }
 
@@ -135,7 +170,10 @@ func (i *DedicatedKeycloakClientReconciler) ReconcileScopeMappings(state *common
 		cr.Spec.ScopeMappings = &kc.MappingsRepresentation{}
 	}
 
-	mappingsNew := scopeMappingDifference(cr.Spec.ScopeMappings, state.ScopeMappings)
+	desiredMappings, unresolved := resolveScopeMappings(&state.AvailableRoles, cr.Spec.ScopeMappings)
+	i.UnresolvedReferences = append(i.UnresolvedReferences, unresolved...)
+
+	mappingsNew := scopeMappingDifference(desiredMappings, state.ScopeMappings)
 	if mappingsNew.RealmMappings != nil {
 		desired.AddAction(i.getCreatedClientRealmScopeMappingsState(state, cr, &mappingsNew.RealmMappings))
 	}
@@ -143,7 +181,7 @@ func (i *DedicatedKeycloakClientReconciler) ReconcileScopeMappings(state *common
 		desired.AddAction(i.getCreatedClientClientScopeMappingsState(state, cr, clientMappings.DeepCopy()))
 	}
 
-	mappingsDeleted := scopeMappingDifference(state.ScopeMappings, cr.Spec.ScopeMappings)
+	mappingsDeleted := scopeMappingDifference(state.ScopeMappings, desiredMappings)
 	if mappingsDeleted.RealmMappings != nil {
 		desired.AddAction(i.getDeletedClientRealmScopeMappingsState(state, cr, &mappingsDeleted.RealmMappings))
 	}
@@ -188,12 +226,492 @@ func (i *DedicatedKeycloakClientReconciler) ReconcileServiceAccountRoles(state *
 	}
 }
 
+// ReconcileAuthorizationSettings reconciles the resource server settings, scopes, resources and policies (including
+// permissions) of the client one by one. They are matched by name, those that are not part of the resource are deleted.
+// Scopes are created first and deleted last as resources and policies refer to them.
+func (i *DedicatedKeycloakClientReconciler) ReconcileAuthorizationSettings(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	existing := state.AuthorizationSettings
+	settings := cr.Spec.Client.AuthorizationSettings
+
+	if !resourceServerEquals(existing, settings) {
+		resourceServer := &kc.KeycloakResourceServer{
+			ID:                            existing.ID,
+			ClientID:                      existing.ClientID,
+			Name:                          existing.Name,
+			AllowRemoteResourceManagement: settings.AllowRemoteResourceManagement,
+			DecisionStrategy:              settings.DecisionStrategy,
+			PolicyEnforcementMode:         settings.PolicyEnforcementMode,
+		}
+		desired.AddAction(i.getUpdatedResourceServerState(state, cr, resourceServer))
+	}
+
+	for _, scope := range settings.Scopes {
+		existingScope := findAuthorizationScopeByName(existing.Scopes, scope.Name)
+		if existingScope == nil {
+			created := scope.DeepCopy()
+			created.ID = ""
+			desired.AddAction(i.getCreatedAuthorizationScopeState(state, cr, created))
+		} else if !authorizationScopeEquals(existingScope, &scope) {
+			updated := scope.DeepCopy()
+			updated.ID = existingScope.ID
+			desired.AddAction(i.getUpdatedAuthorizationScopeState(state, cr, updated))
+		}
+	}
+
+	for _, resource := range settings.Resources {
+		existingResource := findAuthorizationResourceByName(existing.Resources, resource.Name)
+		if existingResource == nil {
+			created := resource.DeepCopy()
+			created.ID = ""
+			desired.AddAction(i.getCreatedAuthorizationResourceState(state, cr, created))
+		} else if !authorizationResourceEquals(existingResource, &resource) {
+			updated := resource.DeepCopy()
+			updated.ID = existingResource.ID
+			desired.AddAction(i.getUpdatedAuthorizationResourceState(state, cr, updated))
+		}
+	}
+
+	for _, policy := range sortAuthorizationPolicies(settings.Policies) {
+		policy, unresolved := resolveAuthorizationPolicy(state, settings, &policy)
+		if len(unresolved) > 0 {
+			// a policy with missing references would grant or deny access to the wrong resources
+			i.UnresolvedReferences = append(i.UnresolvedReferences, unresolved...)
+			continue
+		}
+
+		existingPolicy := findAuthorizationPolicyByName(existing.Policies, policy.Name)
+		if existingPolicy != nil && policy.Type != "" && existingPolicy.Type != policy.Type {
+			// the type of a policy can't be changed
+			desired.AddAction(i.getDeletedAuthorizationPolicyState(state, cr, existingPolicy.DeepCopy()))
+			existingPolicy = nil
+		}
+		if existingPolicy == nil {
+			created := policy.DeepCopy()
+			created.ID = ""
+			desired.AddAction(i.getCreatedAuthorizationPolicyState(state, cr, created))
+		} else if !authorizationPolicyEquals(existingPolicy, policy) {
+			updated := policy.DeepCopy()
+			updated.ID = existingPolicy.ID
+			desired.AddAction(i.getUpdatedAuthorizationPolicyState(state, cr, updated))
+		}
+	}
+
+	for _, policy := range existing.Policies {
+		if findAuthorizationPolicyByName(settings.Policies, policy.Name) == nil {
+			desired.AddAction(i.getDeletedAuthorizationPolicyState(state, cr, policy.DeepCopy()))
+		}
+	}
+
+	for _, resource := range existing.Resources {
+		if findAuthorizationResourceByName(settings.Resources, resource.Name) == nil {
+			desired.AddAction(i.getDeletedAuthorizationResourceState(state, cr, resource.DeepCopy()))
+		}
+	}
+
+	for _, scope := range existing.Scopes {
+		if findAuthorizationScopeByName(settings.Scopes, scope.Name) == nil {
+			desired.AddAction(i.getDeletedAuthorizationScopeState(state, cr, scope.DeepCopy()))
+		}
+	}
+}
+
+// checkAuthenticationFlowBindingOverrides reports overrides with flows that don't exist, they are left out when the
+// client is created or updated
+func (i *DedicatedKeycloakClientReconciler) checkAuthenticationFlowBindingOverrides(state *common.ClientState, cr *kc.KeycloakClient) {
+	bindings := make([]string, 0, len(cr.Spec.Client.AuthenticationFlowBindingOverrides))
+	for binding := range cr.Spec.Client.AuthenticationFlowBindingOverrides {
+		bindings = append(bindings, binding)
+	}
+	sort.Strings(bindings)
+
+	for _, binding := range bindings {
+		flow := cr.Spec.Client.AuthenticationFlowBindingOverrides[binding]
+		if flow == "" {
+			continue
+		}
+		found := false
+		for _, existing := range state.AuthenticationFlows {
+			if existing.Alias == flow || existing.ID == flow {
+				found = true
+				break
+			}
+		}
+		if !found {
+			i.UnresolvedReferences = append(i.UnresolvedReferences, fmt.Sprintf("authentication flow %s", flow))
+		}
+	}
+}
+
+// ReconcileProtocolMappers creates, updates and deletes the protocol mappers of the client one by one, Keycloak ignores
+// the protocol mappers when the client is updated. Mappers are matched by name. The mappers of clients that don't set
+// protocolMappers are not managed, so none of them is deleted.
+func (i *DedicatedKeycloakClientReconciler) ReconcileProtocolMappers(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	if cr.Spec.Client.ProtocolMappers == nil {
+		return
+	}
+
+	mappersDeleted, _ := model.ProtocolMapperDifferenceIntersection(state.ProtocolMappers, cr.Spec.Client.ProtocolMappers)
+	// Prevent the protocol mappers Keycloak adds for service accounts from deletion when they are not present in the CR
+	if cr.Spec.Client.ServiceAccountsEnabled {
+		mappersDeleted = removeServiceAccountMappers(mappersDeleted)
+	}
+	for _, mapper := range mappersDeleted {
+		desired.AddAction(i.getDeletedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+
+	mappersNew, mappersMatching := model.ProtocolMapperDifferenceIntersection(cr.Spec.Client.ProtocolMappers, state.ProtocolMappers)
+	for _, mapper := range mappersNew {
+		mapper.ID = ""
+		desired.AddAction(i.getCreatedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+
+	for _, mapper := range mappersMatching {
+		existing := model.FindProtocolMapperByName(state.ProtocolMappers, mapper.Name)
+		if model.ProtocolMapperEquals(mapper, *existing) {
+			continue
+		}
+		mapper.ID = existing.ID
+		desired.AddAction(i.getUpdatedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+}
+
+// removeServiceAccountMappers removes the protocol mappers Keycloak adds to clients with a service account from m
+func removeServiceAccountMappers(m []kc.KeycloakProtocolMapper) []kc.KeycloakProtocolMapper {
+	var filteredMappers []kc.KeycloakProtocolMapper
+	for _, mapper := range m {
+		if mapper.ProtocolMapper == serviceAccountMapperType && containsString(serviceAccountSessionNotes, mapper.Config["user.session.note"]) {
+			continue
+		}
+		filteredMappers = append(filteredMappers, mapper)
+	}
+	return filteredMappers
+}
+
 // removeUMARole removes the uma_protection role from r if it is present
 func removeUMARole(r []kc.RoleRepresentation) []kc.RoleRepresentation {
 	filteredRoles, _ := model.RoleDifferenceIntersection(r, []kc.RoleRepresentation{{Name: umaRoleName}})
 	return filteredRoles
 }
 
+func resourceServerEquals(existing, desired *kc.KeycloakResourceServer) bool {
+	return existing.AllowRemoteResourceManagement == desired.AllowRemoteResourceManagement &&
+		(desired.DecisionStrategy == "" || existing.DecisionStrategy == desired.DecisionStrategy) &&
+		(desired.PolicyEnforcementMode == "" || existing.PolicyEnforcementMode == desired.PolicyEnforcementMode)
+}
+
+func authorizationScopeEquals(existing, desired *kc.KeycloakScope) bool {
+	return existing.DisplayName == desired.DisplayName && existing.IconURI == desired.IconURI
+}
+
+func authorizationResourceEquals(existing, desired *kc.KeycloakResource) bool {
+	return existing.DisplayName == desired.DisplayName &&
+		existing.Type == desired.Type &&
+		existing.IconURI == desired.IconURI &&
+		existing.OwnerManagedAccess == desired.OwnerManagedAccess &&
+		stringSetEquals(existing.Uris, desired.Uris) &&
+		len(existing.Attributes) == len(desired.Attributes) &&
+		configContains(existing.Attributes, desired.Attributes) &&
+		stringSetEquals(authorizationScopeNames(existing.Scopes), authorizationScopeNames(desired.Scopes))
+}
+
+// authorizationPolicyEquals compares policies, Keycloak adds defaults to the config of some policy types, so only the
+// config given in the resource is compared
+func authorizationPolicyEquals(existing, desired *kc.KeycloakPolicy) bool {
+	return existing.Description == desired.Description &&
+		(desired.Logic == "" || existing.Logic == desired.Logic) &&
+		(desired.DecisionStrategy == "" || existing.DecisionStrategy == desired.DecisionStrategy) &&
+		configContains(existing.Config, desired.Config, "roles", "clients") &&
+		authorizationPolicyRolesEqual(model.AuthorizationPolicyRoles(existing.Config), model.AuthorizationPolicyRoles(desired.Config)) &&
+		stringSetEquals(model.AuthorizationPolicyClients(existing.Config), model.AuthorizationPolicyClients(desired.Config)) &&
+		stringSetEquals(existing.Policies, desired.Policies) &&
+		stringSetEquals(existing.Resources, desired.Resources) &&
+		stringSetEquals(existing.Scopes, desired.Scopes)
+}
+
+// authorizationPolicyRolesEqual compares the roles of role policies, Keycloak doesn't keep their order
+func authorizationPolicyRolesEqual(a, b []model.AuthorizationPolicyRole) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, role := range a {
+		found := false
+		for _, other := range b {
+			if role == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// authorizationScopeNames returns the names of the scopes of a resource, scopes are given as objects with a name
+// or as plain names
+func authorizationScopeNames(scopes []apiextensionsv1.JSON) []string {
+	var names []string
+	for _, scope := range scopes {
+		var name string
+		if err := json.Unmarshal(scope.Raw, &name); err == nil {
+			names = append(names, name)
+			continue
+		}
+		named := struct {
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(scope.Raw, &named); err == nil {
+			names = append(names, named.Name)
+		}
+	}
+	return names
+}
+
+// sortAuthorizationPolicies returns the policies after the policies they refer to by name, policies and permissions
+// referring to other policies can only be created when these exist. Otherwise the order of the settings is kept, the
+// policies of a cycle are returned in the order they are found.
+func sortAuthorizationPolicies(policies []kc.KeycloakPolicy) []kc.KeycloakPolicy {
+	sorted := make([]kc.KeycloakPolicy, 0, len(policies))
+	added := make([]bool, len(policies))
+	var add func(index int)
+	add = func(index int) {
+		if added[index] {
+			return
+		}
+		added[index] = true
+		for _, name := range policies[index].Policies {
+			for referenced := range policies {
+				if policies[referenced].Name == name {
+					add(referenced)
+				}
+			}
+		}
+		sorted = append(sorted, policies[index])
+	}
+
+	for index := range policies {
+		add(index)
+	}
+	return sorted
+}
+
+func stringSetEquals(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, value := range a {
+		if !containsString(b, value) {
+			return false
+		}
+	}
+	for _, value := range b {
+		if !containsString(a, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func findAuthorizationScopeByName(scopes []kc.KeycloakScope, name string) *kc.KeycloakScope {
+	for index := range scopes {
+		if scopes[index].Name == name {
+			return &scopes[index]
+		}
+	}
+	return nil
+}
+
+func findAuthorizationResourceByName(resources []kc.KeycloakResource, name string) *kc.KeycloakResource {
+	for index := range resources {
+		if resources[index].Name == name {
+			return &resources[index]
+		}
+	}
+	return nil
+}
+
+func findAuthorizationPolicyByName(policies []kc.KeycloakPolicy, name string) *kc.KeycloakPolicy {
+	for index := range policies {
+		if policies[index].Name == name {
+			return &policies[index]
+		}
+	}
+	return nil
+}
+
+// resolveScopeMappings resolves the realm roles, clients and client roles of scope mappings that are given by name.
+// Mappings that can't be resolved are left out and returned as unresolved references.
+func resolveScopeMappings(roles *common.AvailableRoles, mappings *kc.MappingsRepresentation) (*kc.MappingsRepresentation, []string) {
+	resolved := &kc.MappingsRepresentation{ClientMappings: map[string]kc.ClientMappingsRepresentation{}}
+	var unresolved []string
+	if mappings == nil {
+		return resolved, unresolved
+	}
+
+	for _, role := range mappings.RealmMappings {
+		if role.ID == "" {
+			role = findRoleByName(roles.RealmRoles, role.Name, role)
+		}
+		if role.ID == "" {
+			unresolved = append(unresolved, fmt.Sprintf("realm role %s", role.Name))
+			continue
+		}
+		resolved.RealmMappings = append(resolved.RealmMappings, role)
+	}
+
+	for _, clientID := range sortedClientMappingKeys(mappings) {
+		clientMappings := mappings.ClientMappings[clientID]
+		if clientMappings.ID == "" {
+			if keycloakClient := roles.GetClientByClientID(clientID); keycloakClient != nil {
+				clientMappings.ID = keycloakClient.ID
+			}
+		}
+		if clientMappings.ID == "" {
+			unresolved = append(unresolved, fmt.Sprintf("client %s", clientID))
+			continue
+		}
+
+		resolvedClientMappings := kc.ClientMappingsRepresentation{ID: clientMappings.ID, Client: clientID}
+		for _, role := range clientMappings.Mappings {
+			if role.ID == "" {
+				role = findRoleByName(roles.ClientRoles[clientID], role.Name, role)
+			}
+			if role.ID == "" {
+				unresolved = append(unresolved, fmt.Sprintf("client role %s/%s", clientID, role.Name))
+				continue
+			}
+			resolvedClientMappings.Mappings = append(resolvedClientMappings.Mappings, role)
+		}
+		resolved.ClientMappings[clientID] = resolvedClientMappings
+	}
+
+	return resolved, unresolved
+}
+
+// resolveAuthorizationPolicy returns a copy of the policy with its associated policies, resources and scopes given by
+// name, they may be given by ID in the resource as well. The roles of role policies and the clients of client policies
+// are resolved to their IDs, as Keycloak returns them.
+func resolveAuthorizationPolicy(state *common.ClientState, settings *kc.KeycloakResourceServer, policy *kc.KeycloakPolicy) (*kc.KeycloakPolicy, []string) {
+	existing := state.AuthorizationSettings
+	resolved := policy.DeepCopy()
+	var unresolved []string
+
+	resolved.Policies = nil
+	for _, reference := range policy.Policies {
+		name := reference
+		if findAuthorizationPolicyByName(settings.Policies, reference) == nil && findAuthorizationPolicyByName(existing.Policies, reference) == nil {
+			name = ""
+			for _, existingPolicy := range existing.Policies {
+				if existingPolicy.ID == reference {
+					name = existingPolicy.Name
+				}
+			}
+		}
+		if name == "" {
+			unresolved = append(unresolved, fmt.Sprintf("authorization policy %s", reference))
+			continue
+		}
+		resolved.Policies = append(resolved.Policies, name)
+	}
+
+	resolved.Resources = nil
+	for _, reference := range policy.Resources {
+		name := reference
+		if findAuthorizationResourceByName(settings.Resources, reference) == nil && findAuthorizationResourceByName(existing.Resources, reference) == nil {
+			name = ""
+			for _, existingResource := range existing.Resources {
+				if existingResource.ID == reference {
+					name = existingResource.Name
+				}
+			}
+		}
+		if name == "" {
+			unresolved = append(unresolved, fmt.Sprintf("authorization resource %s", reference))
+			continue
+		}
+		resolved.Resources = append(resolved.Resources, name)
+	}
+
+	resolved.Scopes = nil
+	for _, reference := range policy.Scopes {
+		name := reference
+		if findAuthorizationScopeByName(settings.Scopes, reference) == nil && findAuthorizationScopeByName(existing.Scopes, reference) == nil {
+			name = ""
+			for _, existingScope := range existing.Scopes {
+				if existingScope.ID == reference {
+					name = existingScope.Name
+				}
+			}
+		}
+		if name == "" {
+			unresolved = append(unresolved, fmt.Sprintf("authorization scope %s", reference))
+			continue
+		}
+		resolved.Scopes = append(resolved.Scopes, name)
+	}
+
+	switch policy.Type {
+	case "role":
+		var roles []model.AuthorizationPolicyRole
+		for _, role := range model.AuthorizationPolicyRoles(policy.Config) {
+			id := resolveAuthorizationPolicyRole(&state.AvailableRoles, role.ID)
+			if id == "" {
+				unresolved = append(unresolved, fmt.Sprintf("role %s", role.ID))
+				continue
+			}
+			roles = append(roles, model.AuthorizationPolicyRole{ID: id, Required: role.Required})
+		}
+		if roles != nil {
+			config, _ := json.Marshal(roles)
+			resolved.Config["roles"] = string(config)
+		}
+	case "client":
+		var clients []string
+		for _, clientID := range model.AuthorizationPolicyClients(policy.Config) {
+			id := ""
+			for _, keycloakClient := range state.Clients {
+				if keycloakClient.ClientID == clientID || keycloakClient.ID == clientID {
+					id = keycloakClient.ID
+					break
+				}
+			}
+			if id == "" {
+				unresolved = append(unresolved, fmt.Sprintf("client %s", clientID))
+				continue
+			}
+			clients = append(clients, id)
+		}
+		if clients != nil {
+			config, _ := json.Marshal(clients)
+			resolved.Config["clients"] = string(config)
+		}
+	}
+
+	return resolved, unresolved
+}
+
+// resolveAuthorizationPolicyRole returns the ID of a role of a role policy, given by ID, by the name of a realm role
+// or by clientId/name of a client role
+func resolveAuthorizationPolicyRole(roles *common.AvailableRoles, reference string) string {
+	for _, role := range roles.RealmRoles {
+		if role.ID == reference || role.Name == reference {
+			return role.ID
+		}
+	}
+	for _, clientRoles := range roles.ClientRoles {
+		for _, role := range clientRoles {
+			if role.ID == reference {
+				return role.ID
+			}
+		}
+	}
+	if separator := strings.Index(reference, "/"); separator > 0 {
+		return findRoleByName(roles.ClientRoles[reference[:separator]], reference[separator+1:], kc.RoleRepresentation{}).ID
+	}
+	return ""
+}
+
 // determine which scope mappings are present in a but not in b
 // works on realm scope mappings and client scope mappings for each client separately
 func scopeMappingDifference(a *kc.MappingsRepresentation, b *kc.MappingsRepresentation) (d *kc.MappingsRepresentation) {
@@ -241,6 +759,26 @@ func scopeMappingDifference(a *kc.MappingsRepresentation, b *kc.MappingsRepresen
 }
 
 // ReconcileDefaultClientRoles see KEYCLOAK-19086
+// ReconcileSAMLIdentityProviderMetadata publishes the SAML metadata of the identity provider to the ConfigMap given in
+// the SAML settings of the client
+func (i *DedicatedKeycloakClientReconciler) ReconcileSAMLIdentityProviderMetadata(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	configMap := state.SAMLIdentityProviderMetadataConfigMap
+	if configMap == nil {
+		desired.AddAction(common.GenericCreateAction{
+			Ref: model.SAMLIdentityProviderMetadataConfigMap(cr, state.SAMLIdentityProviderMetadata),
+			Msg: fmt.Sprintf("create saml identity provider metadata %v/%v", cr.Namespace, cr.Spec.SAML.IdentityProviderMetadataConfigMap),
+		})
+		return
+	}
+
+	if configMap.Data[model.SAMLIdentityProviderMetadataKey] != string(state.SAMLIdentityProviderMetadata) {
+		desired.AddAction(common.GenericUpdateAction{
+			Ref: model.SAMLIdentityProviderMetadataConfigMapReconciled(configMap, state.SAMLIdentityProviderMetadata),
+			Msg: fmt.Sprintf("update saml identity provider metadata %v/%v", cr.Namespace, cr.Spec.SAML.IdentityProviderMetadataConfigMap),
+		})
+	}
+}
+
 func (i *DedicatedKeycloakClientReconciler) ReconcileDefaultClientRoles(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
 	var defaultRolesAdded []kc.RoleRepresentation
 	var defaultRolesDeleted []kc.RoleRepresentation
@@ -375,6 +913,33 @@ func (i *DedicatedKeycloakClientReconciler) getDeletedClientRoleState(state *com
 	}
 }
 
+func (i *DedicatedKeycloakClientReconciler) getCreatedProtocolMapperState(state *common.ClientState, cr *kc.KeycloakClient, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.CreateClientProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("create client protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, mapper.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getUpdatedProtocolMapperState(state *common.ClientState, cr *kc.KeycloakClient, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.UpdateClientProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("update client protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, mapper.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getDeletedProtocolMapperState(state *common.ClientState, cr *kc.KeycloakClient, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.DeleteClientProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("delete client protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, mapper.Name),
+	}
+}
+
 func (i *DedicatedKeycloakClientReconciler) getAddedDefaultClientRolesState(state *common.ClientState, cr *kc.KeycloakClient, roles *[]kc.RoleRepresentation) common.ClusterAction {
 	return common.AddDefaultRolesAction{
 		Roles:              roles,
@@ -431,7 +996,7 @@ func (i *DedicatedKeycloakClientReconciler) getDeletedClientClientScopeMappingsS
 	}
 }
 
-func (i *DedicatedKeycloakClientReconciler) getCreatedClientDefaultClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakClientScope) common.ClusterAction {
+func (i *DedicatedKeycloakClientReconciler) getCreatedClientDefaultClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakAPIClientScope) common.ClusterAction {
 	return common.UpdateClientDefaultClientScopeAction{
 		ClientScope: clientScope,
 		Ref:         cr,
@@ -440,7 +1005,7 @@ func (i *DedicatedKeycloakClientReconciler) getCreatedClientDefaultClientScopeSt
 	}
 }
 
-func (i *DedicatedKeycloakClientReconciler) getCreatedClientOptionalClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakClientScope) common.ClusterAction {
+func (i *DedicatedKeycloakClientReconciler) getCreatedClientOptionalClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakAPIClientScope) common.ClusterAction {
 	return common.UpdateClientOptionalClientScopeAction{
 		ClientScope: clientScope,
 		Ref:         cr,
@@ -449,7 +1014,7 @@ func (i *DedicatedKeycloakClientReconciler) getCreatedClientOptionalClientScopeS
 	}
 }
 
-func (i *DedicatedKeycloakClientReconciler) getDeletedClientDefaultClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakClientScope) common.ClusterAction {
+func (i *DedicatedKeycloakClientReconciler) getDeletedClientDefaultClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakAPIClientScope) common.ClusterAction {
 	return common.DeleteClientDefaultClientScopeAction{
 		ClientScope: clientScope,
 		Ref:         cr,
@@ -458,7 +1023,7 @@ func (i *DedicatedKeycloakClientReconciler) getDeletedClientDefaultClientScopeSt
 	}
 }
 
-func (i *DedicatedKeycloakClientReconciler) getDeletedClientOptionalClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakClientScope) common.ClusterAction {
+func (i *DedicatedKeycloakClientReconciler) getDeletedClientOptionalClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakAPIClientScope) common.ClusterAction {
 	return common.DeleteClientOptionalClientScopeAction{
 		ClientScope: clientScope,
 		Ref:         cr,
@@ -466,3 +1031,93 @@ func (i *DedicatedKeycloakClientReconciler) getDeletedClientOptionalClientScopeS
 		Msg:         fmt.Sprintf("delete client optional client scope %v/%v => %v", cr.Namespace, cr.Spec.Client.ClientID, clientScope.Name),
 	}
 }
+
+func (i *DedicatedKeycloakClientReconciler) getUpdatedResourceServerState(state *common.ClientState, cr *kc.KeycloakClient, resourceServer *kc.KeycloakResourceServer) common.ClusterAction {
+	return common.UpdateResourceServerAction{
+		ResourceServer: resourceServer,
+		Ref:            cr,
+		Realm:          state.Realm.Spec.Realm.Realm,
+		Msg:            fmt.Sprintf("update resource server %v/%v", cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getCreatedAuthorizationScopeState(state *common.ClientState, cr *kc.KeycloakClient, scope *kc.KeycloakScope) common.ClusterAction {
+	return common.CreateAuthorizationScopeAction{
+		Scope: scope,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("create authorization scope %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, scope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getUpdatedAuthorizationScopeState(state *common.ClientState, cr *kc.KeycloakClient, scope *kc.KeycloakScope) common.ClusterAction {
+	return common.UpdateAuthorizationScopeAction{
+		Scope: scope,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("update authorization scope %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, scope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getDeletedAuthorizationScopeState(state *common.ClientState, cr *kc.KeycloakClient, scope *kc.KeycloakScope) common.ClusterAction {
+	return common.DeleteAuthorizationScopeAction{
+		Scope: scope,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("delete authorization scope %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, scope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getCreatedAuthorizationResourceState(state *common.ClientState, cr *kc.KeycloakClient, resource *kc.KeycloakResource) common.ClusterAction {
+	return common.CreateAuthorizationResourceAction{
+		Resource: resource,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("create authorization resource %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, resource.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getUpdatedAuthorizationResourceState(state *common.ClientState, cr *kc.KeycloakClient, resource *kc.KeycloakResource) common.ClusterAction {
+	return common.UpdateAuthorizationResourceAction{
+		Resource: resource,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("update authorization resource %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, resource.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getDeletedAuthorizationResourceState(state *common.ClientState, cr *kc.KeycloakClient, resource *kc.KeycloakResource) common.ClusterAction {
+	return common.DeleteAuthorizationResourceAction{
+		Resource: resource,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("delete authorization resource %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, resource.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getCreatedAuthorizationPolicyState(state *common.ClientState, cr *kc.KeycloakClient, policy *kc.KeycloakPolicy) common.ClusterAction {
+	return common.CreateAuthorizationPolicyAction{
+		Policy: policy,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("create authorization policy %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, policy.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getUpdatedAuthorizationPolicyState(state *common.ClientState, cr *kc.KeycloakClient, policy *kc.KeycloakPolicy) common.ClusterAction {
+	return common.UpdateAuthorizationPolicyAction{
+		Policy: policy,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("update authorization policy %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, policy.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientReconciler) getDeletedAuthorizationPolicyState(state *common.ClientState, cr *kc.KeycloakClient, policy *kc.KeycloakPolicy) common.ClusterAction {
+	return common.DeleteAuthorizationPolicyAction{
+		Policy: policy,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("delete authorization policy %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, policy.Name),
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -241,9 +242,14 @@ func TestKeycloakClientReconciler_Test_Update_Client(t *testing.T) {
 			ClientMappings: map[string]v1alpha1.ClientMappingsRepresentation{"someclient": {Mappings: []v1alpha1.RoleRepresentation{{Name: "a"}, {Name: "b"}}}},
 			RealmMappings:  []v1alpha1.RoleRepresentation{{Name: "ra"}, {Name: "rb"}},
 		},
-		AvailableClientScopes: []v1alpha1.KeycloakClientScope{{Name: "address", ID: "222"}, {Name: "email", ID: "421"}, {Name: "profile", ID: "314"}},
-		DefaultClientScopes:   []v1alpha1.KeycloakClientScope{},
-		OptionalClientScopes:  []v1alpha1.KeycloakClientScope{{Name: "address", ID: "222"}},
+		AvailableClientScopes: []v1alpha1.KeycloakAPIClientScope{{Name: "address", ID: "222"}, {Name: "email", ID: "421"}, {Name: "profile", ID: "314"}},
+		DefaultClientScopes:   []v1alpha1.KeycloakAPIClientScope{},
+		OptionalClientScopes:  []v1alpha1.KeycloakAPIClientScope{{Name: "address", ID: "222"}},
+		AvailableRoles: common.AvailableRoles{
+			RealmRoles:  []v1alpha1.RoleRepresentation{{ID: "rbID", Name: "rb"}, {ID: "rcID", Name: "rc"}},
+			Clients:     []*v1alpha1.KeycloakAPIClient{{ID: "someclientID", ClientID: "someclient"}},
+			ClientRoles: map[string][]v1alpha1.RoleRepresentation{"someclient": {{ID: "bID", Name: "b"}, {ID: "cID", Name: "c"}}},
+		},
 	}
 
 	// when
@@ -251,6 +257,7 @@ func TestKeycloakClientReconciler_Test_Update_Client(t *testing.T) {
 	desiredState := reconciler.ReconcileIt(currentState, cr)
 
 	// then
+	assert.Empty(t, reconciler.UnresolvedReferences)
 	assert.IsType(t, common.PingAction{}, desiredState[0])
 	assert.IsType(t, common.UpdateClientAction{}, desiredState[1])
 	assert.Equal(t, "test", desiredState[1].(common.UpdateClientAction).Realm)
@@ -417,3 +424,395 @@ func TestKeycloakClientReconciler_Test_Remove_Client_ID_Client_Secret(t *testing
 	assert.IsType(t, model.DeprecatedClientSecret(cr), desiredState[3].(common.GenericDeleteAction).Ref)
 	assert.Equal(t, oldSecretName, desiredState[3].(common.GenericDeleteAction).Ref.(*v1.Secret).Name)
 }
+
+func getDummyAuthorizationClientState() *common.ClientState {
+	return &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{AuthorizationServicesEnabled: true},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		AuthorizationSettings: &v1alpha1.KeycloakResourceServer{
+			ID:                    "resource-server-id",
+			PolicyEnforcementMode: "ENFORCING",
+			DecisionStrategy:      "UNANIMOUS",
+			Scopes: []v1alpha1.KeycloakScope{
+				{ID: "read-id", Name: "read"},
+				{ID: "obsolete-id", Name: "obsolete"},
+			},
+			Resources: []v1alpha1.KeycloakResource{
+				{ID: "orders-id", Name: "orders", Uris: []string{"/orders"}, Scopes: []apiextensionsv1.JSON{{Raw: []byte(`{"id":"read-id","name":"read"}`)}}},
+				{ID: "default-resource-id", Name: "Default Resource"},
+			},
+			Policies: []v1alpha1.KeycloakPolicy{
+				{ID: "admins-id", Name: "admins", Type: "role", Logic: "POSITIVE", DecisionStrategy: "UNANIMOUS", Config: map[string]string{"roles": `[{"id":"admin-id","required":false}]`, "fetchRoles": "false"}},
+				{ID: "default-policy-id", Name: "Default Policy", Type: "js"},
+				{ID: "orders-permission-id", Name: "orders-permission", Type: "resource", Logic: "POSITIVE", DecisionStrategy: "UNANIMOUS", Resources: []string{"orders"}, Policies: []string{"admins"}},
+			},
+		},
+		AvailableRoles: common.AvailableRoles{
+			RealmRoles: []v1alpha1.RoleRepresentation{{ID: "admin-id", Name: "admin"}, {ID: "user-id", Name: "user"}},
+		},
+	}
+}
+
+func getDummyAuthorizationClient() *v1alpha1.KeycloakClient {
+	return &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:                     "test",
+				Secret:                       "test",
+				AuthorizationServicesEnabled: true,
+				AuthorizationSettings: &v1alpha1.KeycloakResourceServer{
+					PolicyEnforcementMode: "ENFORCING",
+					Scopes: []v1alpha1.KeycloakScope{
+						{Name: "read"},
+						{Name: "write"},
+					},
+					Resources: []v1alpha1.KeycloakResource{
+						{Name: "orders", Uris: []string{"/orders", "/orders/*"}, Scopes: []apiextensionsv1.JSON{{Raw: []byte(`{"name":"read"}`)}, {Raw: []byte(`"write"`)}}},
+					},
+					Policies: []v1alpha1.KeycloakPolicy{
+						{Name: "orders-permission", Type: "resource", Resources: []string{"orders"}, Policies: []string{"admins"}},
+						{Name: "admins", Type: "role", Config: map[string]string{"roles": `[{"id":"admin","required":false}]`}},
+						{Name: "users", Type: "role", Config: map[string]string{"roles": `[{"id":"user","required":false}]`}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestKeycloakClientReconciler_Test_Update_AuthorizationSettings(t *testing.T) {
+	// given
+	cr := getDummyAuthorizationClient()
+	currentState := getDummyAuthorizationClientState()
+
+	// when
+	reconciler := NewDedicatedKeycloakClientReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// scopes are created first and deleted last, unchanged policies are not updated
+	assert.Len(t, desiredState, 9)
+	assert.IsType(t, common.UpdateClientAction{}, desiredState[1])
+	assert.IsType(t, common.GenericUpdateAction{}, desiredState[2])
+	assert.Equal(t, "write", desiredState[3].(common.CreateAuthorizationScopeAction).Scope.Name)
+	resource := desiredState[4].(common.UpdateAuthorizationResourceAction).Resource
+	assert.Equal(t, "orders-id", resource.ID)
+	assert.Equal(t, []string{"/orders", "/orders/*"}, resource.Uris)
+	assert.Equal(t, "users", desiredState[5].(common.CreateAuthorizationPolicyAction).Policy.Name)
+	assert.Equal(t, "default-policy-id", desiredState[6].(common.DeleteAuthorizationPolicyAction).Policy.ID)
+	assert.Equal(t, "default-resource-id", desiredState[7].(common.DeleteAuthorizationResourceAction).Resource.ID)
+	assert.Equal(t, "obsolete-id", desiredState[8].(common.DeleteAuthorizationScopeAction).Scope.ID)
+}
+
+func TestKeycloakClientReconciler_Test_Enable_AuthorizationServices(t *testing.T) {
+	// given
+	cr := getDummyAuthorizationClient()
+	currentState := getDummyAuthorizationClientState()
+	currentState.Client.AuthorizationServicesEnabled = false
+	currentState.AuthorizationSettings = nil
+
+	// when
+	reconciler := NewDedicatedKeycloakClientReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+	pending := reconciler.AuthorizationSettingsPending
+	currentState = getDummyAuthorizationClientState()
+	enabledState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the settings are reconciled once the update of the client has enabled the authorization services
+	assert.True(t, pending)
+	assert.Len(t, desiredState, 3)
+	assert.True(t, desiredState[1].(common.UpdateClientAction).Ref.Spec.Client.AuthorizationServicesEnabled)
+	assert.False(t, reconciler.AuthorizationSettingsPending)
+	assert.Len(t, enabledState, 9)
+}
+
+func TestKeycloakClientReconciler_Test_Update_AuthorizationPolicy(t *testing.T) {
+	// given
+	cr := getDummyAuthorizationClient()
+	cr.Spec.Client.AuthorizationSettings.PolicyEnforcementMode = "PERMISSIVE"
+	cr.Spec.Client.AuthorizationSettings.Resources[0].Uris = []string{"/orders"}
+	cr.Spec.Client.AuthorizationSettings.Resources[0].Scopes = cr.Spec.Client.AuthorizationSettings.Resources[0].Scopes[:1]
+	cr.Spec.Client.AuthorizationSettings.Scopes = cr.Spec.Client.AuthorizationSettings.Scopes[:1]
+	cr.Spec.Client.AuthorizationSettings.Policies = []v1alpha1.KeycloakPolicy{
+		{Name: "orders-permission", Type: "scope", Scopes: []string{"read"}, Policies: []string{"admins"}},
+		{Name: "admins", Type: "role", Description: "Administrators", Config: map[string]string{"roles": `[{"id":"admin","required":false}]`}},
+	}
+	currentState := getDummyAuthorizationClientState()
+	currentState.AuthorizationSettings.Resources = currentState.AuthorizationSettings.Resources[:1]
+	currentState.AuthorizationSettings.Scopes = currentState.AuthorizationSettings.Scopes[:1]
+	currentState.AuthorizationSettings.Policies = append(currentState.AuthorizationSettings.Policies[:1], currentState.AuthorizationSettings.Policies[2])
+
+	// when
+	reconciler := NewDedicatedKeycloakClientReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the type of a permission can't be changed, it is deleted and created again
+	assert.Len(t, desiredState, 7)
+	resourceServer := desiredState[3].(common.UpdateResourceServerAction).ResourceServer
+	assert.Equal(t, "resource-server-id", resourceServer.ID)
+	assert.Equal(t, "PERMISSIVE", resourceServer.PolicyEnforcementMode)
+	assert.Nil(t, resourceServer.Policies)
+	assert.Equal(t, "admins-id", desiredState[4].(common.UpdateAuthorizationPolicyAction).Policy.ID)
+	assert.Equal(t, `[{"id":"admin-id","required":false}]`, desiredState[4].(common.UpdateAuthorizationPolicyAction).Policy.Config["roles"])
+	assert.Equal(t, "orders-permission-id", desiredState[5].(common.DeleteAuthorizationPolicyAction).Policy.ID)
+	assert.Equal(t, "scope", desiredState[6].(common.CreateAuthorizationPolicyAction).Policy.Type)
+}
+
+func TestKeycloakClientReconciler_Test_Sort_AuthorizationPolicies(t *testing.T) {
+	// given
+	policies := []v1alpha1.KeycloakPolicy{
+		{Name: "orders-permission", Type: "resource", Policies: []string{"staff"}},
+		{Name: "staff", Type: "aggregate", Policies: []string{"admins", "users"}},
+		{Name: "users", Type: "role"},
+		{Name: "admins", Type: "role"},
+		{Name: "cycle-a", Type: "aggregate", Policies: []string{"cycle-b"}},
+		{Name: "cycle-b", Type: "aggregate", Policies: []string{"cycle-a"}},
+	}
+
+	// when
+	sorted := sortAuthorizationPolicies(policies)
+
+	// then
+	// the permission is created after the aggregate policy it refers to
+	var names []string
+	for _, policy := range sorted {
+		names = append(names, policy.Name)
+	}
+	assert.Equal(t, []string{"admins", "users", "staff", "orders-permission", "cycle-b", "cycle-a"}, names)
+}
+
+func TestKeycloakClientReconciler_Test_Unresolved_References(t *testing.T) {
+	// given
+	cr := getDummyAuthorizationClient()
+	cr.Spec.Client.AuthenticationFlowBindingOverrides = map[string]string{"browser": "browser-with-otp", "direct_grant": ""}
+	cr.Spec.ScopeMappings = &v1alpha1.MappingsRepresentation{
+		RealmMappings: []v1alpha1.RoleRepresentation{{Name: "user"}, {Name: "missing"}},
+		ClientMappings: map[string]v1alpha1.ClientMappingsRepresentation{
+			"orders":  {Mappings: []v1alpha1.RoleRepresentation{{Name: "read"}, {Name: "write"}}},
+			"billing": {Mappings: []v1alpha1.RoleRepresentation{{Name: "read"}}},
+		},
+	}
+	cr.Spec.Client.AuthorizationSettings.Policies[2].Config = map[string]string{"roles": `[{"id":"orders/write","required":false}]`}
+	currentState := getDummyAuthorizationClientState()
+	currentState.AuthenticationFlows = []v1alpha1.KeycloakAPIAuthenticationFlow{{ID: "browser-id", Alias: "browser"}}
+	currentState.Clients = []*v1alpha1.KeycloakAPIClient{{ID: "orders-client-id", ClientID: "orders"}}
+	currentState.ClientRoles = map[string][]v1alpha1.RoleRepresentation{"orders": {{ID: "orders-read-id", Name: "read"}}}
+
+	// when
+	reconciler := NewDedicatedKeycloakClientReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// resolvable scope mappings are created, the policy with the missing role is left out
+	assert.Equal(t, []string{
+		"authentication flow browser-with-otp",
+		"realm role missing",
+		"client billing",
+		"client role orders/write",
+		"role orders/write",
+	}, reconciler.UnresolvedReferences)
+	for _, action := range desiredState {
+		if created, ok := action.(common.CreateAuthorizationPolicyAction); ok {
+			assert.NotEqual(t, "users", created.Policy.Name)
+		}
+		if created, ok := action.(common.CreateClientRealmScopeMappingsAction); ok {
+			assert.Equal(t, []v1alpha1.RoleRepresentation{{ID: "user-id", Name: "user"}}, *created.Mappings)
+		}
+		if created, ok := action.(common.CreateClientClientScopeMappingsAction); ok {
+			assert.Equal(t, "orders-client-id", created.Mappings.ID)
+			assert.Equal(t, []v1alpha1.RoleRepresentation{{ID: "orders-read-id", Name: "read"}}, created.Mappings.Mappings)
+		}
+	}
+}
+
+func TestKeycloakClientReconciler_Test_Resolve_ScopeMappings(t *testing.T) {
+	// given
+	roles := &common.AvailableRoles{
+		RealmRoles:  []v1alpha1.RoleRepresentation{{ID: "user-id", Name: "user"}},
+		Clients:     []*v1alpha1.KeycloakAPIClient{{ID: "orders-client-id", ClientID: "orders"}},
+		ClientRoles: map[string][]v1alpha1.RoleRepresentation{"orders": {{ID: "orders-read-id", Name: "read"}}},
+	}
+	mappings := &v1alpha1.MappingsRepresentation{
+		RealmMappings: []v1alpha1.RoleRepresentation{{Name: "user"}, {ID: "admin-id", Name: "admin"}},
+		ClientMappings: map[string]v1alpha1.ClientMappingsRepresentation{
+			"orders": {Mappings: []v1alpha1.RoleRepresentation{{Name: "read"}}},
+		},
+	}
+
+	// when
+	resolved, unresolved := resolveScopeMappings(roles, mappings)
+
+	// then
+	// roles given with an ID are kept as they are
+	assert.Empty(t, unresolved)
+	assert.Equal(t, []v1alpha1.RoleRepresentation{{ID: "user-id", Name: "user"}, {ID: "admin-id", Name: "admin"}}, resolved.RealmMappings)
+	assert.Equal(t, "orders-client-id", resolved.ClientMappings["orders"].ID)
+	assert.Equal(t, "orders-read-id", resolved.ClientMappings["orders"].Mappings[0].ID)
+}
+
+func TestKeycloakClientReconciler_Test_Update_ProtocolMappers(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:               "test",
+				Secret:                 "test",
+				ServiceAccountsEnabled: true,
+				ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+					{Name: "unchanged", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"included.client.audience": "a"}},
+					{Name: "changed", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"included.client.audience": "new"}},
+					{ID: "staleID", Name: "new", ProtocolMapper: "oidc-audience-mapper"},
+				},
+			},
+		},
+	}
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{ServiceAccountsEnabled: true},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+			{ID: "unchangedID", Name: "unchanged", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"included.client.audience": "a"}},
+			{ID: "changedID", Name: "changed", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"included.client.audience": "old"}},
+			{ID: "deletedID", Name: "deleted", ProtocolMapper: "oidc-audience-mapper"},
+			{ID: "clientIdID", Name: "Client ID", ProtocolMapper: "oidc-usersessionmodel-note-mapper", Config: map[string]string{"user.session.note": "clientId"}},
+		},
+	}
+
+	// when
+	reconciler := NewDedicatedKeycloakClientReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the mapper Keycloak added for the service account is kept
+	assert.IsType(t, common.UpdateClientAction{}, desiredState[1])
+	assert.Equal(t, "deletedID", desiredState[3].(common.DeleteClientProtocolMapperAction).Mapper.ID)
+	assert.Equal(t, "new", desiredState[4].(common.CreateClientProtocolMapperAction).Mapper.Name)
+	assert.Equal(t, "", desiredState[4].(common.CreateClientProtocolMapperAction).Mapper.ID)
+	assert.Equal(t, "changedID", desiredState[5].(common.UpdateClientProtocolMapperAction).Mapper.ID)
+	assert.Equal(t, "new", desiredState[5].(common.UpdateClientProtocolMapperAction).Mapper.Config["included.client.audience"])
+	assert.Len(t, desiredState, 6)
+}
+
+func TestKeycloakClientReconciler_Test_Unmanaged_ProtocolMappers(t *testing.T) {
+	// given
+	getCR := func(mappers []v1alpha1.KeycloakProtocolMapper) *v1alpha1.KeycloakClient {
+		return &v1alpha1.KeycloakClient{
+			ObjectMeta: v13.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: v1alpha1.KeycloakClientSpec{
+				Client: &v1alpha1.KeycloakAPIClient{ClientID: "test", Secret: "test", ProtocolMappers: mappers},
+			},
+		}
+	}
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+			{ID: "consoleID", Name: "console", ProtocolMapper: "oidc-audience-mapper"},
+		},
+	}
+
+	// when
+	reconciler := NewDedicatedKeycloakClientReconciler(v1alpha1.Keycloak{})
+	unmanagedState := reconciler.ReconcileIt(currentState, getCR(nil))
+	emptyState := reconciler.ReconcileIt(currentState, getCR([]v1alpha1.KeycloakProtocolMapper{}))
+
+	// then
+	// the mappers of clients without protocolMappers are kept, an empty list deletes them
+	assert.Empty(t, deletedProtocolMappers(unmanagedState))
+	assert.Equal(t, []string{"consoleID"}, deletedProtocolMappers(emptyState))
+}
+
+func deletedProtocolMappers(desiredState common.DesiredClusterState) []string {
+	var deleted []string
+	for _, action := range desiredState {
+		if deleteAction, ok := action.(common.DeleteClientProtocolMapperAction); ok {
+			deleted = append(deleted, deleteAction.Mapper.ID)
+		}
+	}
+	return deleted
+}
+
+func TestKeycloakClientReconciler_Test_SAML_IdentityProviderMetadata(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "https://sp.example.com/saml",
+				Protocol: "saml",
+			},
+			SAML: &v1alpha1.KeycloakClientSAML{
+				IdentityProviderMetadataConfigMap: "idp-metadata",
+			},
+		},
+	}
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{Protocol: "saml"},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		SAMLIdentityProviderMetadata: []byte("<EntityDescriptor/>"),
+	}
+
+	// when
+	reconciler := NewDedicatedKeycloakClientReconciler(v1alpha1.Keycloak{})
+	created := reconciler.ReconcileIt(currentState, cr)
+
+	currentState.SAMLIdentityProviderMetadataConfigMap = &v1.ConfigMap{Data: map[string]string{model.SAMLIdentityProviderMetadataKey: "<EntityDescriptor/>"}}
+	unchanged := reconciler.ReconcileIt(currentState, cr)
+
+	currentState.SAMLIdentityProviderMetadataConfigMap = &v1.ConfigMap{Data: map[string]string{model.SAMLIdentityProviderMetadataKey: "<old/>"}}
+	updated := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	configMap := created[3].(common.GenericCreateAction).Ref.(*v1.ConfigMap)
+	assert.Equal(t, "idp-metadata", configMap.Name)
+	assert.Equal(t, "test", configMap.Namespace)
+	assert.Equal(t, "<EntityDescriptor/>", configMap.Data[model.SAMLIdentityProviderMetadataKey])
+
+	assert.Len(t, unchanged, 3)
+
+	configMap = updated[3].(common.GenericUpdateAction).Ref.(*v1.ConfigMap)
+	assert.Equal(t, "<EntityDescriptor/>", configMap.Data[model.SAMLIdentityProviderMetadataKey])
+}
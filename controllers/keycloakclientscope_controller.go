@@ -0,0 +1,236 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+)
+
+// KeycloakClientScopeReconciler reconciles a KeycloakClientScope object
+type KeycloakClientScopeReconciler struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Namespace the admin credentials of all ClusterKeycloaks are stored in
+	OperatorNamespace string
+	context           context.Context
+	cancel            context.CancelFunc
+	recorder          record.EventRecorder
+}
+
+var logKccs = logf.Log.WithName("controller_keycloakclientscope")
+
+const (
+	ClientScopeFinalizer      = "clientscope.cleanup"
+	ClientScopeControllerName = "keycloakclientscope-controller"
+)
+
+// blank assignment to verify that KeycloakClientScopeReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &KeycloakClientScopeReconciler{}
+
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakclientscopes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakclientscopes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakclientscopes/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes the client scope in all realms selected by the KeycloakClientScope.
+func (r *KeycloakClientScopeReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	_ = logf.FromContext(ctx)
+
+	reqLogger := logKccs.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling KeycloakClientScope")
+
+	// Fetch the KeycloakClientScope instance
+	instance := &kc.KeycloakClientScope{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// The client scope may be applicable to multiple keycloak instances,
+	// process all of them
+	instances, err := common.GetMatchingRealmInstances(ctx, r.Client, r.OperatorNamespace, instance.Spec.RealmSelector, instance.Spec.ClusterRealmSelector)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+	logKccs.Info(fmt.Sprintf("found %v matching realm(s) for client scope %v/%v", len(instances), instance.Namespace, instance.Name))
+
+	var unresolved []string
+	for _, realmInstance := range instances {
+		references, err := r.reconcileClientScopeInRealm(ctx, instance, realmInstance.Realm, realmInstance.Keycloak)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+		unresolved = append(unresolved, references...)
+	}
+
+	setReferencesResolvedCondition(&instance.Status.Conditions, instance.Generation, unresolved)
+	if len(unresolved) > 0 {
+		// the references may be created later on, e.g. by another resource
+		return reconcile.Result{RequeueAfter: ClientRequeueDelayError}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+	}
+
+	return reconcile.Result{Requeue: false}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+}
+
+// reconcileClientScopeInRealm brings the client scope in the given realm of the given keycloak to the desired state,
+// it returns the references of the client scope that couldn't be resolved in the realm
+func (r *KeycloakClientScopeReconciler) reconcileClientScopeInRealm(ctx context.Context, instance *kc.KeycloakClientScope, realm kc.KeycloakRealm, keycloak kc.Keycloak) ([]string, error) {
+	// Get an authenticated keycloak api client for the instance
+	keycloakFactory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := keycloakFactory.AuthenticatedClient(ctx, keycloak, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute the current state of the client scope
+	clientScopeState := common.NewClientScopeState(ctx, realm.DeepCopy(), keycloak)
+
+	logKccs.Info(fmt.Sprintf("read client scope state for keycloak %v/%v, realm %v/%v, client scope %v/%v",
+		keycloak.Namespace,
+		keycloak.Name,
+		realm.Namespace,
+		realm.Name,
+		instance.Namespace,
+		instance.Name))
+
+	err = clientScopeState.Read(ctx, instance, authenticated, r.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Figure out the actions to keep the client scope up to date with
+	// the desired state
+	reconciler := NewDedicatedKeycloakClientScopeReconciler(keycloak)
+	desiredState := reconciler.ReconcileIt(clientScopeState, instance)
+	actionRunner := common.NewClusterAndKeycloakActionRunner(ctx, r.Client, r.Scheme, instance, authenticated)
+
+	// Run all actions to keep the client scope updated
+	err = actionRunner.RunAll(desiredState)
+	if err != nil {
+		return nil, err
+	}
+
+	references := make([]string, 0, len(reconciler.UnresolvedReferences))
+	for _, reference := range reconciler.UnresolvedReferences {
+		references = append(references, fmt.Sprintf("%v in realm %v", reference, realm.Spec.Realm.Realm))
+	}
+	return references, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeycloakClientScopeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	r.context = ctx
+	r.cancel = cancel
+	r.recorder = mgr.GetEventRecorderFor(ClientScopeControllerName)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kc.KeycloakClientScope{}).
+		WithOptions(controllerOptions()).
+		Complete(withErrorBackoff(mgr.GetClient(), &kc.KeycloakClientScope{}, withReconcileTimeout(r)))
+}
+
+func (r *KeycloakClientScopeReconciler) manageSuccess(ctx context.Context, clientScope *kc.KeycloakClientScope, deleted bool) error {
+	clientScope.Status.Ready = true
+	clientScope.Status.Message = ""
+	if condition := meta.FindStatusCondition(clientScope.Status.Conditions, kc.ConditionReferencesResolved); condition != nil && condition.Status == metav1.ConditionFalse {
+		clientScope.Status.Ready = false
+		clientScope.Status.Message = condition.Message
+	}
+	clientScope.Status.Phase = kc.PhaseReconciling
+
+	err := r.Client.Status().Update(ctx, clientScope)
+	if err != nil {
+		logKccs.Error(err, "unable to update status")
+	}
+
+	// Finalizer already set?
+	finalizerExists := false
+	for _, finalizer := range clientScope.Finalizers {
+		if finalizer == ClientScopeFinalizer {
+			finalizerExists = true
+			break
+		}
+	}
+
+	// Resource created and finalizer exists: nothing to do
+	if !deleted && finalizerExists {
+		return nil
+	}
+
+	// Resource created and finalizer does not exist: add finalizer
+	if !deleted && !finalizerExists {
+		clientScope.Finalizers = append(clientScope.Finalizers, ClientScopeFinalizer)
+		logKccs.Info(fmt.Sprintf("added finalizer to keycloak client scope %v/%v",
+			clientScope.Namespace,
+			clientScope.Spec.ClientScope.Name))
+
+		return r.Client.Update(ctx, clientScope)
+	}
+
+	// Otherwise remove the finalizer
+	newFinalizers := []string{}
+	for _, finalizer := range clientScope.Finalizers {
+		if finalizer == ClientScopeFinalizer {
+			logKccs.Info(fmt.Sprintf("removed finalizer from keycloak client scope %v/%v",
+				clientScope.Namespace,
+				clientScope.Spec.ClientScope.Name))
+
+			continue
+		}
+		newFinalizers = append(newFinalizers, finalizer)
+	}
+
+	clientScope.Finalizers = newFinalizers
+	return r.Client.Update(ctx, clientScope)
+}
+
+func (r *KeycloakClientScopeReconciler) ManageError(ctx context.Context, clientScope *kc.KeycloakClientScope, issue error) (reconcile.Result, error) {
+	r.recorder.Event(clientScope, "Warning", "ProcessingError", issue.Error())
+
+	clientScope.Status.Message = issue.Error()
+	clientScope.Status.Ready = false
+	clientScope.Status.Phase = kc.PhaseFailing
+
+	err := r.Client.Status().Update(ctx, clientScope)
+	if err != nil {
+		logKccs.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{}, issue
+}
@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"fmt"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
+)
+
+type DedicatedKeycloakClientScopeReconciler struct { // nolint
+	Keycloak kc.Keycloak
+	// References of the resource that couldn't be resolved by ReconcileIt, the actions
+	// depending on them are left out
+	UnresolvedReferences []string
+}
+
+func NewDedicatedKeycloakClientScopeReconciler(keycloak kc.Keycloak) *DedicatedKeycloakClientScopeReconciler {
+	return &DedicatedKeycloakClientScopeReconciler{
+		Keycloak: keycloak,
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) ReconcileIt(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.DesiredClusterState {
+	desired := common.DesiredClusterState{}
+	i.UnresolvedReferences = nil
+
+	desired.AddAction(i.pingKeycloak())
+	if cr.DeletionTimestamp != nil {
+		if state.ClientScope != nil {
+			desired.AddAction(i.getDeletedClientScopeState(state, cr))
+		}
+		return desired
+	}
+
+	if state.ClientScope == nil {
+		// protocol mappers are created together with the client scope
+		desired.AddAction(i.getCreatedClientScopeState(state, cr))
+	} else {
+		desired.AddAction(i.getUpdatedClientScopeState(state, cr))
+		i.ReconcileProtocolMappers(state, cr, &desired)
+	}
+
+	i.ReconcileScopeMappings(state, cr, &desired)
+
+	i.ReconcileRealmAssignment(state, cr, &desired)
+
+	return desired
+}
+
+// ReconcileProtocolMappers updates the protocol mappers one by one as they are ignored when updating the client scope
+func (i *DedicatedKeycloakClientScopeReconciler) ReconcileProtocolMappers(state *common.ClientScopeState, cr *kc.KeycloakClientScope, desired *common.DesiredClusterState) {
+	mappersDeleted, _ := model.ProtocolMapperDifferenceIntersection(state.ClientScope.ProtocolMappers, cr.Spec.ClientScope.ProtocolMappers)
+	for _, mapper := range mappersDeleted {
+		desired.AddAction(i.getDeletedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+
+	mappersNew, mappersMatching := model.ProtocolMapperDifferenceIntersection(cr.Spec.ClientScope.ProtocolMappers, state.ClientScope.ProtocolMappers)
+	for _, mapper := range mappersNew {
+		mapper.ID = ""
+		desired.AddAction(i.getCreatedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+
+	for _, mapper := range mappersMatching {
+		existing := model.FindProtocolMapperByName(state.ClientScope.ProtocolMappers, mapper.Name)
+		if model.ProtocolMapperEquals(mapper, *existing) {
+			continue
+		}
+		mapper.ID = existing.ID
+		desired.AddAction(i.getUpdatedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) ReconcileScopeMappings(state *common.ClientScopeState, cr *kc.KeycloakClientScope, desired *common.DesiredClusterState) {
+	desiredMappings, unresolved := resolveScopeMappings(&state.AvailableRoles, cr.Spec.ScopeMappings)
+	i.UnresolvedReferences = append(i.UnresolvedReferences, unresolved...)
+
+	mappingsNew := scopeMappingDifference(desiredMappings, state.ScopeMappings)
+	if mappingsNew.RealmMappings != nil {
+		desired.AddAction(i.getCreatedRealmScopeMappingsState(state, cr, &mappingsNew.RealmMappings))
+	}
+	for _, clientMappings := range mappingsNew.ClientMappings {
+		desired.AddAction(i.getCreatedClientScopeMappingsState(state, cr, clientMappings.DeepCopy()))
+	}
+
+	mappingsDeleted := scopeMappingDifference(state.ScopeMappings, desiredMappings)
+	if mappingsDeleted.RealmMappings != nil {
+		desired.AddAction(i.getDeletedRealmScopeMappingsState(state, cr, &mappingsDeleted.RealmMappings))
+	}
+	for _, clientMappings := range mappingsDeleted.ClientMappings {
+		desired.AddAction(i.getDeletedClientScopeMappingsState(state, cr, clientMappings.DeepCopy()))
+	}
+}
+
+// ReconcileRealmAssignment makes the client scope a default or optional client scope of the realm
+func (i *DedicatedKeycloakClientScopeReconciler) ReconcileRealmAssignment(state *common.ClientScopeState, cr *kc.KeycloakClientScope, desired *common.DesiredClusterState) {
+	isDefault := containsClientScopeName(state.RealmDefaultClientScopes, cr.Spec.ClientScope.Name)
+	isOptional := containsClientScopeName(state.RealmOptionalClientScopes, cr.Spec.ClientScope.Name)
+
+	switch cr.Spec.RealmAssignment {
+	case kc.ClientScopeRealmAssignmentDefault:
+		if isOptional {
+			desired.AddAction(i.getDeletedRealmOptionalClientScopeState(state, cr))
+		}
+		if !isDefault {
+			desired.AddAction(i.getCreatedRealmDefaultClientScopeState(state, cr))
+		}
+	case kc.ClientScopeRealmAssignmentOptional:
+		if isDefault {
+			desired.AddAction(i.getDeletedRealmDefaultClientScopeState(state, cr))
+		}
+		if !isOptional {
+			desired.AddAction(i.getCreatedRealmOptionalClientScopeState(state, cr))
+		}
+	default:
+		if isDefault {
+			desired.AddAction(i.getDeletedRealmDefaultClientScopeState(state, cr))
+		}
+		if isOptional {
+			desired.AddAction(i.getDeletedRealmOptionalClientScopeState(state, cr))
+		}
+	}
+}
+
+func containsClientScopeName(clientScopes []kc.KeycloakAPIClientScope, name string) bool {
+	for _, clientScope := range clientScopes {
+		if clientScope.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) pingKeycloak() common.ClusterAction {
+	return common.PingAction{
+		Msg: "check if keycloak is available",
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getCreatedClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.CreateClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("create client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getUpdatedClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.UpdateClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("update client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getDeletedClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.DeleteClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("delete client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getCreatedProtocolMapperState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.CreateClientScopeProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("create client scope protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.ClientScope.Name, mapper.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getUpdatedProtocolMapperState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.UpdateClientScopeProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("update client scope protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.ClientScope.Name, mapper.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getDeletedProtocolMapperState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.DeleteClientScopeProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("delete client scope protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.ClientScope.Name, mapper.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getCreatedRealmScopeMappingsState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mappings *[]kc.RoleRepresentation) common.ClusterAction {
+	return common.CreateClientScopeRealmScopeMappingsAction{
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("create client scope realm scope mappings for %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getDeletedRealmScopeMappingsState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mappings *[]kc.RoleRepresentation) common.ClusterAction {
+	return common.DeleteClientScopeRealmScopeMappingsAction{
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("delete client scope realm scope mappings for %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getCreatedClientScopeMappingsState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mappings *kc.ClientMappingsRepresentation) common.ClusterAction {
+	return common.CreateClientScopeClientScopeMappingsAction{
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("create client scope client scope mappings %v/%v => %v", cr.Namespace, cr.Spec.ClientScope.Name, mappings.Client),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getDeletedClientScopeMappingsState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mappings *kc.ClientMappingsRepresentation) common.ClusterAction {
+	return common.DeleteClientScopeClientScopeMappingsAction{
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("delete client scope client scope mappings %v/%v => %v", cr.Namespace, cr.Spec.ClientScope.Name, mappings.Client),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getCreatedRealmDefaultClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.UpdateRealmDefaultClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("add realm default client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getDeletedRealmDefaultClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.DeleteRealmDefaultClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("remove realm default client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getCreatedRealmOptionalClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.UpdateRealmOptionalClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("add realm optional client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *DedicatedKeycloakClientScopeReconciler) getDeletedRealmOptionalClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.DeleteRealmOptionalClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("remove realm optional client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+
+	"github.com/stretchr/testify/assert"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getDummyClientScope() *v1alpha1.KeycloakClientScope {
+	return &v1alpha1.KeycloakClientScope{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientScopeSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			ClientScope: &v1alpha1.KeycloakAPIClientScope{
+				Name:     "test",
+				Protocol: "openid-connect",
+				ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+					{Name: "unchanged", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"included.client.audience": "a"}},
+					{Name: "changed", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"included.client.audience": "new"}},
+					{Name: "new", ProtocolMapper: "oidc-audience-mapper"},
+				},
+			},
+		},
+	}
+}
+
+func getDummyClientScopeState() *common.ClientScopeState {
+	return &common.ClientScopeState{
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+}
+
+func TestKeycloakClientScopeReconciler_Test_Creating_ClientScope(t *testing.T) {
+	// given
+	cr := getDummyClientScope()
+	currentState := getDummyClientScopeState()
+
+	// when
+	reconciler := NewDedicatedKeycloakClientScopeReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// protocol mappers are created together with the client scope
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.CreateClientScopeAction{}, desiredState[1])
+}
+
+func TestKeycloakClientScopeReconciler_Test_Deleting_ClientScope(t *testing.T) {
+	// given
+	cr := getDummyClientScope()
+	cr.DeletionTimestamp = &v13.Time{}
+	currentState := getDummyClientScopeState()
+	currentState.ClientScope = &v1alpha1.KeycloakAPIClientScope{ID: "id", Name: "test"}
+
+	// when
+	reconciler := NewDedicatedKeycloakClientScopeReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.DeleteClientScopeAction{}, desiredState[1])
+}
+
+func TestKeycloakClientScopeReconciler_Test_Updating_ProtocolMappers(t *testing.T) {
+	// given
+	cr := getDummyClientScope()
+	currentState := getDummyClientScopeState()
+	currentState.ClientScope = &v1alpha1.KeycloakAPIClientScope{
+		ID:   "id",
+		Name: "test",
+		ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+			{ID: "unchangedID", Name: "unchanged", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"included.client.audience": "a"}},
+			{ID: "changedID", Name: "changed", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"included.client.audience": "old"}},
+			{ID: "deletedID", Name: "deleted", ProtocolMapper: "oidc-audience-mapper"},
+		},
+	}
+
+	// when
+	reconciler := NewDedicatedKeycloakClientScopeReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 5)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.UpdateClientScopeAction{}, desiredState[1])
+	assert.Equal(t, "deletedID", desiredState[2].(common.DeleteClientScopeProtocolMapperAction).Mapper.ID)
+	assert.Equal(t, "new", desiredState[3].(common.CreateClientScopeProtocolMapperAction).Mapper.Name)
+	assert.Equal(t, "", desiredState[3].(common.CreateClientScopeProtocolMapperAction).Mapper.ID)
+	assert.Equal(t, "changedID", desiredState[4].(common.UpdateClientScopeProtocolMapperAction).Mapper.ID)
+}
+
+func TestKeycloakClientScopeReconciler_Test_RealmAssignment(t *testing.T) {
+	// given
+	cr := getDummyClientScope()
+	cr.Spec.ClientScope.ProtocolMappers = nil
+	cr.Spec.RealmAssignment = v1alpha1.ClientScopeRealmAssignmentOptional
+	currentState := getDummyClientScopeState()
+	currentState.ClientScope = &v1alpha1.KeycloakAPIClientScope{ID: "id", Name: "test"}
+	currentState.RealmDefaultClientScopes = []v1alpha1.KeycloakAPIClientScope{{ID: "id", Name: "test"}}
+
+	// when
+	reconciler := NewDedicatedKeycloakClientScopeReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the client scope is moved from the default to the optional client scopes
+	assert.Len(t, desiredState, 4)
+	assert.IsType(t, common.DeleteRealmDefaultClientScopeAction{}, desiredState[2])
+	assert.IsType(t, common.UpdateRealmOptionalClientScopeAction{}, desiredState[3])
+
+	// when
+	cr.Spec.RealmAssignment = v1alpha1.ClientScopeRealmAssignmentNone
+	desiredState = reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, common.DeleteRealmDefaultClientScopeAction{}, desiredState[2])
+}
+
+func TestKeycloakClientScopeReconciler_Test_Unresolved_ScopeMappings(t *testing.T) {
+	// given
+	cr := getDummyClientScope()
+	cr.Spec.ScopeMappings = &v1alpha1.MappingsRepresentation{
+		RealmMappings: []v1alpha1.RoleRepresentation{{Name: "user"}, {Name: "missing"}},
+	}
+	currentState := getDummyClientScopeState()
+	currentState.ClientScope = &v1alpha1.KeycloakAPIClientScope{ID: "test-id", Name: "test"}
+	currentState.RealmRoles = []v1alpha1.RoleRepresentation{{ID: "user-id", Name: "user"}}
+
+	// when
+	reconciler := NewDedicatedKeycloakClientScopeReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the resolvable realm role is mapped nevertheless
+	assert.Equal(t, []string{"realm role missing"}, reconciler.UnresolvedReferences)
+	mappings := desiredState[len(desiredState)-1].(common.CreateClientScopeRealmScopeMappingsAction).Mappings
+	assert.Equal(t, []v1alpha1.RoleRepresentation{{ID: "user-id", Name: "user"}}, *mappings)
+}
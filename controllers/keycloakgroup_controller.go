@@ -0,0 +1,216 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+)
+
+// KeycloakGroupReconciler reconciles a KeycloakGroup object
+type KeycloakGroupReconciler struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Namespace the admin credentials of all ClusterKeycloaks are stored in
+	OperatorNamespace string
+	context           context.Context
+	cancel            context.CancelFunc
+	recorder          record.EventRecorder
+}
+
+var logKcg = logf.Log.WithName("controller_keycloakgroup")
+
+const (
+	GroupFinalizer      = "group.cleanup"
+	GroupControllerName = "keycloakgroup-controller"
+)
+
+// blank assignment to verify that KeycloakGroupReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &KeycloakGroupReconciler{}
+
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakgroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakgroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakgroups/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes the group in all realms selected by the KeycloakGroup.
+func (r *KeycloakGroupReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	_ = logf.FromContext(ctx)
+
+	reqLogger := logKcg.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling KeycloakGroup")
+
+	// Fetch the KeycloakGroup instance
+	instance := &kc.KeycloakGroup{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// The group may be applicable to multiple keycloak instances,
+	// process all of them
+	instances, err := common.GetMatchingRealmInstances(ctx, r.Client, r.OperatorNamespace, instance.Spec.RealmSelector, instance.Spec.ClusterRealmSelector)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+	logKcg.Info(fmt.Sprintf("found %v matching realm(s) for group %v/%v", len(instances), instance.Namespace, instance.Name))
+
+	for _, realmInstance := range instances {
+		err = r.reconcileGroupInRealm(ctx, instance, realmInstance.Realm, realmInstance.Keycloak)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+	}
+
+	return reconcile.Result{Requeue: false}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+}
+
+// reconcileGroupInRealm brings the group in the given realm of the given keycloak to the desired state
+func (r *KeycloakGroupReconciler) reconcileGroupInRealm(ctx context.Context, instance *kc.KeycloakGroup, realm kc.KeycloakRealm, keycloak kc.Keycloak) error {
+	// Get an authenticated keycloak api client for the instance
+	keycloakFactory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := keycloakFactory.AuthenticatedClient(ctx, keycloak, false)
+	if err != nil {
+		return err
+	}
+
+	// Compute the current state of the group
+	groupState := common.NewGroupState(ctx, realm.DeepCopy(), keycloak)
+
+	logKcg.Info(fmt.Sprintf("read group state for keycloak %v/%v, realm %v/%v, group %v/%v",
+		keycloak.Namespace,
+		keycloak.Name,
+		realm.Namespace,
+		realm.Name,
+		instance.Namespace,
+		instance.Name))
+
+	err = groupState.Read(ctx, instance, authenticated, r.Client)
+	if err != nil {
+		return err
+	}
+
+	// Figure out the actions to keep the group up to date with
+	// the desired state
+	reconciler := NewDedicatedKeycloakGroupReconciler(keycloak)
+	desiredState := reconciler.ReconcileIt(groupState, instance)
+	actionRunner := common.NewClusterAndKeycloakActionRunner(ctx, r.Client, r.Scheme, instance, authenticated)
+
+	// Run all actions to keep the group updated
+	return actionRunner.RunAll(desiredState)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeycloakGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	r.context = ctx
+	r.cancel = cancel
+	r.recorder = mgr.GetEventRecorderFor(GroupControllerName)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kc.KeycloakGroup{}).
+		WithOptions(controllerOptions()).
+		Complete(withErrorBackoff(mgr.GetClient(), &kc.KeycloakGroup{}, withReconcileTimeout(r)))
+}
+
+func (r *KeycloakGroupReconciler) manageSuccess(ctx context.Context, group *kc.KeycloakGroup, deleted bool) error {
+	group.Status.Ready = true
+	group.Status.Message = ""
+	group.Status.Phase = kc.PhaseReconciling
+
+	err := r.Client.Status().Update(ctx, group)
+	if err != nil {
+		logKcg.Error(err, "unable to update status")
+	}
+
+	// Finalizer already set?
+	finalizerExists := false
+	for _, finalizer := range group.Finalizers {
+		if finalizer == GroupFinalizer {
+			finalizerExists = true
+			break
+		}
+	}
+
+	// Resource created and finalizer exists: nothing to do
+	if !deleted && finalizerExists {
+		return nil
+	}
+
+	// Only the finalizers are patched, the group is reconciled in several realms and keycloaks, so the IDs of the group
+	// and its subgroups read into the spec are the ones of the last realm and must not be stored
+	patch := client.MergeFrom(group.DeepCopy())
+
+	// Resource created and finalizer does not exist: add finalizer
+	if !deleted && !finalizerExists {
+		group.Finalizers = append(group.Finalizers, GroupFinalizer)
+		logKcg.Info(fmt.Sprintf("added finalizer to keycloak group %v/%v",
+			group.Namespace,
+			group.Spec.Group.Name))
+
+		return r.Client.Patch(ctx, group, patch)
+	}
+
+	// Otherwise remove the finalizer
+	newFinalizers := []string{}
+	for _, finalizer := range group.Finalizers {
+		if finalizer == GroupFinalizer {
+			logKcg.Info(fmt.Sprintf("removed finalizer from keycloak group %v/%v",
+				group.Namespace,
+				group.Spec.Group.Name))
+
+			continue
+		}
+		newFinalizers = append(newFinalizers, finalizer)
+	}
+
+	group.Finalizers = newFinalizers
+	return r.Client.Patch(ctx, group, patch)
+}
+
+func (r *KeycloakGroupReconciler) ManageError(ctx context.Context, group *kc.KeycloakGroup, issue error) (reconcile.Result, error) {
+	r.recorder.Event(group, "Warning", "ProcessingError", issue.Error())
+
+	group.Status.Message = issue.Error()
+	group.Status.Ready = false
+	group.Status.Phase = kc.PhaseFailing
+
+	err := r.Client.Status().Update(ctx, group)
+	if err != nil {
+		logKcg.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{}, issue
+}
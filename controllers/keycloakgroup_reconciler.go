@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+)
+
+type DedicatedKeycloakGroupReconciler struct { // nolint
+	Keycloak kc.Keycloak
+}
+
+func NewDedicatedKeycloakGroupReconciler(keycloak kc.Keycloak) *DedicatedKeycloakGroupReconciler {
+	return &DedicatedKeycloakGroupReconciler{
+		Keycloak: keycloak,
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) ReconcileIt(state *common.GroupState, cr *kc.KeycloakGroup) common.DesiredClusterState {
+	desired := common.DesiredClusterState{}
+
+	desired.AddAction(i.pingKeycloak())
+	path := common.GroupPath("", cr.Spec.Group)
+	if cr.DeletionTimestamp != nil {
+		// subgroups are deleted together with the group
+		if state.Groups[path] != nil {
+			desired.AddAction(i.getDeletedGroupState(state, cr, cr.Spec.Group))
+		}
+		return desired
+	}
+
+	i.ReconcileGroup(state, cr, nil, cr.Spec.Group, path, &desired)
+
+	i.ReconcileDefaultGroup(state, cr, &desired)
+
+	return desired
+}
+
+// ReconcileGroup reconciles the group with the given path followed by its subgroups, subgroups that
+// exist in keycloak but are not part of the desired group are deleted
+func (i *DedicatedKeycloakGroupReconciler) ReconcileGroup(state *common.GroupState, cr *kc.KeycloakGroup, parentGroup, group *kc.KeycloakAPIGroup, path string, desired *common.DesiredClusterState) {
+	existing := state.Groups[path]
+	if existing == nil {
+		desired.AddAction(i.getCreatedGroupState(state, cr, group, parentGroup))
+	} else {
+		if !groupEquals(existing, group) {
+			desired.AddAction(i.getUpdatedGroupState(state, cr, group))
+		}
+		for index := range existing.SubGroups {
+			subGroup := existing.SubGroups[index]
+			if findSubGroupByName(group, subGroup.Name) == nil {
+				desired.AddAction(i.getDeletedGroupState(state, cr, subGroup.DeepCopy()))
+			}
+		}
+	}
+
+	i.ReconcileRoleMappings(state, cr, group, path, desired)
+
+	for index := range group.SubGroups {
+		subGroup := &group.SubGroups[index]
+		i.ReconcileGroup(state, cr, group, subGroup, common.GroupPath(path, subGroup), desired)
+	}
+}
+
+// ReconcileRoleMappings adds and removes the realm and client roles of the group, the roles are given by name
+func (i *DedicatedKeycloakGroupReconciler) ReconcileRoleMappings(state *common.GroupState, cr *kc.KeycloakGroup, group *kc.KeycloakAPIGroup, path string, desired *common.DesiredClusterState) {
+	desiredMappings := resolveRoleMappings(&state.AvailableRoles, group.RealmRoles, group.ClientRoles)
+
+	mappingsNew := scopeMappingDifference(desiredMappings, state.RoleMappings[path])
+	if len(mappingsNew.RealmMappings) > 0 {
+		desired.AddAction(i.getCreatedGroupRealmRoleMappingsState(state, cr, group, &mappingsNew.RealmMappings))
+	}
+	for _, clientID := range sortedClientMappingKeys(mappingsNew) {
+		clientMappings := mappingsNew.ClientMappings[clientID]
+		desired.AddAction(i.getCreatedGroupClientRoleMappingsState(state, cr, group, clientMappings.DeepCopy()))
+	}
+
+	mappingsDeleted := scopeMappingDifference(state.RoleMappings[path], desiredMappings)
+	if len(mappingsDeleted.RealmMappings) > 0 {
+		desired.AddAction(i.getDeletedGroupRealmRoleMappingsState(state, cr, group, &mappingsDeleted.RealmMappings))
+	}
+	for _, clientID := range sortedClientMappingKeys(mappingsDeleted) {
+		clientMappings := mappingsDeleted.ClientMappings[clientID]
+		desired.AddAction(i.getDeletedGroupClientRoleMappingsState(state, cr, group, clientMappings.DeepCopy()))
+	}
+}
+
+// ReconcileDefaultGroup adds the group to or removes it from the default groups of the realm
+func (i *DedicatedKeycloakGroupReconciler) ReconcileDefaultGroup(state *common.GroupState, cr *kc.KeycloakGroup, desired *common.DesiredClusterState) {
+	path := common.GroupPath("", cr.Spec.Group)
+	isDefault := false
+	for _, defaultGroup := range state.DefaultGroups {
+		if defaultGroup.Path == path {
+			isDefault = true
+			break
+		}
+	}
+
+	if cr.Spec.DefaultGroup && !isDefault {
+		desired.AddAction(i.getCreatedRealmDefaultGroupState(state, cr))
+	}
+	if !cr.Spec.DefaultGroup && isDefault {
+		desired.AddAction(i.getDeletedRealmDefaultGroupState(state, cr))
+	}
+}
+
+// resolveRoleMappings resolves the realm and client roles given by name to the roles of the realm.
+// Roles that can't be resolved are added without ID, Keycloak will reject them and the
+// resource is reconciled again
+func resolveRoleMappings(roles *common.AvailableRoles, realmRoles []string, clientRoles map[string][]string) *kc.MappingsRepresentation {
+	mappings := &kc.MappingsRepresentation{ClientMappings: map[string]kc.ClientMappingsRepresentation{}}
+
+	for _, name := range realmRoles {
+		mappings.RealmMappings = append(mappings.RealmMappings, findRoleByName(roles.RealmRoles, name, kc.RoleRepresentation{Name: name}))
+	}
+
+	for clientID, names := range clientRoles {
+		clientMappings := kc.ClientMappingsRepresentation{Client: clientID}
+		if keycloakClient := roles.GetClientByClientID(clientID); keycloakClient != nil {
+			clientMappings.ID = keycloakClient.ID
+		}
+		for _, name := range names {
+			clientMappings.Mappings = append(clientMappings.Mappings, findRoleByName(roles.ClientRoles[clientID], name, kc.RoleRepresentation{Name: name}))
+		}
+		mappings.ClientMappings[clientID] = clientMappings
+	}
+
+	return mappings
+}
+
+// sortedClientMappingKeys returns the clientIds of the client mappings in a stable order to get the same actions on every run
+func sortedClientMappingKeys(mappings *kc.MappingsRepresentation) []string {
+	clientIDs := make([]string, 0, len(mappings.ClientMappings))
+	for clientID := range mappings.ClientMappings {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+	return clientIDs
+}
+
+func findSubGroupByName(group *kc.KeycloakAPIGroup, name string) *kc.KeycloakAPIGroup {
+	for index := range group.SubGroups {
+		if group.SubGroups[index].Name == name {
+			return &group.SubGroups[index]
+		}
+	}
+	return nil
+}
+
+func groupEquals(existing *kc.KeycloakAPIGroup, desired *kc.KeycloakAPIGroup) bool {
+	if len(existing.Attributes) == 0 && len(desired.Attributes) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(existing.Attributes, desired.Attributes)
+}
+
+func (i *DedicatedKeycloakGroupReconciler) pingKeycloak() common.ClusterAction {
+	return common.PingAction{
+		Msg: "check if keycloak is available",
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) getCreatedGroupState(state *common.GroupState, cr *kc.KeycloakGroup, group, parentGroup *kc.KeycloakAPIGroup) common.ClusterAction {
+	return common.CreateGroupAction{
+		Group:       group,
+		ParentGroup: parentGroup,
+		Ref:         cr,
+		Realm:       state.Realm.Spec.Realm.Realm,
+		Msg:         fmt.Sprintf("create group %v/%v", cr.Namespace, group.Name),
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) getUpdatedGroupState(state *common.GroupState, cr *kc.KeycloakGroup, group *kc.KeycloakAPIGroup) common.ClusterAction {
+	return common.UpdateGroupAction{
+		Group: group,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("update group %v/%v", cr.Namespace, group.Name),
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) getDeletedGroupState(state *common.GroupState, cr *kc.KeycloakGroup, group *kc.KeycloakAPIGroup) common.ClusterAction {
+	return common.DeleteGroupAction{
+		Group: group,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("delete group %v/%v", cr.Namespace, group.Name),
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) getCreatedGroupRealmRoleMappingsState(state *common.GroupState, cr *kc.KeycloakGroup, group *kc.KeycloakAPIGroup, mappings *[]kc.RoleRepresentation) common.ClusterAction {
+	return common.CreateGroupRealmRoleMappingsAction{
+		Group:    group,
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("create group realm role mappings for %v/%v", cr.Namespace, group.Name),
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) getDeletedGroupRealmRoleMappingsState(state *common.GroupState, cr *kc.KeycloakGroup, group *kc.KeycloakAPIGroup, mappings *[]kc.RoleRepresentation) common.ClusterAction {
+	return common.DeleteGroupRealmRoleMappingsAction{
+		Group:    group,
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("delete group realm role mappings for %v/%v", cr.Namespace, group.Name),
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) getCreatedGroupClientRoleMappingsState(state *common.GroupState, cr *kc.KeycloakGroup, group *kc.KeycloakAPIGroup, mappings *kc.ClientMappingsRepresentation) common.ClusterAction {
+	return common.CreateGroupClientRoleMappingsAction{
+		Group:    group,
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("create group client role mappings %v/%v => %v", cr.Namespace, group.Name, mappings.Client),
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) getDeletedGroupClientRoleMappingsState(state *common.GroupState, cr *kc.KeycloakGroup, group *kc.KeycloakAPIGroup, mappings *kc.ClientMappingsRepresentation) common.ClusterAction {
+	return common.DeleteGroupClientRoleMappingsAction{
+		Group:    group,
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("delete group client role mappings %v/%v => %v", cr.Namespace, group.Name, mappings.Client),
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) getCreatedRealmDefaultGroupState(state *common.GroupState, cr *kc.KeycloakGroup) common.ClusterAction {
+	return common.UpdateRealmDefaultGroupAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("add realm default group %v/%v", cr.Namespace, cr.Spec.Group.Name),
+	}
+}
+
+func (i *DedicatedKeycloakGroupReconciler) getDeletedRealmDefaultGroupState(state *common.GroupState, cr *kc.KeycloakGroup) common.ClusterAction {
+	return common.DeleteRealmDefaultGroupAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("remove realm default group %v/%v", cr.Namespace, cr.Spec.Group.Name),
+	}
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+
+	"github.com/stretchr/testify/assert"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getDummyGroup() *v1alpha1.KeycloakGroup {
+	return &v1alpha1.KeycloakGroup{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakGroupSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Group: &v1alpha1.KeycloakAPIGroup{
+				Name:        "parent",
+				RealmRoles:  []string{"realm-a"},
+				ClientRoles: map[string][]string{"client": {"client-a"}},
+				SubGroups: []v1alpha1.KeycloakAPIGroup{
+					{Name: "child"},
+				},
+			},
+		},
+	}
+}
+
+func getDummyGroupState() *common.GroupState {
+	return &common.GroupState{
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		Groups:       map[string]*v1alpha1.KeycloakAPIGroup{},
+		RoleMappings: map[string]*v1alpha1.MappingsRepresentation{},
+		AvailableRoles: common.AvailableRoles{
+			RealmRoles: []v1alpha1.RoleRepresentation{
+				{ID: "realm-a-id", Name: "realm-a"},
+				{ID: "realm-b-id", Name: "realm-b"},
+			},
+			Clients: []*v1alpha1.KeycloakAPIClient{
+				{ID: "client-id", ClientID: "client"},
+			},
+			ClientRoles: map[string][]v1alpha1.RoleRepresentation{
+				"client": {
+					{ID: "client-a-id", Name: "client-a"},
+				},
+			},
+		},
+	}
+}
+
+func TestKeycloakGroupReconciler_Test_Creating_Group(t *testing.T) {
+	// given
+	cr := getDummyGroup()
+	cr.Spec.DefaultGroup = true
+	currentState := getDummyGroupState()
+
+	// when
+	reconciler := NewDedicatedKeycloakGroupReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the parent is created before the role mappings and the subgroup
+	assert.Len(t, desiredState, 6)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.Nil(t, desiredState[1].(common.CreateGroupAction).ParentGroup)
+	assert.Equal(t, "realm-a-id", (*desiredState[2].(common.CreateGroupRealmRoleMappingsAction).Mappings)[0].ID)
+	assert.Equal(t, "client-id", desiredState[3].(common.CreateGroupClientRoleMappingsAction).Mappings.ID)
+	assert.Equal(t, cr.Spec.Group, desiredState[4].(common.CreateGroupAction).ParentGroup)
+	assert.Equal(t, "child", desiredState[4].(common.CreateGroupAction).Group.Name)
+	assert.IsType(t, common.UpdateRealmDefaultGroupAction{}, desiredState[5])
+}
+
+func TestKeycloakGroupReconciler_Test_Updating_Group(t *testing.T) {
+	// given
+	cr := getDummyGroup()
+	cr.Spec.Group.Attributes = map[string][]string{"department": {"sales"}}
+	currentState := getDummyGroupState()
+	currentState.Groups["/parent"] = &v1alpha1.KeycloakAPIGroup{
+		ID:   "parent-id",
+		Name: "parent",
+		Path: "/parent",
+		SubGroups: []v1alpha1.KeycloakAPIGroup{
+			{ID: "child-id", Name: "child", Path: "/parent/child"},
+			{ID: "removed-id", Name: "removed", Path: "/parent/removed"},
+		},
+	}
+	currentState.Groups["/parent/child"] = &v1alpha1.KeycloakAPIGroup{ID: "child-id", Name: "child", Path: "/parent/child"}
+	currentState.RoleMappings["/parent"] = &v1alpha1.MappingsRepresentation{
+		RealmMappings: []v1alpha1.RoleRepresentation{
+			{ID: "realm-a-id", Name: "realm-a"},
+			{ID: "realm-b-id", Name: "realm-b"},
+		},
+		ClientMappings: map[string]v1alpha1.ClientMappingsRepresentation{
+			"client": {ID: "client-id", Client: "client", Mappings: []v1alpha1.RoleRepresentation{{ID: "client-a-id", Name: "client-a"}}},
+		},
+	}
+	currentState.DefaultGroups = []v1alpha1.KeycloakAPIGroup{{ID: "parent-id", Name: "parent", Path: "/parent"}}
+
+	// when
+	reconciler := NewDedicatedKeycloakGroupReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 5)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.UpdateGroupAction{}, desiredState[1])
+	assert.Equal(t, "removed-id", desiredState[2].(common.DeleteGroupAction).Group.ID)
+	assert.Equal(t, "realm-b-id", (*desiredState[3].(common.DeleteGroupRealmRoleMappingsAction).Mappings)[0].ID)
+	assert.IsType(t, common.DeleteRealmDefaultGroupAction{}, desiredState[4])
+}
+
+func TestKeycloakGroupReconciler_Test_Deleting_Group(t *testing.T) {
+	// given
+	cr := getDummyGroup()
+	cr.DeletionTimestamp = &v13.Time{}
+	currentState := getDummyGroupState()
+	currentState.Groups["/parent"] = &v1alpha1.KeycloakAPIGroup{ID: "parent-id", Name: "parent"}
+
+	// when
+	reconciler := NewDedicatedKeycloakGroupReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// subgroups are deleted together with the group
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.DeleteGroupAction{}, desiredState[1])
+}
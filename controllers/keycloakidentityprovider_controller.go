@@ -0,0 +1,246 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+)
+
+// KeycloakIdentityProviderReconciler reconciles a KeycloakIdentityProvider object
+type KeycloakIdentityProviderReconciler struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Namespace the admin credentials of all ClusterKeycloaks are stored in
+	OperatorNamespace string
+	context           context.Context
+	cancel            context.CancelFunc
+	recorder          record.EventRecorder
+}
+
+var logKcip = logf.Log.WithName("controller_keycloakidentityprovider")
+
+const (
+	IdentityProviderFinalizer      = "identityprovider.cleanup"
+	IdentityProviderControllerName = "keycloakidentityprovider-controller"
+)
+
+// blank assignment to verify that KeycloakIdentityProviderReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &KeycloakIdentityProviderReconciler{}
+
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakidentityproviders,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakidentityproviders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakidentityproviders/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile creates, updates and deletes the identity provider in all realms selected by the KeycloakIdentityProvider.
+func (r *KeycloakIdentityProviderReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	_ = logf.FromContext(ctx)
+
+	reqLogger := logKcip.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling KeycloakIdentityProvider")
+
+	// Fetch the KeycloakIdentityProvider instance
+	instance := &kc.KeycloakIdentityProvider{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// The identity provider may be applicable to multiple keycloak instances,
+	// process all of them
+	instances, err := common.GetMatchingRealmInstances(ctx, r.Client, r.OperatorNamespace, instance.Spec.RealmSelector, instance.Spec.ClusterRealmSelector)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+	logKcip.Info(fmt.Sprintf("found %v matching realm(s) for identity provider %v/%v", len(instances), instance.Namespace, instance.Name))
+
+	clientSecretVersion := ""
+	for _, realmInstance := range instances {
+		clientSecretVersion, err = r.reconcileIdentityProviderInRealm(ctx, instance, realmInstance.Realm, realmInstance.Keycloak)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+	}
+
+	// the client secret is sent to all realms, so a changed secret isn't sent again
+	instance.Status.ClientSecretVersion = clientSecretVersion
+
+	return reconcile.Result{Requeue: false}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+}
+
+// reconcileIdentityProviderInRealm brings the identity provider in the given realm of the given keycloak to the desired state
+// and returns the resource version of the client secret
+func (r *KeycloakIdentityProviderReconciler) reconcileIdentityProviderInRealm(ctx context.Context, instance *kc.KeycloakIdentityProvider, realm kc.KeycloakRealm, keycloak kc.Keycloak) (string, error) {
+	// Get an authenticated keycloak api client for the instance
+	keycloakFactory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := keycloakFactory.AuthenticatedClient(ctx, keycloak, false)
+	if err != nil {
+		return "", err
+	}
+
+	// Compute the current state of the identity provider
+	identityProviderState := common.NewIdentityProviderState(ctx, realm.DeepCopy(), keycloak)
+
+	logKcip.Info(fmt.Sprintf("read identity provider state for keycloak %v/%v, realm %v/%v, identity provider %v/%v",
+		keycloak.Namespace,
+		keycloak.Name,
+		realm.Namespace,
+		realm.Name,
+		instance.Namespace,
+		instance.Name))
+
+	err = identityProviderState.Read(ctx, instance, authenticated, r.Client)
+	if err != nil {
+		return "", err
+	}
+
+	// Figure out the actions to keep the identity provider up to date with
+	// the desired state
+	reconciler := NewDedicatedKeycloakIdentityProviderReconciler(keycloak)
+	desiredState := reconciler.ReconcileIt(identityProviderState, instance)
+	actionRunner := common.NewClusterAndKeycloakActionRunner(ctx, r.Client, r.Scheme, instance, authenticated)
+
+	// Run all actions to keep the identity provider updated
+	return identityProviderState.ClientSecretVersion, actionRunner.RunAll(desiredState)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeycloakIdentityProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	r.context = ctx
+	r.cancel = cancel
+	r.recorder = mgr.GetEventRecorderFor(IdentityProviderControllerName)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kc.KeycloakIdentityProvider{}).
+		WithOptions(controllerOptions()).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.identityProvidersForSecret)).
+		Complete(withErrorBackoff(mgr.GetClient(), &kc.KeycloakIdentityProvider{}, withReconcileTimeout(r)))
+}
+
+// identityProvidersForSecret returns the identity providers that take their client secret from the given secret
+func (r *KeycloakIdentityProviderReconciler) identityProvidersForSecret(secret client.Object) []reconcile.Request {
+	identityProviders := &kc.KeycloakIdentityProviderList{}
+	err := r.Client.List(r.context, identityProviders, client.InNamespace(secret.GetNamespace()))
+	if err != nil {
+		logKcip.Error(err, "unable to list identity providers")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, identityProvider := range identityProviders.Items {
+		if identityProvider.Spec.ClientSecret != nil && identityProvider.Spec.ClientSecret.Name == secret.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: identityProvider.Namespace,
+					Name:      identityProvider.Name,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+func (r *KeycloakIdentityProviderReconciler) manageSuccess(ctx context.Context, identityProvider *kc.KeycloakIdentityProvider, deleted bool) error {
+	identityProvider.Status.Ready = true
+	identityProvider.Status.Message = ""
+	identityProvider.Status.Phase = kc.PhaseReconciling
+
+	err := r.Client.Status().Update(ctx, identityProvider)
+	if err != nil {
+		logKcip.Error(err, "unable to update status")
+	}
+
+	// Finalizer already set?
+	finalizerExists := false
+	for _, finalizer := range identityProvider.Finalizers {
+		if finalizer == IdentityProviderFinalizer {
+			finalizerExists = true
+			break
+		}
+	}
+
+	// Resource created and finalizer exists: nothing to do
+	if !deleted && finalizerExists {
+		return nil
+	}
+
+	// Resource created and finalizer does not exist: add finalizer
+	if !deleted && !finalizerExists {
+		identityProvider.Finalizers = append(identityProvider.Finalizers, IdentityProviderFinalizer)
+		logKcip.Info(fmt.Sprintf("added finalizer to keycloak identity provider %v/%v",
+			identityProvider.Namespace,
+			identityProvider.Spec.IdentityProvider.Alias))
+
+		return r.Client.Update(ctx, identityProvider)
+	}
+
+	// Otherwise remove the finalizer
+	newFinalizers := []string{}
+	for _, finalizer := range identityProvider.Finalizers {
+		if finalizer == IdentityProviderFinalizer {
+			logKcip.Info(fmt.Sprintf("removed finalizer from keycloak identity provider %v/%v",
+				identityProvider.Namespace,
+				identityProvider.Spec.IdentityProvider.Alias))
+
+			continue
+		}
+		newFinalizers = append(newFinalizers, finalizer)
+	}
+
+	identityProvider.Finalizers = newFinalizers
+	return r.Client.Update(ctx, identityProvider)
+}
+
+func (r *KeycloakIdentityProviderReconciler) ManageError(ctx context.Context, identityProvider *kc.KeycloakIdentityProvider, issue error) (reconcile.Result, error) {
+	r.recorder.Event(identityProvider, "Warning", "ProcessingError", issue.Error())
+
+	identityProvider.Status.Message = issue.Error()
+	identityProvider.Status.Ready = false
+	identityProvider.Status.Phase = kc.PhaseFailing
+
+	err := r.Client.Status().Update(ctx, identityProvider)
+	if err != nil {
+		logKcip.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{}, issue
+}
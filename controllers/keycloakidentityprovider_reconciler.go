@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"fmt"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+)
+
+type DedicatedKeycloakIdentityProviderReconciler struct { // nolint
+	Keycloak kc.Keycloak
+}
+
+func NewDedicatedKeycloakIdentityProviderReconciler(keycloak kc.Keycloak) *DedicatedKeycloakIdentityProviderReconciler {
+	return &DedicatedKeycloakIdentityProviderReconciler{
+		Keycloak: keycloak,
+	}
+}
+
+func (i *DedicatedKeycloakIdentityProviderReconciler) ReconcileIt(state *common.IdentityProviderState, cr *kc.KeycloakIdentityProvider) common.DesiredClusterState {
+	desired := common.DesiredClusterState{}
+
+	desired.AddAction(i.pingKeycloak())
+	if cr.DeletionTimestamp != nil {
+		// mappers are deleted together with the identity provider
+		if state.IdentityProvider != nil {
+			desired.AddAction(i.getDeletedIdentityProviderState(state, cr))
+		}
+		return desired
+	}
+
+	identityProvider := desiredIdentityProvider(state, cr)
+	if state.IdentityProvider == nil {
+		desired.AddAction(i.getCreatedIdentityProviderState(state, cr, identityProvider))
+	} else if !identityProviderEquals(state.IdentityProvider, identityProvider) || state.ClientSecretVersion != cr.Status.ClientSecretVersion {
+		// Keycloak doesn't return the client secret, a changed secret is detected by its resource version
+		desired.AddAction(i.getUpdatedIdentityProviderState(state, cr, identityProvider))
+	}
+
+	i.ReconcileMappers(state, cr, &desired)
+
+	return desired
+}
+
+// ReconcileMappers creates, updates and deletes the mappers of the identity provider, mappers are matched by name
+func (i *DedicatedKeycloakIdentityProviderReconciler) ReconcileMappers(state *common.IdentityProviderState, cr *kc.KeycloakIdentityProvider, desired *common.DesiredClusterState) {
+	for index := range state.Mappers {
+		existing := state.Mappers[index]
+		if findIdentityProviderMapperByName(cr.Spec.Mappers, existing.Name) == nil {
+			desired.AddAction(i.getDeletedIdentityProviderMapperState(state, cr, existing.DeepCopy()))
+		}
+	}
+
+	for index := range cr.Spec.Mappers {
+		mapper := cr.Spec.Mappers[index].DeepCopy()
+		mapper.IdentityProviderAlias = cr.Spec.IdentityProvider.Alias
+
+		existing := findIdentityProviderMapperByName(state.Mappers, mapper.Name)
+		if existing == nil {
+			mapper.ID = ""
+			desired.AddAction(i.getCreatedIdentityProviderMapperState(state, cr, mapper))
+			continue
+		}
+
+		if existing.IdentityProviderMapper != mapper.IdentityProviderMapper || !configContains(existing.Config, mapper.Config) {
+			mapper.ID = existing.ID
+			desired.AddAction(i.getUpdatedIdentityProviderMapperState(state, cr, mapper))
+		}
+	}
+}
+
+// desiredIdentityProvider returns the identity provider that is sent to Keycloak. It is a copy of the identity
+// provider of the resource with the client secret, so the client secret never ends up in the resource.
+func desiredIdentityProvider(state *common.IdentityProviderState, cr *kc.KeycloakIdentityProvider) *kc.KeycloakAPIIdentityProvider {
+	identityProvider := cr.Spec.IdentityProvider.DeepCopy()
+	if state.IdentityProvider != nil {
+		identityProvider.InternalID = state.IdentityProvider.InternalID
+	}
+	if state.ClientSecret != "" {
+		if identityProvider.Config == nil {
+			identityProvider.Config = map[string]string{}
+		}
+		identityProvider.Config[kc.IdentityProviderClientSecretConfigKey] = state.ClientSecret
+	}
+	return identityProvider
+}
+
+// identityProviderEquals compares the identity provider in Keycloak with the desired one. Config values that
+// are not given in the resource, e.g. defaults added by Keycloak, and the masked client secret are ignored.
+func identityProviderEquals(existing *kc.KeycloakAPIIdentityProvider, desired *kc.KeycloakAPIIdentityProvider) bool {
+	if existing.DisplayName != desired.DisplayName ||
+		existing.ProviderID != desired.ProviderID ||
+		existing.Enabled != desired.Enabled ||
+		existing.TrustEmail != desired.TrustEmail ||
+		existing.StoreToken != desired.StoreToken ||
+		existing.AddReadTokenRoleOnCreate != desired.AddReadTokenRoleOnCreate ||
+		existing.LinkOnly != desired.LinkOnly ||
+		existing.PostBrokerLoginFlowAlias != desired.PostBrokerLoginFlowAlias {
+		return false
+	}
+	if desired.FirstBrokerLoginFlowAlias != "" && existing.FirstBrokerLoginFlowAlias != desired.FirstBrokerLoginFlowAlias {
+		return false
+	}
+	return configContains(existing.Config, desired.Config, kc.IdentityProviderClientSecretConfigKey)
+}
+
+// configContains checks that all values of the desired config are set in the existing config
+func configContains(existing, desired map[string]string, ignoredKeys ...string) bool {
+	for key, value := range desired {
+		if containsString(ignoredKeys, key) {
+			continue
+		}
+		if existingValue, ok := existing[key]; !ok || existingValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func findIdentityProviderMapperByName(mappers []kc.KeycloakAPIIdentityProviderMapper, name string) *kc.KeycloakAPIIdentityProviderMapper {
+	for index := range mappers {
+		if mappers[index].Name == name {
+			return &mappers[index]
+		}
+	}
+	return nil
+}
+
+func (i *DedicatedKeycloakIdentityProviderReconciler) pingKeycloak() common.ClusterAction {
+	return common.PingAction{
+		Msg: "check if keycloak is available",
+	}
+}
+
+func (i *DedicatedKeycloakIdentityProviderReconciler) getCreatedIdentityProviderState(state *common.IdentityProviderState, cr *kc.KeycloakIdentityProvider, identityProvider *kc.KeycloakAPIIdentityProvider) common.ClusterAction {
+	return common.CreateIdentityProviderAction{
+		IdentityProvider: identityProvider,
+		Ref:              cr,
+		Realm:            state.Realm.Spec.Realm.Realm,
+		Msg:              fmt.Sprintf("create identity provider %v/%v", cr.Namespace, cr.Spec.IdentityProvider.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakIdentityProviderReconciler) getUpdatedIdentityProviderState(state *common.IdentityProviderState, cr *kc.KeycloakIdentityProvider, identityProvider *kc.KeycloakAPIIdentityProvider) common.ClusterAction {
+	return common.UpdateIdentityProviderAction{
+		IdentityProvider: identityProvider,
+		Ref:              cr,
+		Realm:            state.Realm.Spec.Realm.Realm,
+		Msg:              fmt.Sprintf("update identity provider %v/%v", cr.Namespace, cr.Spec.IdentityProvider.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakIdentityProviderReconciler) getDeletedIdentityProviderState(state *common.IdentityProviderState, cr *kc.KeycloakIdentityProvider) common.ClusterAction {
+	return common.DeleteIdentityProviderAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("delete identity provider %v/%v", cr.Namespace, cr.Spec.IdentityProvider.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakIdentityProviderReconciler) getCreatedIdentityProviderMapperState(state *common.IdentityProviderState, cr *kc.KeycloakIdentityProvider, mapper *kc.KeycloakAPIIdentityProviderMapper) common.ClusterAction {
+	return common.CreateIdentityProviderMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("create mapper %v of identity provider %v/%v", mapper.Name, cr.Namespace, cr.Spec.IdentityProvider.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakIdentityProviderReconciler) getUpdatedIdentityProviderMapperState(state *common.IdentityProviderState, cr *kc.KeycloakIdentityProvider, mapper *kc.KeycloakAPIIdentityProviderMapper) common.ClusterAction {
+	return common.UpdateIdentityProviderMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("update mapper %v of identity provider %v/%v", mapper.Name, cr.Namespace, cr.Spec.IdentityProvider.Alias),
+	}
+}
+
+func (i *DedicatedKeycloakIdentityProviderReconciler) getDeletedIdentityProviderMapperState(state *common.IdentityProviderState, cr *kc.KeycloakIdentityProvider, mapper *kc.KeycloakAPIIdentityProviderMapper) common.ClusterAction {
+	return common.DeleteIdentityProviderMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("delete mapper %v of identity provider %v/%v", mapper.Name, cr.Namespace, cr.Spec.IdentityProvider.Alias),
+	}
+}
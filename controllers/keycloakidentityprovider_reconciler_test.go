@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+
+	"github.com/stretchr/testify/assert"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getDummyIdentityProvider() *v1alpha1.KeycloakIdentityProvider {
+	return &v1alpha1.KeycloakIdentityProvider{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakIdentityProviderSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			IdentityProvider: &v1alpha1.KeycloakAPIIdentityProvider{
+				Alias:      "azure-ad",
+				ProviderID: "oidc",
+				Enabled:    true,
+				Config: map[string]string{
+					"clientId": "client",
+				},
+			},
+			Mappers: []v1alpha1.KeycloakAPIIdentityProviderMapper{
+				{
+					Name:                   "department",
+					IdentityProviderMapper: "oidc-user-attribute-idp-mapper",
+					Config:                 map[string]string{"claim": "department"},
+				},
+			},
+		},
+	}
+}
+
+func getDummyIdentityProviderState() *common.IdentityProviderState {
+	return &common.IdentityProviderState{
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+}
+
+func TestKeycloakIdentityProviderReconciler_Test_Creating_IdentityProvider(t *testing.T) {
+	// given
+	cr := getDummyIdentityProvider()
+	currentState := getDummyIdentityProviderState()
+	currentState.ClientSecret = "secret"
+
+	// when
+	reconciler := NewDedicatedKeycloakIdentityProviderReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the client secret is sent to keycloak but not stored in the resource
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	identityProvider := desiredState[1].(common.CreateIdentityProviderAction).IdentityProvider
+	assert.Equal(t, "secret", identityProvider.Config[v1alpha1.IdentityProviderClientSecretConfigKey])
+	assert.NotContains(t, cr.Spec.IdentityProvider.Config, v1alpha1.IdentityProviderClientSecretConfigKey)
+	assert.Equal(t, "azure-ad", desiredState[2].(common.CreateIdentityProviderMapperAction).Mapper.IdentityProviderAlias)
+}
+
+func TestKeycloakIdentityProviderReconciler_Test_Unchanged_IdentityProvider(t *testing.T) {
+	// given
+	cr := getDummyIdentityProvider()
+	cr.Status.ClientSecretVersion = "1"
+	currentState := getDummyIdentityProviderState()
+	currentState.ClientSecret = "secret"
+	currentState.ClientSecretVersion = "1"
+	currentState.IdentityProvider = &v1alpha1.KeycloakAPIIdentityProvider{
+		Alias:                     "azure-ad",
+		InternalID:                "internal-id",
+		ProviderID:                "oidc",
+		Enabled:                   true,
+		FirstBrokerLoginFlowAlias: "first broker login",
+		Config: map[string]string{
+			"clientId":     "client",
+			"clientSecret": "**********",
+			"syncMode":     "IMPORT",
+		},
+	}
+	currentState.Mappers = []v1alpha1.KeycloakAPIIdentityProviderMapper{
+		{
+			ID:                     "mapper-id",
+			Name:                   "department",
+			IdentityProviderAlias:  "azure-ad",
+			IdentityProviderMapper: "oidc-user-attribute-idp-mapper",
+			Config:                 map[string]string{"claim": "department", "syncMode": "INHERIT"},
+		},
+	}
+
+	// when
+	reconciler := NewDedicatedKeycloakIdentityProviderReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 1)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+}
+
+func TestKeycloakIdentityProviderReconciler_Test_Updating_IdentityProvider(t *testing.T) {
+	// given
+	cr := getDummyIdentityProvider()
+	cr.Status.ClientSecretVersion = "1"
+	currentState := getDummyIdentityProviderState()
+	currentState.ClientSecret = "new-secret"
+	currentState.ClientSecretVersion = "2"
+	currentState.IdentityProvider = &v1alpha1.KeycloakAPIIdentityProvider{
+		Alias:      "azure-ad",
+		InternalID: "internal-id",
+		ProviderID: "oidc",
+		Enabled:    true,
+		Config:     map[string]string{"clientId": "client"},
+	}
+	currentState.Mappers = []v1alpha1.KeycloakAPIIdentityProviderMapper{
+		{
+			ID:                     "mapper-id",
+			Name:                   "department",
+			IdentityProviderAlias:  "azure-ad",
+			IdentityProviderMapper: "oidc-user-attribute-idp-mapper",
+			Config:                 map[string]string{"claim": "dept"},
+		},
+		{
+			ID:                     "obsolete-id",
+			Name:                   "obsolete",
+			IdentityProviderAlias:  "azure-ad",
+			IdentityProviderMapper: "hardcoded-role-idp-mapper",
+		},
+	}
+
+	// when
+	reconciler := NewDedicatedKeycloakIdentityProviderReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the changed client secret triggers an update of the identity provider
+	assert.Len(t, desiredState, 4)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	identityProvider := desiredState[1].(common.UpdateIdentityProviderAction).IdentityProvider
+	assert.Equal(t, "internal-id", identityProvider.InternalID)
+	assert.Equal(t, "new-secret", identityProvider.Config[v1alpha1.IdentityProviderClientSecretConfigKey])
+	assert.Equal(t, "obsolete-id", desiredState[2].(common.DeleteIdentityProviderMapperAction).Mapper.ID)
+	assert.Equal(t, "mapper-id", desiredState[3].(common.UpdateIdentityProviderMapperAction).Mapper.ID)
+}
+
+func TestKeycloakIdentityProviderReconciler_Test_Delete_IdentityProvider(t *testing.T) {
+	// given
+	cr := getDummyIdentityProvider()
+	cr.DeletionTimestamp = &v13.Time{}
+	currentState := getDummyIdentityProviderState()
+	currentState.IdentityProvider = &v1alpha1.KeycloakAPIIdentityProvider{Alias: "azure-ad"}
+
+	// when
+	reconciler := NewDedicatedKeycloakIdentityProviderReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.DeleteIdentityProviderAction{}, desiredState[1])
+}
@@ -19,18 +19,13 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
-	"github.com/pkg/errors"
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 
 	corev1 "k8s.io/api/core/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"k8s.io/client-go/tools/record"
 
@@ -52,9 +47,8 @@ type KeycloakRealmReconciler struct {
 }
 
 const (
-	RealmFinalizer         = "realm.cleanup"
-	RealmRequeueDelayError = 60 * time.Second
-	RealmControllerName    = "controller_keycloakrealm"
+	RealmFinalizer      = "realm.cleanup"
+	RealmControllerName = "controller_keycloakrealm"
 )
 
 var logKcr = logf.Log.WithName(RealmControllerName)
@@ -93,7 +87,7 @@ func (r *KeycloakRealmReconciler) Reconcile(ctx context.Context, request ctrl.Re
 
 	// Fetch the KeycloakRealm instance
 	instance := &kc.KeycloakRealm{}
-	err := r.Client.Get(context.TODO(), request.NamespacedName, instance)
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
 	if err != nil {
 		if kubeerrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -106,7 +100,7 @@ func (r *KeycloakRealmReconciler) Reconcile(ctx context.Context, request ctrl.Re
 	}
 
 	if instance.Spec.Unmanaged {
-		return reconcile.Result{Requeue: false}, r.manageSuccess(instance, instance.DeletionTimestamp != nil)
+		return reconcile.Result{Requeue: false}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
 	}
 
 	// If no selector is set we can't figure out which Keycloak instance this realm should
@@ -116,9 +110,9 @@ func (r *KeycloakRealmReconciler) Reconcile(ctx context.Context, request ctrl.Re
 		return reconcile.Result{Requeue: false}, nil
 	}
 
-	keycloaks, err := common.GetMatchingKeycloaks(r.context, r.Client, instance.Spec.InstanceSelector)
+	keycloaks, err := common.GetMatchingKeycloaks(ctx, r.Client, instance.Spec.InstanceSelector)
 	if err != nil {
-		return r.ManageError(instance, err)
+		return r.ManageError(ctx, instance, err)
 	}
 
 	logKcr.Info(fmt.Sprintf("found %v matching keycloak(s) for realm %v/%v", len(keycloaks.Items), instance.Namespace, instance.Name))
@@ -130,17 +124,17 @@ func (r *KeycloakRealmReconciler) Reconcile(ctx context.Context, request ctrl.Re
 		keycloakFactory := common.LocalConfigKeycloakFactory{}
 
 		if keycloak.Spec.Unmanaged {
-			return r.ManageError(instance, errors.Errorf("realms cannot be created for unmanaged keycloak instances"))
+			return r.ManageError(ctx, instance, common.PermanentErrorf("realms cannot be created for unmanaged keycloak instances"))
 		}
 
-		authenticated, err := keycloakFactory.AuthenticatedClient(keycloak, false)
+		authenticated, err := keycloakFactory.AuthenticatedClient(ctx, keycloak, false)
 
 		if err != nil {
-			return r.ManageError(instance, err)
+			return r.ManageError(ctx, instance, err)
 		}
 
 		// Compute the current state of the realm
-		realmState := common.NewRealmState(r.context, keycloak)
+		realmState := common.NewRealmState(ctx, keycloak)
 
 		logKcr.Info(fmt.Sprintf("read state for keycloak %v/%v, realm %v/%v",
 			keycloak.Namespace,
@@ -148,50 +142,30 @@ func (r *KeycloakRealmReconciler) Reconcile(ctx context.Context, request ctrl.Re
 			instance.Namespace,
 			instance.Spec.Realm.Realm))
 
-		err = realmState.Read(instance, authenticated, r.Client)
+		err = realmState.Read(ctx, instance, authenticated, r.Client)
 		if err != nil {
-			return r.ManageError(instance, err)
+			return r.ManageError(ctx, instance, err)
 		}
 
 		// Figure out the actions to keep the realms up to date with
 		// the desired state
 		reconciler := NewDedicatedKeycloakRealmReconciler(keycloak)
 		desiredState := reconciler.Reconcile(realmState, instance)
-		actionRunner := common.NewClusterAndKeycloakActionRunner(r.context, r.Client, r.Scheme, instance, authenticated)
+		actionRunner := common.NewClusterAndKeycloakActionRunner(ctx, r.Client, r.Scheme, instance, authenticated)
 
 		// Run all actions to keep the realms updated
 		err = actionRunner.RunAll(desiredState)
 		if err != nil {
-			return r.ManageError(instance, err)
+			return r.ManageError(ctx, instance, err)
 		}
 	}
 
-	return reconcile.Result{Requeue: false}, r.manageSuccess(instance, instance.DeletionTimestamp != nil)
+	return reconcile.Result{Requeue: false}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
 
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *KeycloakRealmReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	c, err := controller.New(RealmControllerName, mgr, controller.Options{Reconciler: r})
-	if err != nil {
-		return err
-	}
-
-	// Watch for changes to primary resource KeycloakRealm
-	err = c.Watch(&source.Kind{Type: &kc.KeycloakRealm{}}, &handler.EnqueueRequestForObject{})
-	if err != nil {
-		return err
-	}
-
-	// Make sure to watch the credential secrets
-	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
-		IsController: true,
-		OwnerType:    &kc.KeycloakRealm{},
-	})
-	if err != nil {
-		return err
-	}
-
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	r.context = ctx
@@ -199,18 +173,20 @@ func (r *KeycloakRealmReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.recorder = mgr.GetEventRecorderFor(RealmControllerName)
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&keycloakv1alpha1.KeycloakRealm{}).
-		Complete(r)
+		WithOptions(controllerOptions()).
+		Owns(&corev1.Secret{}).
+		Complete(withErrorBackoff(mgr.GetClient(), &keycloakv1alpha1.KeycloakRealm{}, withReconcileTimeout(r)))
 }
 
 // blank assignment to verify that ReconcileKeycloakRealm implements reconcile.Reconciler
 var _ reconcile.Reconciler = &KeycloakRealmReconciler{}
 
-func (r *KeycloakRealmReconciler) manageSuccess(realm *kc.KeycloakRealm, deleted bool) error {
+func (r *KeycloakRealmReconciler) manageSuccess(ctx context.Context, realm *kc.KeycloakRealm, deleted bool) error {
 	realm.Status.Ready = true
 	realm.Status.Message = ""
 	realm.Status.Phase = keycloakv1alpha1.PhaseReconciling
 
-	err := r.Client.Status().Update(r.context, realm)
+	err := r.Client.Status().Update(ctx, realm)
 	if err != nil {
 		logKcr.Error(err, "unable to update status")
 	}
@@ -236,7 +212,7 @@ func (r *KeycloakRealmReconciler) manageSuccess(realm *kc.KeycloakRealm, deleted
 			realm.Namespace,
 			realm.Spec.Realm.Realm))
 
-		return r.Client.Update(r.context, realm)
+		return r.Client.Update(ctx, realm)
 	}
 
 	// Otherwise remove the finalizer
@@ -253,23 +229,20 @@ func (r *KeycloakRealmReconciler) manageSuccess(realm *kc.KeycloakRealm, deleted
 	}
 
 	realm.Finalizers = newFinalizers
-	return r.Client.Update(r.context, realm)
+	return r.Client.Update(ctx, realm)
 }
 
-func (r *KeycloakRealmReconciler) ManageError(realm *kc.KeycloakRealm, issue error) (reconcile.Result, error) {
+func (r *KeycloakRealmReconciler) ManageError(ctx context.Context, realm *kc.KeycloakRealm, issue error) (reconcile.Result, error) {
 	r.recorder.Event(realm, "Warning", "ProcessingError", issue.Error())
 
 	realm.Status.Message = issue.Error()
 	realm.Status.Ready = false
 	realm.Status.Phase = keycloakv1alpha1.PhaseFailing
 
-	err := r.Client.Status().Update(r.context, realm)
+	err := r.Client.Status().Update(ctx, realm)
 	if err != nil {
 		logKcr.Error(err, "unable to update status")
 	}
 
-	return reconcile.Result{
-		RequeueAfter: RealmRequeueDelayError,
-		Requeue:      true,
-	}, nil
+	return reconcile.Result{}, issue
 }
@@ -0,0 +1,212 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+)
+
+// KeycloakRealmRoleReconciler reconciles a KeycloakRealmRole object
+type KeycloakRealmRoleReconciler struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Namespace the admin credentials of all ClusterKeycloaks are stored in
+	OperatorNamespace string
+	context           context.Context
+	cancel            context.CancelFunc
+	recorder          record.EventRecorder
+}
+
+var logKcrr = logf.Log.WithName("controller_keycloakrealmrole")
+
+const (
+	RealmRoleFinalizer      = "realmrole.cleanup"
+	RealmRoleControllerName = "keycloakrealmrole-controller"
+)
+
+// blank assignment to verify that KeycloakRealmRoleReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &KeycloakRealmRoleReconciler{}
+
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakrealmroles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakrealmroles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakrealmroles/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes the realm role in all realms selected by the KeycloakRealmRole.
+func (r *KeycloakRealmRoleReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	_ = logf.FromContext(ctx)
+
+	reqLogger := logKcrr.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling KeycloakRealmRole")
+
+	// Fetch the KeycloakRealmRole instance
+	instance := &kc.KeycloakRealmRole{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// The realm role may be applicable to multiple keycloak instances,
+	// process all of them
+	instances, err := common.GetMatchingRealmInstances(ctx, r.Client, r.OperatorNamespace, instance.Spec.RealmSelector, instance.Spec.ClusterRealmSelector)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+	logKcrr.Info(fmt.Sprintf("found %v matching realm(s) for realm role %v/%v", len(instances), instance.Namespace, instance.Name))
+
+	for _, realmInstance := range instances {
+		err = r.reconcileRealmRoleInRealm(ctx, instance, realmInstance.Realm, realmInstance.Keycloak)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+	}
+
+	return reconcile.Result{Requeue: false}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+}
+
+// reconcileRealmRoleInRealm brings the realm role in the given realm of the given keycloak to the desired state
+func (r *KeycloakRealmRoleReconciler) reconcileRealmRoleInRealm(ctx context.Context, instance *kc.KeycloakRealmRole, realm kc.KeycloakRealm, keycloak kc.Keycloak) error {
+	// Get an authenticated keycloak api client for the instance
+	keycloakFactory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := keycloakFactory.AuthenticatedClient(ctx, keycloak, false)
+	if err != nil {
+		return err
+	}
+
+	// Compute the current state of the realm role
+	realmRoleState := common.NewRealmRoleState(ctx, realm.DeepCopy(), keycloak)
+
+	logKcrr.Info(fmt.Sprintf("read realm role state for keycloak %v/%v, realm %v/%v, realm role %v/%v",
+		keycloak.Namespace,
+		keycloak.Name,
+		realm.Namespace,
+		realm.Name,
+		instance.Namespace,
+		instance.Name))
+
+	err = realmRoleState.Read(ctx, instance, authenticated, r.Client)
+	if err != nil {
+		return err
+	}
+
+	// Figure out the actions to keep the realm role up to date with
+	// the desired state
+	reconciler := NewDedicatedKeycloakRealmRoleReconciler(keycloak)
+	desiredState := reconciler.ReconcileIt(realmRoleState, instance)
+	actionRunner := common.NewClusterAndKeycloakActionRunner(ctx, r.Client, r.Scheme, instance, authenticated)
+
+	// Run all actions to keep the realm role updated
+	return actionRunner.RunAll(desiredState)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeycloakRealmRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	r.context = ctx
+	r.cancel = cancel
+	r.recorder = mgr.GetEventRecorderFor(RealmRoleControllerName)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kc.KeycloakRealmRole{}).
+		WithOptions(controllerOptions()).
+		Complete(withErrorBackoff(mgr.GetClient(), &kc.KeycloakRealmRole{}, withReconcileTimeout(r)))
+}
+
+func (r *KeycloakRealmRoleReconciler) manageSuccess(ctx context.Context, realmRole *kc.KeycloakRealmRole, deleted bool) error {
+	realmRole.Status.Ready = true
+	realmRole.Status.Message = ""
+	realmRole.Status.Phase = kc.PhaseReconciling
+
+	err := r.Client.Status().Update(ctx, realmRole)
+	if err != nil {
+		logKcrr.Error(err, "unable to update status")
+	}
+
+	// Finalizer already set?
+	finalizerExists := false
+	for _, finalizer := range realmRole.Finalizers {
+		if finalizer == RealmRoleFinalizer {
+			finalizerExists = true
+			break
+		}
+	}
+
+	// Resource created and finalizer exists: nothing to do
+	if !deleted && finalizerExists {
+		return nil
+	}
+
+	// Resource created and finalizer does not exist: add finalizer
+	if !deleted && !finalizerExists {
+		realmRole.Finalizers = append(realmRole.Finalizers, RealmRoleFinalizer)
+		logKcrr.Info(fmt.Sprintf("added finalizer to keycloak realm role %v/%v",
+			realmRole.Namespace,
+			realmRole.Spec.Role.Name))
+
+		return r.Client.Update(ctx, realmRole)
+	}
+
+	// Otherwise remove the finalizer
+	newFinalizers := []string{}
+	for _, finalizer := range realmRole.Finalizers {
+		if finalizer == RealmRoleFinalizer {
+			logKcrr.Info(fmt.Sprintf("removed finalizer from keycloak realm role %v/%v",
+				realmRole.Namespace,
+				realmRole.Spec.Role.Name))
+
+			continue
+		}
+		newFinalizers = append(newFinalizers, finalizer)
+	}
+
+	realmRole.Finalizers = newFinalizers
+	return r.Client.Update(ctx, realmRole)
+}
+
+func (r *KeycloakRealmRoleReconciler) ManageError(ctx context.Context, realmRole *kc.KeycloakRealmRole, issue error) (reconcile.Result, error) {
+	r.recorder.Event(realmRole, "Warning", "ProcessingError", issue.Error())
+
+	realmRole.Status.Message = issue.Error()
+	realmRole.Status.Ready = false
+	realmRole.Status.Phase = kc.PhaseFailing
+
+	err := r.Client.Status().Update(ctx, realmRole)
+	if err != nil {
+		logKcrr.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{}, issue
+}
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+)
+
+type DedicatedKeycloakRealmRoleReconciler struct { // nolint
+	Keycloak kc.Keycloak
+}
+
+func NewDedicatedKeycloakRealmRoleReconciler(keycloak kc.Keycloak) *DedicatedKeycloakRealmRoleReconciler {
+	return &DedicatedKeycloakRealmRoleReconciler{
+		Keycloak: keycloak,
+	}
+}
+
+func (i *DedicatedKeycloakRealmRoleReconciler) ReconcileIt(state *common.RealmRoleState, cr *kc.KeycloakRealmRole) common.DesiredClusterState {
+	desired := common.DesiredClusterState{}
+
+	desired.AddAction(i.pingKeycloak())
+	if cr.DeletionTimestamp != nil {
+		if state.Role != nil {
+			desired.AddAction(i.getDeletedRealmRoleState(state, cr))
+		}
+		return desired
+	}
+
+	if state.Role == nil {
+		desired.AddAction(i.getCreatedRealmRoleState(state, cr))
+	} else if !realmRoleEquals(state.Role, cr.Spec.Role) {
+		desired.AddAction(i.getUpdatedRealmRoleState(state, cr))
+	}
+
+	i.ReconcileComposites(state, cr, &desired)
+
+	return desired
+}
+
+// ReconcileComposites adds and removes composite realm and client roles, the existing and desired
+// composites are matched by the name of the role and the client it belongs to
+func (i *DedicatedKeycloakRealmRoleReconciler) ReconcileComposites(state *common.RealmRoleState, cr *kc.KeycloakRealmRole, desired *common.DesiredClusterState) {
+	desiredComposites := getDesiredComposites(state, cr)
+
+	compositesAdded := compositeDifference(desiredComposites, state.Composites)
+	if len(compositesAdded) > 0 {
+		desired.AddAction(i.getAddedCompositesState(state, cr, &compositesAdded))
+	}
+
+	compositesDeleted := compositeDifference(state.Composites, desiredComposites)
+	if len(compositesDeleted) > 0 {
+		desired.AddAction(i.getDeletedCompositesState(state, cr, &compositesDeleted))
+	}
+}
+
+// getDesiredComposites resolves the composites of the resource, that are given by name, to the roles of the realm.
+// Roles that can't be resolved are added without ID, Keycloak will reject them and the
+// resource is reconciled again
+func getDesiredComposites(state *common.RealmRoleState, cr *kc.KeycloakRealmRole) []kc.RoleRepresentation {
+	var composites []kc.RoleRepresentation
+	if cr.Spec.Role.Composites == nil {
+		return composites
+	}
+
+	for _, name := range cr.Spec.Role.Composites.Realm {
+		composites = append(composites, findRoleByName(state.RealmRoles, name, kc.RoleRepresentation{Name: name}))
+	}
+
+	// iterate the clients in a stable order to get the same actions on every run
+	clientIDs := make([]string, 0, len(cr.Spec.Role.Composites.Client))
+	for clientID := range cr.Spec.Role.Composites.Client {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+
+	clientRole := true
+	for _, clientID := range clientIDs {
+		containerID := ""
+		if keycloakClient := state.GetClientByClientID(clientID); keycloakClient != nil {
+			containerID = keycloakClient.ID
+		}
+		for _, name := range cr.Spec.Role.Composites.Client[clientID] {
+			role := findRoleByName(state.ClientRoles[clientID], name, kc.RoleRepresentation{Name: name})
+			role.ClientRole = &clientRole
+			role.ContainerID = containerID
+			composites = append(composites, role)
+		}
+	}
+
+	return composites
+}
+
+func findRoleByName(roles []kc.RoleRepresentation, name string, fallback kc.RoleRepresentation) kc.RoleRepresentation {
+	for _, role := range roles {
+		if role.Name == name {
+			return *role.DeepCopy()
+		}
+	}
+	return fallback
+}
+
+func compositeKey(role kc.RoleRepresentation) string {
+	if role.ClientRole != nil && *role.ClientRole {
+		return fmt.Sprintf("client/%s/%s", role.ContainerID, role.Name)
+	}
+	return fmt.Sprintf("realm/%s", role.Name)
+}
+
+// compositeDifference returns the composites of a that are not contained in b
+func compositeDifference(a []kc.RoleRepresentation, b []kc.RoleRepresentation) []kc.RoleRepresentation {
+	keys := make(map[string]bool, len(b))
+	for _, role := range b {
+		keys[compositeKey(role)] = true
+	}
+
+	var difference []kc.RoleRepresentation
+	for _, role := range a {
+		if !keys[compositeKey(role)] {
+			difference = append(difference, role)
+		}
+	}
+	return difference
+}
+
+func realmRoleEquals(existing *kc.RoleRepresentation, desired *kc.RoleRepresentation) bool {
+	if existing.Description != desired.Description {
+		return false
+	}
+	if len(existing.Attributes) == 0 && len(desired.Attributes) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(existing.Attributes, desired.Attributes)
+}
+
+func (i *DedicatedKeycloakRealmRoleReconciler) pingKeycloak() common.ClusterAction {
+	return common.PingAction{
+		Msg: "check if keycloak is available",
+	}
+}
+
+func (i *DedicatedKeycloakRealmRoleReconciler) getCreatedRealmRoleState(state *common.RealmRoleState, cr *kc.KeycloakRealmRole) common.ClusterAction {
+	return common.CreateRealmRoleAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("create realm role %v/%v", cr.Namespace, cr.Spec.Role.Name),
+	}
+}
+
+func (i *DedicatedKeycloakRealmRoleReconciler) getUpdatedRealmRoleState(state *common.RealmRoleState, cr *kc.KeycloakRealmRole) common.ClusterAction {
+	return common.UpdateRealmRoleAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("update realm role %v/%v", cr.Namespace, cr.Spec.Role.Name),
+	}
+}
+
+func (i *DedicatedKeycloakRealmRoleReconciler) getDeletedRealmRoleState(state *common.RealmRoleState, cr *kc.KeycloakRealmRole) common.ClusterAction {
+	return common.DeleteRealmRoleAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("delete realm role %v/%v", cr.Namespace, cr.Spec.Role.Name),
+	}
+}
+
+func (i *DedicatedKeycloakRealmRoleReconciler) getAddedCompositesState(state *common.RealmRoleState, cr *kc.KeycloakRealmRole, roles *[]kc.RoleRepresentation) common.ClusterAction {
+	return common.AddRealmRoleCompositesAction{
+		Roles: roles,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("add composites to realm role %v/%v", cr.Namespace, cr.Spec.Role.Name),
+	}
+}
+
+func (i *DedicatedKeycloakRealmRoleReconciler) getDeletedCompositesState(state *common.RealmRoleState, cr *kc.KeycloakRealmRole, roles *[]kc.RoleRepresentation) common.ClusterAction {
+	return common.DeleteRealmRoleCompositesAction{
+		Roles: roles,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("delete composites from realm role %v/%v", cr.Namespace, cr.Spec.Role.Name),
+	}
+}
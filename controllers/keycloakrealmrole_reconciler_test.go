@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+
+	"github.com/stretchr/testify/assert"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getDummyRealmRole() *v1alpha1.KeycloakRealmRole {
+	return &v1alpha1.KeycloakRealmRole{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakRealmRoleSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Role: &v1alpha1.RoleRepresentation{
+				Name:        "test",
+				Description: "test",
+				Composites: &v1alpha1.RoleRepresentationComposites{
+					Realm:  []string{"realm-a", "realm-b"},
+					Client: map[string][]string{"client": {"client-a"}},
+				},
+			},
+		},
+	}
+}
+
+func getDummyRealmRoleState() *common.RealmRoleState {
+	return &common.RealmRoleState{
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		AvailableRoles: common.AvailableRoles{
+			RealmRoles: []v1alpha1.RoleRepresentation{
+				{ID: "realm-a-id", Name: "realm-a"},
+				{ID: "realm-b-id", Name: "realm-b"},
+				{ID: "realm-c-id", Name: "realm-c"},
+			},
+			Clients: []*v1alpha1.KeycloakAPIClient{
+				{ID: "client-id", ClientID: "client"},
+			},
+			ClientRoles: map[string][]v1alpha1.RoleRepresentation{
+				"client": {
+					{ID: "client-a-id", Name: "client-a"},
+					{ID: "client-b-id", Name: "client-b"},
+				},
+			},
+		},
+	}
+}
+
+func TestKeycloakRealmRoleReconciler_Test_Creating_RealmRole(t *testing.T) {
+	// given
+	cr := getDummyRealmRole()
+	currentState := getDummyRealmRoleState()
+
+	// when
+	reconciler := NewDedicatedKeycloakRealmRoleReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// all composites are added after the role is created
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.CreateRealmRoleAction{}, desiredState[1])
+	assert.IsType(t, common.AddRealmRoleCompositesAction{}, desiredState[2])
+	composites := *desiredState[2].(common.AddRealmRoleCompositesAction).Roles
+	assert.Len(t, composites, 3)
+	assert.Equal(t, "realm-a-id", composites[0].ID)
+	assert.Equal(t, "realm-b-id", composites[1].ID)
+	assert.Equal(t, "client-a-id", composites[2].ID)
+}
+
+func TestKeycloakRealmRoleReconciler_Test_Updating_Composites(t *testing.T) {
+	// given
+	clientRole := true
+	cr := getDummyRealmRole()
+	currentState := getDummyRealmRoleState()
+	currentState.Role = &v1alpha1.RoleRepresentation{ID: "id", Name: "test", Description: "test"}
+	currentState.Composites = []v1alpha1.RoleRepresentation{
+		{ID: "realm-a-id", Name: "realm-a"},
+		{ID: "realm-c-id", Name: "realm-c"},
+		{ID: "client-a-id", Name: "client-a", ClientRole: &clientRole, ContainerID: "client-id"},
+		{ID: "client-b-id", Name: "client-b", ClientRole: &clientRole, ContainerID: "client-id"},
+	}
+
+	// when
+	reconciler := NewDedicatedKeycloakRealmRoleReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the role itself is unchanged, only the composites are updated
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	added := *desiredState[1].(common.AddRealmRoleCompositesAction).Roles
+	assert.Len(t, added, 1)
+	assert.Equal(t, "realm-b-id", added[0].ID)
+	deleted := *desiredState[2].(common.DeleteRealmRoleCompositesAction).Roles
+	assert.Len(t, deleted, 2)
+	assert.Equal(t, "realm-c-id", deleted[0].ID)
+	assert.Equal(t, "client-b-id", deleted[1].ID)
+}
+
+func TestKeycloakRealmRoleReconciler_Test_Updating_RealmRole(t *testing.T) {
+	// given
+	cr := getDummyRealmRole()
+	cr.Spec.Role.Composites = nil
+	cr.Spec.Role.Attributes = map[string][]string{"department": {"sales"}}
+	currentState := getDummyRealmRoleState()
+	currentState.Role = &v1alpha1.RoleRepresentation{ID: "id", Name: "test", Description: "test"}
+
+	// when
+	reconciler := NewDedicatedKeycloakRealmRoleReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.UpdateRealmRoleAction{}, desiredState[1])
+}
+
+func TestKeycloakRealmRoleReconciler_Test_Deleting_RealmRole(t *testing.T) {
+	// given
+	cr := getDummyRealmRole()
+	cr.DeletionTimestamp = &v13.Time{}
+	currentState := getDummyRealmRoleState()
+	currentState.Role = &v1alpha1.RoleRepresentation{ID: "id", Name: "test"}
+
+	// when
+	reconciler := NewDedicatedKeycloakRealmRoleReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.DeleteRealmRoleAction{}, desiredState[1])
+}
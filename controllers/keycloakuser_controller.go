@@ -0,0 +1,224 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+)
+
+// KeycloakUserReconciler reconciles a KeycloakUser object
+type KeycloakUserReconciler struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Namespace the admin credentials of all ClusterKeycloaks are stored in
+	OperatorNamespace string
+	context           context.Context
+	cancel            context.CancelFunc
+	recorder          record.EventRecorder
+}
+
+var logKcu = logf.Log.WithName("controller_keycloakuser")
+
+const (
+	UserFinalizer      = "user.cleanup"
+	UserControllerName = "keycloakuser-controller"
+)
+
+// blank assignment to verify that KeycloakUserReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &KeycloakUserReconciler{}
+
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakusers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakusers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=keycloak.org,resources=keycloakusers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates, updates and deletes the user in all realms selected by the KeycloakUser.
+func (r *KeycloakUserReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	_ = logf.FromContext(ctx)
+
+	reqLogger := logKcu.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling KeycloakUser")
+
+	// Fetch the KeycloakUser instance
+	instance := &kc.KeycloakUser{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// The user may be applicable to multiple keycloak instances,
+	// process all of them
+	instances, err := common.GetMatchingRealmInstances(ctx, r.Client, r.OperatorNamespace, instance.Spec.RealmSelector, instance.Spec.ClusterRealmSelector)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+	logKcu.Info(fmt.Sprintf("found %v matching realm(s) for user %v/%v", len(instances), instance.Namespace, instance.Name))
+
+	requeue := false
+	for _, realmInstance := range instances {
+		created, err := r.reconcileUserInRealm(ctx, instance, realmInstance.Realm, realmInstance.Keycloak)
+		if err != nil {
+			return r.ManageError(ctx, instance, err)
+		}
+		requeue = requeue || created
+	}
+
+	// Roles, groups and federated identities of new users are reconciled in the next run
+	return reconcile.Result{Requeue: requeue}, r.manageSuccess(ctx, instance, instance.DeletionTimestamp != nil)
+}
+
+// reconcileUserInRealm brings the user in the given realm of the given keycloak to the desired state,
+// returns true if the user was created
+func (r *KeycloakUserReconciler) reconcileUserInRealm(ctx context.Context, instance *kc.KeycloakUser, realm kc.KeycloakRealm, keycloak kc.Keycloak) (bool, error) {
+	// Get an authenticated keycloak api client for the instance
+	keycloakFactory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := keycloakFactory.AuthenticatedClient(ctx, keycloak, false)
+	if err != nil {
+		return false, err
+	}
+
+	// Compute the current state of the user
+	userState := common.NewUserState(keycloak)
+
+	logKcu.Info(fmt.Sprintf("read user state for keycloak %v/%v, realm %v/%v, user %v/%v",
+		keycloak.Namespace,
+		keycloak.Name,
+		realm.Namespace,
+		realm.Name,
+		instance.Namespace,
+		instance.Name))
+
+	err = userState.Read(ctx, instance, realm.DeepCopy(), authenticated, r.Client)
+	if err != nil {
+		return false, err
+	}
+
+	// Figure out the actions to keep the user up to date with
+	// the desired state
+	reconciler := NewDedicatedKeycloakUserReconciler(keycloak)
+	desiredState := reconciler.ReconcileIt(userState, instance)
+	actionRunner := common.NewClusterAndKeycloakActionRunner(ctx, r.Client, r.Scheme, instance, authenticated)
+
+	// Run all actions to keep the user updated
+	err = actionRunner.RunAll(desiredState)
+	return err == nil && userState.User == nil && instance.DeletionTimestamp == nil, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeycloakUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	r.context = ctx
+	r.cancel = cancel
+	r.recorder = mgr.GetEventRecorderFor(UserControllerName)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kc.KeycloakUser{}).
+		WithOptions(controllerOptions()).
+		Owns(&corev1.Secret{}).
+		Complete(withErrorBackoff(mgr.GetClient(), &kc.KeycloakUser{}, withReconcileTimeout(r)))
+}
+
+func (r *KeycloakUserReconciler) manageSuccess(ctx context.Context, user *kc.KeycloakUser, deleted bool) error {
+	user.Status.Ready = true
+	user.Status.Message = ""
+	user.Status.Phase = kc.PhaseReconciling
+
+	err := r.Client.Status().Update(ctx, user)
+	if err != nil {
+		logKcu.Error(err, "unable to update status")
+	}
+
+	// Finalizer already set?
+	finalizerExists := false
+	for _, finalizer := range user.Finalizers {
+		if finalizer == UserFinalizer {
+			finalizerExists = true
+			break
+		}
+	}
+
+	// Resource created and finalizer exists: nothing to do
+	if !deleted && finalizerExists {
+		return nil
+	}
+
+	// Only the finalizers are patched, the user is reconciled in several realms and keycloaks, so the user ID read into
+	// the spec is the one of the last realm and must not be stored
+	patch := client.MergeFrom(user.DeepCopy())
+
+	// Resource created and finalizer does not exist: add finalizer
+	if !deleted && !finalizerExists {
+		user.Finalizers = append(user.Finalizers, UserFinalizer)
+		logKcu.Info(fmt.Sprintf("added finalizer to keycloak user %v/%v",
+			user.Namespace,
+			user.Spec.User.UserName))
+
+		return r.Client.Patch(ctx, user, patch)
+	}
+
+	// Otherwise remove the finalizer
+	newFinalizers := []string{}
+	for _, finalizer := range user.Finalizers {
+		if finalizer == UserFinalizer {
+			logKcu.Info(fmt.Sprintf("removed finalizer from keycloak user %v/%v",
+				user.Namespace,
+				user.Spec.User.UserName))
+
+			continue
+		}
+		newFinalizers = append(newFinalizers, finalizer)
+	}
+
+	user.Finalizers = newFinalizers
+	return r.Client.Patch(ctx, user, patch)
+}
+
+func (r *KeycloakUserReconciler) ManageError(ctx context.Context, user *kc.KeycloakUser, issue error) (reconcile.Result, error) {
+	r.recorder.Event(user, "Warning", "ProcessingError", issue.Error())
+
+	user.Status.Message = issue.Error()
+	user.Status.Ready = false
+	user.Status.Phase = kc.PhaseFailing
+
+	err := r.Client.Status().Update(ctx, user)
+	if err != nil {
+		logKcu.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{}, issue
+}
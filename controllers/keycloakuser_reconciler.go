@@ -2,11 +2,248 @@ package controllers
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
 	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
 )
 
+// Length of the random bytes of generated user passwords
+const generatedPasswordLength = 16
+
+type DedicatedKeycloakUserReconciler struct { // nolint
+	Keycloak v1alpha1.Keycloak
+}
+
+func NewDedicatedKeycloakUserReconciler(keycloak v1alpha1.Keycloak) *DedicatedKeycloakUserReconciler {
+	return &DedicatedKeycloakUserReconciler{
+		Keycloak: keycloak,
+	}
+}
+
+func (i *DedicatedKeycloakUserReconciler) ReconcileIt(state *common.UserState, cr *v1alpha1.KeycloakUser) common.DesiredClusterState {
+	desired := common.DesiredClusterState{}
+	realmName := state.Realm.Spec.Realm.Realm
+
+	desired.AddAction(i.pingKeycloak())
+	if cr.DeletionTimestamp != nil {
+		// the credential secret is owned by the KeycloakUser and removed by kubernetes
+		if state.User != nil {
+			desired.AddAction(i.getDeletedUserState(state, cr))
+		}
+		return desired
+	}
+
+	if state.User == nil {
+		// roles, groups and federated identities need the ID of the user and are reconciled in the next run
+		password := i.getInitialPassword(state)
+		desired.AddAction(i.getCreatedUserState(state, cr, password))
+		desired.AddAction(i.getUserSecretState(state, cr, password))
+		return desired
+	}
+
+	if !userEquals(state.User, cr.Spec.User) {
+		desired.AddAction(i.getUpdatedUserState(state, cr))
+	}
+
+	for _, action := range GetUserRealmRolesDesiredState(state, cr.Spec.User.RealmRoles, realmName) {
+		desired.AddAction(action)
+	}
+
+	for _, action := range GetUserClientRolesDesiredState(state, cr.Spec.User.ClientRoles, realmName) {
+		desired.AddAction(action)
+	}
+
+	i.ReconcileGroups(state, cr, &desired)
+
+	i.ReconcileFederatedIdentities(state, cr, &desired)
+
+	if state.Secret == nil {
+		// the generated password is unknown once the secret is lost
+		desired.AddAction(i.getUserSecretState(state, cr, state.InitialPassword))
+	}
+
+	return desired
+}
+
+// ReconcileGroups adds the user to and removes it from groups, groups are matched by path
+func (i *DedicatedKeycloakUserReconciler) ReconcileGroups(state *common.UserState, cr *v1alpha1.KeycloakUser, desired *common.DesiredClusterState) {
+	for _, groupPath := range cr.Spec.User.Groups {
+		if findGroupByPath(state.Groups, groupPath) == nil {
+			desired.AddAction(i.getAddedUserGroupState(state, cr, groupPath))
+		}
+	}
+
+	for index := range state.Groups {
+		group := state.Groups[index]
+		if !containsGroupPath(cr.Spec.User.Groups, group.Path) {
+			desired.AddAction(i.getRemovedUserGroupState(state, cr, group.DeepCopy()))
+		}
+	}
+}
+
+// ReconcileFederatedIdentities links the user to identity providers, changed links are removed and added again
+func (i *DedicatedKeycloakUserReconciler) ReconcileFederatedIdentities(state *common.UserState, cr *v1alpha1.KeycloakUser, desired *common.DesiredClusterState) {
+	for _, existing := range state.FederatedIdentities {
+		fid := findFederatedIdentity(cr.Spec.User.FederatedIdentities, existing.IdentityProvider)
+		if fid == nil || fid.UserID != existing.UserID || fid.UserName != existing.UserName {
+			desired.AddAction(i.getRemovedFederatedIdentityState(state, cr, existing))
+		}
+	}
+
+	for _, fid := range cr.Spec.User.FederatedIdentities {
+		existing := findFederatedIdentity(state.FederatedIdentities, fid.IdentityProvider)
+		if existing == nil || fid.UserID != existing.UserID || fid.UserName != existing.UserName {
+			desired.AddAction(i.getAddedFederatedIdentityState(state, cr, fid))
+		}
+	}
+}
+
+// getInitialPassword returns the password of a new user: the password from the password secret,
+// the password of a previously published secret or a generated password
+func (i *DedicatedKeycloakUserReconciler) getInitialPassword(state *common.UserState) string {
+	if state.InitialPassword != "" {
+		return state.InitialPassword
+	}
+	if state.Secret != nil && len(state.Secret.Data[model.UserSecretPasswordProperty]) > 0 {
+		return string(state.Secret.Data[model.UserSecretPasswordProperty])
+	}
+	return model.GenerateRandomString(generatedPasswordLength)
+}
+
+func userEquals(existing *v1alpha1.KeycloakAPIUser, desired *v1alpha1.KeycloakAPIUser) bool {
+	if existing.FirstName != desired.FirstName ||
+		existing.LastName != desired.LastName ||
+		existing.Email != desired.Email ||
+		existing.EmailVerified != desired.EmailVerified ||
+		existing.Enabled != desired.Enabled {
+		return false
+	}
+	if len(existing.RequiredActions) != 0 || len(desired.RequiredActions) != 0 {
+		if !reflect.DeepEqual(existing.RequiredActions, desired.RequiredActions) {
+			return false
+		}
+	}
+	if len(existing.Attributes) != 0 || len(desired.Attributes) != 0 {
+		if !reflect.DeepEqual(existing.Attributes, desired.Attributes) {
+			return false
+		}
+	}
+	return true
+}
+
+func findGroupByPath(groups []v1alpha1.KeycloakAPIGroup, path string) *v1alpha1.KeycloakAPIGroup {
+	for index := range groups {
+		if groups[index].Path == path {
+			return &groups[index]
+		}
+	}
+	return nil
+}
+
+func containsGroupPath(paths []string, path string) bool {
+	for _, item := range paths {
+		if item == path {
+			return true
+		}
+	}
+	return false
+}
+
+func findFederatedIdentity(fids []v1alpha1.FederatedIdentity, identityProvider string) *v1alpha1.FederatedIdentity {
+	for index := range fids {
+		if fids[index].IdentityProvider == identityProvider {
+			return &fids[index]
+		}
+	}
+	return nil
+}
+
+func (i *DedicatedKeycloakUserReconciler) pingKeycloak() common.ClusterAction {
+	return common.PingAction{
+		Msg: "check if keycloak is available",
+	}
+}
+
+func (i *DedicatedKeycloakUserReconciler) getCreatedUserState(state *common.UserState, cr *v1alpha1.KeycloakUser, password string) common.ClusterAction {
+	return common.CreateUserAction{
+		Password: password,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("create user %v/%v", cr.Namespace, cr.Spec.User.UserName),
+	}
+}
+
+func (i *DedicatedKeycloakUserReconciler) getUpdatedUserState(state *common.UserState, cr *v1alpha1.KeycloakUser) common.ClusterAction {
+	return common.UpdateUserAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("update user %v/%v", cr.Namespace, cr.Spec.User.UserName),
+	}
+}
+
+func (i *DedicatedKeycloakUserReconciler) getDeletedUserState(state *common.UserState, cr *v1alpha1.KeycloakUser) common.ClusterAction {
+	return common.DeleteUserAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("delete user %v/%v", cr.Namespace, cr.Spec.User.UserName),
+	}
+}
+
+func (i *DedicatedKeycloakUserReconciler) getAddedUserGroupState(state *common.UserState, cr *v1alpha1.KeycloakUser, groupPath string) common.ClusterAction {
+	return common.AddUserToGroupAction{
+		GroupPath: groupPath,
+		Ref:       cr,
+		Realm:     state.Realm.Spec.Realm.Realm,
+		Msg:       fmt.Sprintf("add user %v/%v to group %v", cr.Namespace, cr.Spec.User.UserName, groupPath),
+	}
+}
+
+func (i *DedicatedKeycloakUserReconciler) getRemovedUserGroupState(state *common.UserState, cr *v1alpha1.KeycloakUser, group *v1alpha1.KeycloakAPIGroup) common.ClusterAction {
+	return common.RemoveUserFromGroupAction{
+		Group: group,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("remove user %v/%v from group %v", cr.Namespace, cr.Spec.User.UserName, group.Path),
+	}
+}
+
+func (i *DedicatedKeycloakUserReconciler) getAddedFederatedIdentityState(state *common.UserState, cr *v1alpha1.KeycloakUser, fid v1alpha1.FederatedIdentity) common.ClusterAction {
+	return common.AddUserFederatedIdentityAction{
+		FederatedIdentity: fid,
+		Ref:               cr,
+		Realm:             state.Realm.Spec.Realm.Realm,
+		Msg:               fmt.Sprintf("add federated identity %v to user %v/%v", fid.IdentityProvider, cr.Namespace, cr.Spec.User.UserName),
+	}
+}
+
+func (i *DedicatedKeycloakUserReconciler) getRemovedFederatedIdentityState(state *common.UserState, cr *v1alpha1.KeycloakUser, fid v1alpha1.FederatedIdentity) common.ClusterAction {
+	return common.RemoveUserFederatedIdentityAction{
+		FederatedIdentity: fid,
+		Ref:               cr,
+		Realm:             state.Realm.Spec.Realm.Realm,
+		Msg:               fmt.Sprintf("remove federated identity %v from user %v/%v", fid.IdentityProvider, cr.Namespace, cr.Spec.User.UserName),
+	}
+}
+
+func (i *DedicatedKeycloakUserReconciler) getUserSecretState(state *common.UserState, cr *v1alpha1.KeycloakUser, password string) common.ClusterAction {
+	secret := model.UserCredentialSecret(cr, state.Realm, &state.Keycloak, password)
+	if state.Secret == nil {
+		return common.GenericCreateAction{
+			Ref: secret,
+			Msg: fmt.Sprintf("create credential secret for user %v/%v", cr.Namespace, cr.Spec.User.UserName),
+		}
+	}
+
+	reconciled := state.Secret.DeepCopy()
+	reconciled.Data = secret.Data
+	return common.GenericUpdateAction{
+		Ref: reconciled,
+		Msg: fmt.Sprintf("update credential secret for user %v/%v", cr.Namespace, cr.Spec.User.UserName),
+	}
+}
+
 func GetUserRealmRolesDesiredState(state *common.UserState, realmRoles []string, realmName string) []common.ClusterAction {
 	var assignRoles []common.ClusterAction
 	var removeRoles []common.ClusterAction
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getDummyUser() *v1alpha1.KeycloakUser {
+	return &v1alpha1.KeycloakUser{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakUserSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			User: &v1alpha1.KeycloakAPIUser{
+				UserName: "test-user",
+				Enabled:  true,
+				Groups:   []string{"/sales/managers"},
+				FederatedIdentities: []v1alpha1.FederatedIdentity{
+					{IdentityProvider: "github", UserID: "4711", UserName: "test-user"},
+				},
+			},
+		},
+	}
+}
+
+func getDummyUserState() *common.UserState {
+	state := common.NewUserState(v1alpha1.Keycloak{})
+	state.Realm = &v1alpha1.KeycloakRealm{
+		Spec: v1alpha1.KeycloakRealmSpec{
+			Realm: &v1alpha1.KeycloakAPIRealm{
+				Realm: "test",
+			},
+		},
+	}
+	return state
+}
+
+func TestKeycloakUserReconciler_Test_Creating_User(t *testing.T) {
+	// given
+	cr := getDummyUser()
+	currentState := getDummyUserState()
+	currentState.InitialPassword = "secret"
+
+	// when
+	reconciler := NewDedicatedKeycloakUserReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// groups and federated identities are added in the next run
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.Equal(t, "secret", desiredState[1].(common.CreateUserAction).Password)
+	secret := desiredState[2].(common.GenericCreateAction).Ref.(*v1.Secret)
+	assert.Equal(t, "test-user", string(secret.Data[model.UserSecretUsernameProperty]))
+	assert.Equal(t, "secret", string(secret.Data[model.UserSecretPasswordProperty]))
+}
+
+func TestKeycloakUserReconciler_Test_Creating_User_With_Generated_Password(t *testing.T) {
+	// given
+	cr := getDummyUser()
+	currentState := getDummyUserState()
+
+	// when
+	reconciler := NewDedicatedKeycloakUserReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	password := desiredState[1].(common.CreateUserAction).Password
+	assert.NotEmpty(t, password)
+	secret := desiredState[2].(common.GenericCreateAction).Ref.(*v1.Secret)
+	assert.Equal(t, password, string(secret.Data[model.UserSecretPasswordProperty]))
+}
+
+func TestKeycloakUserReconciler_Test_Updating_Groups_And_Federated_Identities(t *testing.T) {
+	// given
+	cr := getDummyUser()
+	currentState := getDummyUserState()
+	currentState.User = &v1alpha1.KeycloakAPIUser{ID: "user-id", UserName: "test-user", Enabled: true}
+	currentState.Secret = &v1.Secret{}
+	currentState.Groups = []v1alpha1.KeycloakAPIGroup{
+		{ID: "old-id", Name: "old", Path: "/old"},
+	}
+	currentState.FederatedIdentities = []v1alpha1.FederatedIdentity{
+		{IdentityProvider: "github", UserID: "0815", UserName: "test-user"},
+	}
+
+	// when
+	reconciler := NewDedicatedKeycloakUserReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	// the changed federated identity is removed and added again
+	assert.Len(t, desiredState, 5)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.Equal(t, "/sales/managers", desiredState[1].(common.AddUserToGroupAction).GroupPath)
+	assert.Equal(t, "old-id", desiredState[2].(common.RemoveUserFromGroupAction).Group.ID)
+	assert.Equal(t, "0815", desiredState[3].(common.RemoveUserFederatedIdentityAction).FederatedIdentity.UserID)
+	assert.Equal(t, "4711", desiredState[4].(common.AddUserFederatedIdentityAction).FederatedIdentity.UserID)
+}
+
+func TestKeycloakUserReconciler_Test_Unchanged_User(t *testing.T) {
+	// given
+	cr := getDummyUser()
+	currentState := getDummyUserState()
+	currentState.User = &v1alpha1.KeycloakAPIUser{ID: "user-id", UserName: "test-user", Enabled: true}
+	currentState.Secret = &v1.Secret{}
+	currentState.Groups = []v1alpha1.KeycloakAPIGroup{
+		{ID: "managers-id", Name: "managers", Path: "/sales/managers"},
+	}
+	currentState.FederatedIdentities = cr.Spec.User.FederatedIdentities
+
+	// when
+	reconciler := NewDedicatedKeycloakUserReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 1)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+}
+
+func TestKeycloakUserReconciler_Test_Delete_User(t *testing.T) {
+	// given
+	cr := getDummyUser()
+	cr.DeletionTimestamp = &v13.Time{}
+	currentState := getDummyUserState()
+	currentState.User = &v1alpha1.KeycloakAPIUser{ID: "user-id", UserName: "test-user"}
+
+	// when
+	reconciler := NewDedicatedKeycloakUserReconciler(v1alpha1.Keycloak{})
+	desiredState := reconciler.ReconcileIt(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.DeleteUserAction{}, desiredState[1])
+}
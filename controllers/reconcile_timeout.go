@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReconcileTimeout limits the duration of a reconciliation, the requests to keycloak still running when it expires
+// are cancelled. Reconciliations are not limited when it is zero.
+var ReconcileTimeout time.Duration
+
+// timeoutReconciler runs the reconciliations of a reconciler with the ReconcileTimeout
+type timeoutReconciler struct {
+	reconciler reconcile.Reconciler
+}
+
+func withReconcileTimeout(reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return &timeoutReconciler{reconciler: reconciler}
+}
+
+func (r *timeoutReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	if ReconcileTimeout <= 0 {
+		return r.reconciler.Reconcile(ctx, request)
+	}
+	ctx, cancel := context.WithTimeout(ctx, ReconcileTimeout)
+	defer cancel()
+	return r.reconciler.Reconcile(ctx, request)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -28,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/common"
 	"github.com/christianwoehrle/keycloakclient-controller/pkg/k8sutil"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -68,11 +70,35 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var tracing common.TracingOptions
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8383", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&tracing.Endpoint, "otlp-endpoint", "", "The host:port of the OTLP gRPC receiver the traces are exported to, tracing is disabled without endpoint.")
+	flag.BoolVar(&tracing.Insecure, "otlp-insecure", false, "Export the traces without TLS.")
+	flag.Float64Var(&tracing.SampleRatio, "trace-sample-ratio", 1, "The ratio of the reconciliations that are traced.")
+	flag.DurationVar(&controllers.ReconcileTimeout, "reconcile-timeout", 5*time.Minute,
+		"The maximum duration of a reconciliation, requests to Keycloak still running are cancelled. 0 disables the timeout.")
+	flag.DurationVar(&controllers.ErrorBackoffBaseDelay, "error-backoff-base-delay", controllers.ErrorBackoffBaseDelay,
+		"The delay before a resource that failed with a transient error is reconciled again, it doubles with every failure in a row.")
+	flag.DurationVar(&controllers.ErrorBackoffMaxDelay, "error-backoff-max-delay", controllers.ErrorBackoffMaxDelay,
+		"The maximum delay before a resource that failed with a transient error is reconciled again.")
+	flag.IntVar(&controllers.MaxConcurrentReconciles, "max-concurrent-reconciles", controllers.MaxConcurrentReconciles,
+		"The number of resources of each kind reconciled at the same time.")
+	flag.IntVar(&common.KeycloakRequestOptions.MaxInFlight, "keycloak-max-in-flight", common.KeycloakRequestOptions.MaxInFlight,
+		"The number of requests to each Keycloak running at the same time. 0 disables the limit.")
+	flag.IntVar(&common.KeycloakRequestOptions.MaxRetries, "keycloak-max-retries", common.KeycloakRequestOptions.MaxRetries,
+		"The number of times an idempotent request to Keycloak is retried after a network error, 429 or 5xx.")
+	flag.DurationVar(&common.KeycloakRequestOptions.RetryBaseDelay, "keycloak-retry-base-delay", common.KeycloakRequestOptions.RetryBaseDelay,
+		"The delay before the first retry of a request to Keycloak, it doubles with every retry.")
+	flag.DurationVar(&common.KeycloakRequestOptions.RetryMaxDelay, "keycloak-retry-max-delay", common.KeycloakRequestOptions.RetryMaxDelay,
+		"The maximum delay between two attempts of a request to Keycloak, including the delay asked for with Retry-After.")
+	flag.Float64Var(&common.KeycloakRequestOptions.RateLimit, "keycloak-rate-limit", common.KeycloakRequestOptions.RateLimit,
+		"The number of requests per second sent to each Keycloak. 0 disables the rate limit.")
+	flag.IntVar(&common.KeycloakRequestOptions.RateBurst, "keycloak-rate-burst", common.KeycloakRequestOptions.RateBurst,
+		"The number of requests sent to each Keycloak at once before the rate limit applies.")
 	//pflag.CommandLine.AddFlagSet(zap.FlagSet())
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
@@ -98,6 +124,18 @@ func main() {
 
 	setupLog.Info(fmt.Sprintf("Setting watch namespace to '%v'", namespace))
 
+	// The admin credentials of cluster scoped keycloaks are kept in the operator namespace
+	operatorNamespace, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		setupLog.Info(fmt.Sprintf("Operator namespace unknown, cluster scoped keycloaks are not available: %v", err))
+	}
+
+	shutdownTracing, err := common.SetupTracing(context.Background(), tracing)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
 	syncPeriod := time.Minute * 10
 
 	// Set default manager options
@@ -157,25 +195,96 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controllers.KeycloakClientReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KeycloakClient")
 		os.Exit(1)
 	}
+	if err = (&controllers.ClusterKeycloakReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterKeycloak")
+		os.Exit(1)
+	}
+	if err = (&controllers.ClusterKeycloakRealmReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterKeycloakRealm")
+		os.Exit(1)
+	}
+	if err = (&controllers.KeycloakClientScopeReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeycloakClientScope")
+		os.Exit(1)
+	}
+	if err = (&controllers.KeycloakRealmRoleReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeycloakRealmRole")
+		os.Exit(1)
+	}
+	if err = (&controllers.KeycloakGroupReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeycloakGroup")
+		os.Exit(1)
+	}
+	if err = (&controllers.KeycloakUserReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeycloakUser")
+		os.Exit(1)
+	}
+	if err = (&controllers.KeycloakIdentityProviderReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeycloakIdentityProvider")
+		os.Exit(1)
+	}
+	if err = (&controllers.KeycloakAuthenticationFlowReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeycloakAuthenticationFlow")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	// the controller is ready when it could log in to a keycloak the last time, keycloaks aren't contacted by the check
+	if err := mgr.AddReadyzCheck("readyz", common.Contacts.Check); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	// flush the remaining spans before exiting
+	if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+		setupLog.Error(shutdownErr, "unable to flush the traces")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
@@ -0,0 +1,81 @@
+package common
+
+import (
+	"context"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type AuthenticationFlowState struct {
+	Flow *kc.KeycloakAPIAuthenticationFlow
+	// Executions of the flow and its sub-flows in the order they are listed by Keycloak
+	Executions []kc.KeycloakAPIAuthenticationExecutionInfo
+	// Authenticator configs by their ID
+	AuthenticatorConfigs map[string]*kc.KeycloakAPIAuthenticatorConfig
+	RealmFlowBindings    *kc.KeycloakAPIRealmFlowBindings
+	Context              context.Context
+	Realm                *kc.KeycloakRealm
+	Keycloak             kc.Keycloak
+}
+
+func NewAuthenticationFlowState(context context.Context, realm *kc.KeycloakRealm, keycloak kc.Keycloak) *AuthenticationFlowState {
+	return &AuthenticationFlowState{
+		Context:  context,
+		Realm:    realm,
+		Keycloak: keycloak,
+	}
+}
+
+func (i *AuthenticationFlowState) Read(context context.Context, cr *kc.KeycloakAuthenticationFlow, realmClient KeycloakInterface, controllerClient client.Client) error {
+	realmName := i.Realm.Spec.Realm.Realm
+
+	flows, err := realmClient.ListAuthenticationFlows(context, realmName)
+	if err != nil {
+		return err
+	}
+
+	i.Flow = nil
+	for index := range flows {
+		if flows[index].Alias == cr.Spec.Flow.Alias {
+			i.Flow = flows[index].DeepCopy()
+			break
+		}
+	}
+
+	i.RealmFlowBindings, err = realmClient.GetRealmFlowBindings(context, realmName)
+	if err != nil {
+		return err
+	}
+
+	i.Executions = nil
+	i.AuthenticatorConfigs = map[string]*kc.KeycloakAPIAuthenticatorConfig{}
+	if i.Flow == nil {
+		return nil
+	}
+
+	if i.Flow.BuiltIn {
+		return errors.Errorf("authentication flow %s is a built-in flow and can't be managed", i.Flow.Alias)
+	}
+
+	i.Executions, err = realmClient.ListAuthenticationExecutions(context, i.Flow.Alias, realmName)
+	if err != nil {
+		return err
+	}
+
+	for _, execution := range i.Executions {
+		if execution.AuthenticationConfig == "" {
+			continue
+		}
+		config, err := realmClient.GetAuthenticatorConfig(context, execution.AuthenticationConfig, realmName)
+		if err != nil {
+			return err
+		}
+		if config != nil {
+			i.AuthenticatorConfigs[execution.AuthenticationConfig] = config
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package common
+
+import (
+	"context"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+)
+
+// AvailableRoles holds the roles of a realm and its clients, they are used to
+// resolve roles that are referenced by name to their IDs
+type AvailableRoles struct {
+	// All roles of the realm
+	RealmRoles []kc.RoleRepresentation
+	// Clients of the realm, used to resolve client roles by clientId
+	Clients []*kc.KeycloakAPIClient
+	// Roles of the referenced clients, by clientId
+	ClientRoles map[string][]kc.RoleRepresentation
+}
+
+func (i *AvailableRoles) readAvailableRoles(context context.Context, realmClient KeycloakInterface, realmName string, clientIDs []string) error {
+	var err error
+	i.RealmRoles, err = realmClient.ListRealmRoles(context, realmName)
+	if err != nil {
+		return err
+	}
+
+	i.Clients, err = realmClient.ListClients(context, realmName)
+	if err != nil {
+		return err
+	}
+
+	i.ClientRoles = map[string][]kc.RoleRepresentation{}
+	for _, clientID := range clientIDs {
+		keycloakClient := i.GetClientByClientID(clientID)
+		if keycloakClient == nil {
+			continue
+		}
+		i.ClientRoles[clientID], err = realmClient.ListClientRoles(context, keycloakClient.ID, realmName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetClientByClientID returns the client of the realm with the given clientId or nil if it doesn't exist
+func (i *AvailableRoles) GetClientByClientID(clientID string) *kc.KeycloakAPIClient {
+	for _, keycloakClient := range i.Clients {
+		if keycloakClient.ClientID == clientID {
+			return keycloakClient
+		}
+	}
+	return nil
+}
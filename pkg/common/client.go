@@ -41,14 +41,15 @@ type Client struct {
 type T interface{}
 
 // Generic create function for creating new Keycloak resources
-func (c *Client) create(obj T, resourcePath, resourceName string) (string, error) {
+func (c *Client) create(ctx context.Context, obj T, resourcePath, resourceName string) (string, error) {
 	jsonValue, err := json.Marshal(obj)
 	if err != nil {
 		logrus.Errorf("error %+v marshalling object", err)
 		return "", nil
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
 		bytes.NewBuffer(jsonValue),
@@ -69,7 +70,7 @@ func (c *Client) create(obj T, resourcePath, resourceName string) (string, error
 	defer res.Body.Close()
 
 	if res.StatusCode != 201 && res.StatusCode != 204 {
-		return "", errors.Errorf("failed to create %s: (%d) %s", resourceName, res.StatusCode, res.Status)
+		return "", newAPIError(res, "failed to create %s", resourceName)
 	}
 
 	if resourceName == "client" {
@@ -86,43 +87,91 @@ func (c *Client) Endpoint() string {
 	return c.URL
 }
 
-func (c *Client) CreateRealm(realm *v1alpha1.KeycloakRealm) (string, error) {
-	return c.create(realm.Spec.Realm, "realms", "realm")
+func (c *Client) CreateRealm(ctx context.Context, realm *v1alpha1.KeycloakRealm) (string, error) {
+	return c.create(ctx, realm.Spec.Realm, "realms", "realm")
 }
 
-func (c *Client) CreateClient(client *v1alpha1.KeycloakAPIClient, realmName string) (string, error) {
-	return c.create(client, fmt.Sprintf("realms/%s/clients", realmName), "client")
+func (c *Client) CreateClient(ctx context.Context, client *v1alpha1.KeycloakAPIClient, realmName string) (string, error) {
+	return c.create(ctx, client, fmt.Sprintf("realms/%s/clients", realmName), "client")
 }
 
-func (c *Client) CreateClientRole(clientID string, role *v1alpha1.RoleRepresentation, realmName string) (string, error) {
-	return c.create(role, fmt.Sprintf("realms/%s/clients/%s/roles", realmName, clientID), "client role")
+func (c *Client) CreateClientRole(ctx context.Context, clientID string, role *v1alpha1.RoleRepresentation, realmName string) (string, error) {
+	return c.create(ctx, role, fmt.Sprintf("realms/%s/clients/%s/roles", realmName, clientID), "client role")
 }
 
-func (c *Client) AddRealmRoleComposites(realmName, roleID string, roles *[]v1alpha1.RoleRepresentation) error {
-	_, err := c.create(roles, fmt.Sprintf("realms/%s/roles-by-id/%s/composites", realmName, roleID), "realm role composites")
+func (c *Client) AddRealmRoleComposites(ctx context.Context, realmName, roleID string, roles *[]v1alpha1.RoleRepresentation) error {
+	_, err := c.create(ctx, roles, fmt.Sprintf("realms/%s/roles-by-id/%s/composites", realmName, roleID), "realm role composites")
 	return err
 }
 
-func (c *Client) CreateClientRealmScopeMappings(specClient *v1alpha1.KeycloakAPIClient, mappings *[]v1alpha1.RoleRepresentation, realmName string) error {
-	_, err := c.create(mappings, fmt.Sprintf("realms/%s/clients/%s/scope-mappings/realm", realmName, specClient.ID), "client realm scope mappings")
+func (c *Client) CreateRealmRole(ctx context.Context, role *v1alpha1.RoleRepresentation, realmName string) (string, error) {
+	return c.create(ctx, role, fmt.Sprintf("realms/%s/roles", realmName), "realm role")
+}
+
+func (c *Client) CreateClientRealmScopeMappings(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, mappings *[]v1alpha1.RoleRepresentation, realmName string) error {
+	_, err := c.create(ctx, mappings, fmt.Sprintf("realms/%s/clients/%s/scope-mappings/realm", realmName, specClient.ID), "client realm scope mappings")
+	return err
+}
+
+func (c *Client) CreateClientClientScopeMappings(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error {
+	_, err := c.create(ctx, mappings.Mappings, fmt.Sprintf("realms/%s/clients/%s/scope-mappings/clients/%s", realmName, specClient.ID, mappings.ID), "client client scope mappings")
+	return err
+}
+
+func (c *Client) CreateClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) (string, error) {
+	return c.create(ctx, clientScope, fmt.Sprintf("realms/%s/client-scopes", realmName), "client scope")
+}
+
+func (c *Client) CreateClientProtocolMapper(ctx context.Context, clientID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) (string, error) {
+	return c.create(ctx, mapper, fmt.Sprintf("realms/%s/clients/%s/protocol-mappers/models", realmName, clientID), "client protocol mapper")
+}
+
+func (c *Client) CreateClientScopeProtocolMapper(ctx context.Context, clientScopeID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) (string, error) {
+	return c.create(ctx, mapper, fmt.Sprintf("realms/%s/client-scopes/%s/protocol-mappers/models", realmName, clientScopeID), "client scope protocol mapper")
+}
+
+func (c *Client) CreateClientScopeRealmScopeMappings(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, mappings *[]v1alpha1.RoleRepresentation, realmName string) error {
+	_, err := c.create(ctx, mappings, fmt.Sprintf("realms/%s/client-scopes/%s/scope-mappings/realm", realmName, clientScope.ID), "client scope realm scope mappings")
 	return err
 }
 
-func (c *Client) CreateClientClientScopeMappings(specClient *v1alpha1.KeycloakAPIClient, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error {
-	_, err := c.create(mappings.Mappings, fmt.Sprintf("realms/%s/clients/%s/scope-mappings/clients/%s", realmName, specClient.ID, mappings.ID), "client client scope mappings")
+func (c *Client) CreateClientScopeClientScopeMappings(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error {
+	_, err := c.create(ctx, mappings.Mappings, fmt.Sprintf("realms/%s/client-scopes/%s/scope-mappings/clients/%s", realmName, clientScope.ID, mappings.ID), "client scope client scope mappings")
 	return err
 }
 
-func (c *Client) CreateFederatedIdentity(fid v1alpha1.FederatedIdentity, userID string, realmName string) (string, error) {
-	return c.create(fid, fmt.Sprintf("realms/%s/users/%s/federated-identity/%s", realmName, userID, fid.IdentityProvider), "federated-identity")
+func (c *Client) CreateGroup(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, realmName string) (string, error) {
+	return c.create(ctx, group, fmt.Sprintf("realms/%s/groups", realmName), "group")
 }
 
-func (c *Client) RemoveFederatedIdentity(fid v1alpha1.FederatedIdentity, userID string, realmName string) error {
-	return c.delete(fmt.Sprintf("realms/%s/users/%s/federated-identity/%s", realmName, userID, fid.IdentityProvider), "federated-identity", fid)
+func (c *Client) CreateChildGroup(ctx context.Context, parentGroupID string, group *v1alpha1.KeycloakAPIGroup, realmName string) (string, error) {
+	return c.create(ctx, group, fmt.Sprintf("realms/%s/groups/%s/children", realmName, parentGroupID), "child group")
 }
 
-func (c *Client) GetUserFederatedIdentities(userID string, realmName string) ([]v1alpha1.FederatedIdentity, error) {
-	result, err := c.get(fmt.Sprintf("realms/%s/users/%s/federated-identity", realmName, userID), "federated-identity", func(body []byte) (T, error) {
+func (c *Client) CreateGroupRealmRoleMappings(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, mappings *[]v1alpha1.RoleRepresentation, realmName string) error {
+	_, err := c.create(ctx, mappings, fmt.Sprintf("realms/%s/groups/%s/role-mappings/realm", realmName, group.ID), "group realm role mappings")
+	return err
+}
+
+func (c *Client) CreateGroupClientRoleMappings(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error {
+	_, err := c.create(ctx, mappings.Mappings, fmt.Sprintf("realms/%s/groups/%s/role-mappings/clients/%s", realmName, group.ID, mappings.ID), "group client role mappings")
+	return err
+}
+
+func (c *Client) CreateUser(ctx context.Context, user *v1alpha1.KeycloakAPIUser, realmName string) (string, error) {
+	return c.create(ctx, user, fmt.Sprintf("realms/%s/users", realmName), "user")
+}
+
+func (c *Client) CreateFederatedIdentity(ctx context.Context, fid v1alpha1.FederatedIdentity, userID string, realmName string) (string, error) {
+	return c.create(ctx, fid, fmt.Sprintf("realms/%s/users/%s/federated-identity/%s", realmName, userID, fid.IdentityProvider), "federated-identity")
+}
+
+func (c *Client) RemoveFederatedIdentity(ctx context.Context, fid v1alpha1.FederatedIdentity, userID string, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/users/%s/federated-identity/%s", realmName, userID, fid.IdentityProvider), "federated-identity", fid)
+}
+
+func (c *Client) GetUserFederatedIdentities(ctx context.Context, userID string, realmName string) ([]v1alpha1.FederatedIdentity, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/users/%s/federated-identity", realmName, userID), "federated-identity", func(body []byte) (T, error) {
 		var fids []v1alpha1.FederatedIdentity
 		err := json.Unmarshal(body, &fids)
 		return fids, err
@@ -133,23 +182,26 @@ func (c *Client) GetUserFederatedIdentities(userID string, realmName string) ([]
 	return result.([]v1alpha1.FederatedIdentity), err
 }
 
-func (c *Client) CreateUserClientRole(role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) (string, error) {
+func (c *Client) CreateUserClientRole(ctx context.Context, role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) (string, error) {
 	return c.create(
+		ctx,
 		[]*v1alpha1.KeycloakUserRole{role},
 		fmt.Sprintf("realms/%s/users/%s/role-mappings/clients/%s", realmName, userID, clientID),
 		"user-client-role",
 	)
 }
-func (c *Client) CreateUserRealmRole(role *v1alpha1.KeycloakUserRole, realmName, userID string) (string, error) {
+func (c *Client) CreateUserRealmRole(ctx context.Context, role *v1alpha1.KeycloakUserRole, realmName, userID string) (string, error) {
 	return c.create(
+		ctx,
 		[]*v1alpha1.KeycloakUserRole{role},
 		fmt.Sprintf("realms/%s/users/%s/role-mappings/realm", realmName, userID),
 		"user-realm-role",
 	)
 }
 
-func (c *Client) DeleteUserClientRole(role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) error {
+func (c *Client) DeleteUserClientRole(ctx context.Context, role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) error {
 	err := c.delete(
+		ctx,
 		fmt.Sprintf("realms/%s/users/%s/role-mappings/clients/%s", realmName, userID, clientID),
 		"user-client-role",
 		[]*v1alpha1.KeycloakUserRole{role},
@@ -157,8 +209,9 @@ func (c *Client) DeleteUserClientRole(role *v1alpha1.KeycloakUserRole, realmName
 	return err
 }
 
-func (c *Client) DeleteUserRealmRole(role *v1alpha1.KeycloakUserRole, realmName, userID string) error {
+func (c *Client) DeleteUserRealmRole(ctx context.Context, role *v1alpha1.KeycloakUserRole, realmName, userID string) error {
 	err := c.delete(
+		ctx,
 		fmt.Sprintf("realms/%s/users/%s/role-mappings/realm", realmName, userID),
 		"user-realm-role",
 		[]*v1alpha1.KeycloakUserRole{role},
@@ -167,9 +220,10 @@ func (c *Client) DeleteUserRealmRole(role *v1alpha1.KeycloakUserRole, realmName,
 }
 
 // Generic get function for returning a Keycloak resource
-func (c *Client) get(resourcePath, resourceName string, unMarshalFunc func(body []byte) (T, error)) (T, error) {
+func (c *Client) get(ctx context.Context, resourcePath, resourceName string, unMarshalFunc func(body []byte) (T, error)) (T, error) {
 	u := fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath)
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		u,
 		nil,
@@ -193,7 +247,7 @@ func (c *Client) get(resourcePath, resourceName string, unMarshalFunc func(body
 	}
 
 	if res.StatusCode != 200 {
-		return nil, errors.Errorf("failed to GET %s: (%d) %s", resourceName, res.StatusCode, res.Status)
+		return nil, newAPIError(res, "failed to GET %s", resourceName)
 	}
 
 	body, err := ioutil.ReadAll(res.Body)
@@ -210,12 +264,16 @@ func (c *Client) get(resourcePath, resourceName string, unMarshalFunc func(body
 	return obj, nil
 }
 
-func (c *Client) GetRealm(realmName string) (*v1alpha1.KeycloakRealm, error) {
-	result, err := c.get(fmt.Sprintf("realms/%s", realmName), "realm", func(body []byte) (T, error) {
+func (c *Client) GetRealm(ctx context.Context, realmName string) (*v1alpha1.KeycloakRealm, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s", realmName), "realm", func(body []byte) (T, error) {
 		realm := &v1alpha1.KeycloakAPIRealm{}
 		err := json.Unmarshal(body, realm)
 		return realm, err
 	})
+	// a failed or cancelled request must not be mistaken for a missing realm
+	if err != nil {
+		return nil, err
+	}
 	if result == nil {
 		return nil, nil
 	}
@@ -227,8 +285,8 @@ func (c *Client) GetRealm(realmName string) (*v1alpha1.KeycloakRealm, error) {
 	return ret, err
 }
 
-func (c *Client) GetClient(clientID, realmName string) (*v1alpha1.KeycloakAPIClient, error) {
-	result, err := c.get(fmt.Sprintf("realms/%s/clients/%s", realmName, clientID), "client", func(body []byte) (T, error) {
+func (c *Client) GetClient(ctx context.Context, clientID, realmName string) (*v1alpha1.KeycloakAPIClient, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/clients/%s", realmName, clientID), "client", func(body []byte) (T, error) {
 		client := &v1alpha1.KeycloakAPIClient{}
 		err := json.Unmarshal(body, client)
 		return client, err
@@ -243,8 +301,58 @@ func (c *Client) GetClient(clientID, realmName string) (*v1alpha1.KeycloakAPICli
 	return ret, err
 }
 
-func (c *Client) GetClientID(name, realmName string) (string, error) {
-	result, err := c.get(fmt.Sprintf("realms/%s/clients/?clientId=%s", realmName, name), "client", func(body []byte) (T, error) {
+func (c *Client) GetClientScope(ctx context.Context, clientScopeID, realmName string) (*v1alpha1.KeycloakAPIClientScope, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/client-scopes/%s", realmName, clientScopeID), "client scope", func(body []byte) (T, error) {
+		clientScope := &v1alpha1.KeycloakAPIClientScope{}
+		err := json.Unmarshal(body, clientScope)
+		return clientScope, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakAPIClientScope), nil
+}
+
+func (c *Client) GetRealmRole(ctx context.Context, roleName, realmName string) (*v1alpha1.RoleRepresentation, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/roles/%s", realmName, url.PathEscape(roleName)), "realm role", func(body []byte) (T, error) {
+		role := &v1alpha1.RoleRepresentation{}
+		err := json.Unmarshal(body, role)
+		return role, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.RoleRepresentation), nil
+}
+
+// GetGroupByPath returns the group with the given path, e.g. /parent/child, or nil if it doesn't exist
+func (c *Client) GetGroupByPath(ctx context.Context, path, realmName string) (*v1alpha1.KeycloakAPIGroup, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/group-by-path/%s", realmName, strings.Join(segments, "/")), "group", func(body []byte) (T, error) {
+		group := &v1alpha1.KeycloakAPIGroup{}
+		err := json.Unmarshal(body, group)
+		return group, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakAPIGroup), nil
+}
+
+func (c *Client) GetClientID(ctx context.Context, name, realmName string) (string, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/clients/?clientId=%s", realmName, name), "client", func(body []byte) (T, error) {
 		clients := []*v1alpha1.KeycloakAPIClient{}
 		err := json.Unmarshal(body, &clients)
 		return clients[0].ID, err
@@ -259,9 +367,9 @@ func (c *Client) GetClientID(name, realmName string) (string, error) {
 	return ret, err
 }
 
-func (c *Client) GetClientSecret(clientID, realmName string) (string, error) {
+func (c *Client) GetClientSecret(ctx context.Context, clientID, realmName string) (string, error) {
 	//"https://{{ rhsso_route }}/auth/admin/realms/{{ rhsso_realm }}/clients/{{ rhsso_client_id }}/client-secret"
-	result, err := c.get(fmt.Sprintf("realms/%s/clients/%s/client-secret", realmName, clientID), "client-secret", func(body []byte) (T, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/clients/%s/client-secret", realmName, clientID), "client-secret", func(body []byte) (T, error) {
 		res := map[string]string{}
 		if err := json.Unmarshal(body, &res); err != nil {
 			return nil, err
@@ -277,9 +385,15 @@ func (c *Client) GetClientSecret(clientID, realmName string) (string, error) {
 	return result.(string), nil
 }
 
-func (c *Client) GetClientInstall(clientID, realmName string) ([]byte, error) {
+func (c *Client) GetClientInstall(ctx context.Context, clientID, realmName string) ([]byte, error) {
+	return c.GetClientInstallation(ctx, clientID, "keycloak-oidc-keycloak-json", realmName)
+}
+
+// GetClientInstallation returns the configuration of the client created by the given installation provider, e.g. the
+// SAML metadata of the identity provider
+func (c *Client) GetClientInstallation(ctx context.Context, clientID, providerID, realmName string) ([]byte, error) {
 	var response []byte
-	if _, err := c.get(fmt.Sprintf("realms/%s/clients/%s/installation/providers/keycloak-oidc-keycloak-json", realmName, clientID), "client-installation", func(body []byte) (T, error) {
+	if _, err := c.get(ctx, fmt.Sprintf("realms/%s/clients/%s/installation/providers/%s", realmName, clientID, providerID), "client-installation", func(body []byte) (T, error) {
 		response = body
 		return body, nil
 	}); err != nil {
@@ -289,13 +403,14 @@ func (c *Client) GetClientInstall(clientID, realmName string) ([]byte, error) {
 }
 
 // Generic put function for updating Keycloak resources
-func (c *Client) update(obj T, resourcePath, resourceName string) error {
+func (c *Client) update(ctx context.Context, obj T, resourcePath, resourceName string) error {
 	jsonValue, err := json.Marshal(obj)
 	if err != nil {
 		return nil
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"PUT",
 		fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
 		bytes.NewBuffer(jsonValue),
@@ -315,35 +430,68 @@ func (c *Client) update(obj T, resourcePath, resourceName string) error {
 	defer res.Body.Close()
 	if res.StatusCode < 200 || res.StatusCode > 299 {
 		logrus.Errorf("failed to UPDATE %s %v", resourceName, res.Status)
-		return errors.Errorf("failed to UPDATE %s: (%d) %s", resourceName, res.StatusCode, res.Status)
+		return newAPIError(res, "failed to UPDATE %s", resourceName)
 	}
 
 	return nil
 }
 
-func (c *Client) UpdateRealm(realm *v1alpha1.KeycloakRealm) error {
-	return c.update(realm, fmt.Sprintf("realms/%s", realm.Spec.Realm.ID), "realm")
+func (c *Client) UpdateRealm(ctx context.Context, realm *v1alpha1.KeycloakRealm) error {
+	return c.update(ctx, realm, fmt.Sprintf("realms/%s", realm.Spec.Realm.ID), "realm")
+}
+
+func (c *Client) UpdateClient(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, realmName string) error {
+	return c.update(ctx, specClient, fmt.Sprintf("realms/%s/clients/%s", realmName, specClient.ID), "client")
+}
+
+func (c *Client) UpdateClientRole(ctx context.Context, clientID string, role, oldRole *v1alpha1.RoleRepresentation, realmName string) error {
+	return c.update(ctx, role, fmt.Sprintf("realms/%s/clients/%s/roles/%s", realmName, clientID, oldRole.Name), "client role")
+}
+
+func (c *Client) UpdateRealmRole(ctx context.Context, role *v1alpha1.RoleRepresentation, realmName string) error {
+	return c.update(ctx, role, fmt.Sprintf("realms/%s/roles-by-id/%s", realmName, role.ID), "realm role")
+}
+
+func (c *Client) UpdateClientDefaultClientScope(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.update(ctx, clientScope, fmt.Sprintf("realms/%s/clients/%s/default-client-scopes/%s", realmName, specClient.ID, clientScope.ID), "client default client scope")
+}
+
+func (c *Client) UpdateClientOptionalClientScope(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.update(ctx, clientScope, fmt.Sprintf("realms/%s/clients/%s/optional-client-scopes/%s", realmName, specClient.ID, clientScope.ID), "client optional client scope")
+}
+
+func (c *Client) UpdateClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.update(ctx, clientScope, fmt.Sprintf("realms/%s/client-scopes/%s", realmName, clientScope.ID), "client scope")
+}
+
+func (c *Client) UpdateClientProtocolMapper(ctx context.Context, clientID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) error {
+	return c.update(ctx, mapper, fmt.Sprintf("realms/%s/clients/%s/protocol-mappers/models/%s", realmName, clientID, mapper.ID), "client protocol mapper")
+}
+
+func (c *Client) UpdateClientScopeProtocolMapper(ctx context.Context, clientScopeID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) error {
+	return c.update(ctx, mapper, fmt.Sprintf("realms/%s/client-scopes/%s/protocol-mappers/models/%s", realmName, clientScopeID, mapper.ID), "client scope protocol mapper")
 }
 
-func (c *Client) UpdateClient(specClient *v1alpha1.KeycloakAPIClient, realmName string) error {
-	return c.update(specClient, fmt.Sprintf("realms/%s/clients/%s", realmName, specClient.ID), "client")
+func (c *Client) UpdateGroup(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, realmName string) error {
+	return c.update(ctx, group, fmt.Sprintf("realms/%s/groups/%s", realmName, group.ID), "group")
 }
 
-func (c *Client) UpdateClientRole(clientID string, role, oldRole *v1alpha1.RoleRepresentation, realmName string) error {
-	return c.update(role, fmt.Sprintf("realms/%s/clients/%s/roles/%s", realmName, clientID, oldRole.Name), "client role")
+func (c *Client) UpdateRealmDefaultGroup(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, realmName string) error {
+	return c.update(ctx, group, fmt.Sprintf("realms/%s/default-groups/%s", realmName, group.ID), "realm default group")
 }
 
-func (c *Client) UpdateClientDefaultClientScope(specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakClientScope, realmName string) error {
-	return c.update(clientScope, fmt.Sprintf("realms/%s/clients/%s/default-client-scopes/%s", realmName, specClient.ID, clientScope.ID), "client default client scope")
+func (c *Client) UpdateRealmDefaultClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.update(ctx, clientScope, fmt.Sprintf("realms/%s/default-default-client-scopes/%s", realmName, clientScope.ID), "realm default client scope")
 }
 
-func (c *Client) UpdateClientOptionalClientScope(specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakClientScope, realmName string) error {
-	return c.update(clientScope, fmt.Sprintf("realms/%s/clients/%s/optional-client-scopes/%s", realmName, specClient.ID, clientScope.ID), "client optional client scope")
+func (c *Client) UpdateRealmOptionalClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.update(ctx, clientScope, fmt.Sprintf("realms/%s/default-optional-client-scopes/%s", realmName, clientScope.ID), "realm optional client scope")
 }
 
 // Generic delete function for deleting Keycloak resources
-func (c *Client) delete(resourcePath, resourceName string, obj T) error {
-	req, err := http.NewRequest(
+func (c *Client) delete(ctx context.Context, resourcePath, resourceName string, obj T) error {
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"DELETE",
 		fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
 		nil,
@@ -354,7 +502,8 @@ func (c *Client) delete(resourcePath, resourceName string, obj T) error {
 		if err != nil {
 			return nil
 		}
-		req, err = http.NewRequest(
+		req, err = http.NewRequestWithContext(
+			ctx,
 			"DELETE",
 			fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
 			bytes.NewBuffer(jsonValue),
@@ -381,50 +530,99 @@ func (c *Client) delete(resourcePath, resourceName string, obj T) error {
 		logrus.Errorf("Resource %v/%v already deleted", resourcePath, resourceName)
 	}
 	if res.StatusCode != 204 && res.StatusCode != 404 {
-		return errors.Errorf("failed to DELETE %s: (%d) %s", resourceName, res.StatusCode, res.Status)
+		return newAPIError(res, "failed to DELETE %s", resourceName)
 	}
 
 	return nil
 }
 
-func (c *Client) DeleteRealm(realmName string) error {
-	err := c.delete(fmt.Sprintf("realms/%s", realmName), "realm", nil)
+func (c *Client) DeleteRealm(ctx context.Context, realmName string) error {
+	err := c.delete(ctx, fmt.Sprintf("realms/%s", realmName), "realm", nil)
 	return err
 }
 
-func (c *Client) DeleteClient(clientID, realmName string) error {
-	err := c.delete(fmt.Sprintf("realms/%s/clients/%s", realmName, clientID), "client", nil)
+func (c *Client) DeleteClient(ctx context.Context, clientID, realmName string) error {
+	err := c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s", realmName, clientID), "client", nil)
 	return err
 }
 
-func (c *Client) DeleteClientRole(clientID, role, realmName string) error {
-	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/roles/%s", realmName, clientID, role), "client role", nil)
+func (c *Client) DeleteClientRole(ctx context.Context, clientID, role, realmName string) error {
+	err := c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s/roles/%s", realmName, clientID, role), "client role", nil)
 	return err
 }
 
-func (c *Client) DeleteRealmRoleComposites(realmName, roleID string, roles *[]v1alpha1.RoleRepresentation) error {
-	return c.delete(fmt.Sprintf("realms/%s/roles-by-id/%s/composites", realmName, roleID), "realm role composites", roles)
+func (c *Client) DeleteRealmRoleComposites(ctx context.Context, realmName, roleID string, roles *[]v1alpha1.RoleRepresentation) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/roles-by-id/%s/composites", realmName, roleID), "realm role composites", roles)
+}
+
+func (c *Client) DeleteRealmRole(ctx context.Context, roleID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/roles-by-id/%s", realmName, roleID), "realm role", nil)
+}
+
+func (c *Client) DeleteClientRealmScopeMappings(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, mappings *[]v1alpha1.RoleRepresentation, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s/scope-mappings/realm", realmName, specClient.ID), "client realm scope mappings", mappings)
+}
+
+func (c *Client) DeleteClientClientScopeMappings(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s/scope-mappings/clients/%s", realmName, specClient.ID, mappings.ID), "client client scope mappings", mappings.Mappings)
+}
+
+func (c *Client) DeleteClientDefaultClientScope(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s/default-client-scopes/%s", realmName, specClient.ID, clientScope.ID), "client default client scope", clientScope)
+}
+
+func (c *Client) DeleteClientOptionalClientScope(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s/optional-client-scopes/%s", realmName, specClient.ID, clientScope.ID), "client optional client scope", clientScope)
+}
+
+func (c *Client) DeleteClientScope(ctx context.Context, clientScopeID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/client-scopes/%s", realmName, clientScopeID), "client scope", nil)
+}
+
+func (c *Client) DeleteClientProtocolMapper(ctx context.Context, clientID, mapperID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s/protocol-mappers/models/%s", realmName, clientID, mapperID), "client protocol mapper", nil)
+}
+
+func (c *Client) DeleteClientScopeProtocolMapper(ctx context.Context, clientScopeID, mapperID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/client-scopes/%s/protocol-mappers/models/%s", realmName, clientScopeID, mapperID), "client scope protocol mapper", nil)
+}
+
+func (c *Client) DeleteClientScopeRealmScopeMappings(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, mappings *[]v1alpha1.RoleRepresentation, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/client-scopes/%s/scope-mappings/realm", realmName, clientScope.ID), "client scope realm scope mappings", mappings)
 }
 
-func (c *Client) DeleteClientRealmScopeMappings(specClient *v1alpha1.KeycloakAPIClient, mappings *[]v1alpha1.RoleRepresentation, realmName string) error {
-	return c.delete(fmt.Sprintf("realms/%s/clients/%s/scope-mappings/realm", realmName, specClient.ID), "client realm scope mappings", mappings)
+func (c *Client) DeleteClientScopeClientScopeMappings(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/client-scopes/%s/scope-mappings/clients/%s", realmName, clientScope.ID, mappings.ID), "client scope client scope mappings", mappings.Mappings)
 }
 
-func (c *Client) DeleteClientClientScopeMappings(specClient *v1alpha1.KeycloakAPIClient, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error {
-	return c.delete(fmt.Sprintf("realms/%s/clients/%s/scope-mappings/clients/%s", realmName, specClient.ID, mappings.ID), "client client scope mappings", mappings.Mappings)
+func (c *Client) DeleteGroup(ctx context.Context, groupID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/groups/%s", realmName, groupID), "group", nil)
 }
 
-func (c *Client) DeleteClientDefaultClientScope(specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakClientScope, realmName string) error {
-	return c.delete(fmt.Sprintf("realms/%s/clients/%s/default-client-scopes/%s", realmName, specClient.ID, clientScope.ID), "client default client scope", clientScope)
+func (c *Client) DeleteGroupRealmRoleMappings(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, mappings *[]v1alpha1.RoleRepresentation, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/groups/%s/role-mappings/realm", realmName, group.ID), "group realm role mappings", mappings)
 }
 
-func (c *Client) DeleteClientOptionalClientScope(specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakClientScope, realmName string) error {
-	return c.delete(fmt.Sprintf("realms/%s/clients/%s/optional-client-scopes/%s", realmName, specClient.ID, clientScope.ID), "client optional client scope", clientScope)
+func (c *Client) DeleteGroupClientRoleMappings(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/groups/%s/role-mappings/clients/%s", realmName, group.ID, mappings.ID), "group client role mappings", mappings.Mappings)
+}
+
+func (c *Client) DeleteRealmDefaultGroup(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/default-groups/%s", realmName, group.ID), "realm default group", nil)
+}
+
+func (c *Client) DeleteRealmDefaultClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/default-default-client-scopes/%s", realmName, clientScope.ID), "realm default client scope", nil)
+}
+
+func (c *Client) DeleteRealmOptionalClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/default-optional-client-scopes/%s", realmName, clientScope.ID), "realm optional client scope", nil)
 }
 
 // Generic list function for listing Keycloak resources
-func (c *Client) list(resourcePath, resourceName string, unMarshalListFunc func(body []byte) (T, error)) (T, error) {
-	req, err := http.NewRequest(
+func (c *Client) list(ctx context.Context, resourcePath, resourceName string, unMarshalListFunc func(body []byte) (T, error)) (T, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
 		nil,
@@ -443,7 +641,7 @@ func (c *Client) list(resourcePath, resourceName string, unMarshalListFunc func(
 	defer res.Body.Close()
 
 	if res.StatusCode < 200 || res.StatusCode > 299 {
-		return nil, errors.Errorf("failed to LIST %s: (%d) %s", resourceName, res.StatusCode, res.Status)
+		return nil, newAPIError(res, "failed to LIST %s", resourceName)
 	}
 
 	body, err := ioutil.ReadAll(res.Body)
@@ -460,8 +658,8 @@ func (c *Client) list(resourcePath, resourceName string, unMarshalListFunc func(
 	return objs, nil
 }
 
-func (c *Client) ListRealms() ([]*v1alpha1.KeycloakRealm, error) {
-	result, err := c.list("realms", "realm", func(body []byte) (T, error) {
+func (c *Client) ListRealms(ctx context.Context) ([]*v1alpha1.KeycloakRealm, error) {
+	result, err := c.list(ctx, "realms", "realm", func(body []byte) (T, error) {
 		var realms []*v1alpha1.KeycloakRealm
 		err := json.Unmarshal(body, &realms)
 		return realms, err
@@ -473,8 +671,36 @@ func (c *Client) ListRealms() ([]*v1alpha1.KeycloakRealm, error) {
 	return resultAsRealm, err
 }
 
-func (c *Client) ListRealmRoleClientRoleComposites(realmName, roleID, clientID string) ([]v1alpha1.RoleRepresentation, error) {
-	result, err := c.list(fmt.Sprintf("realms/%s/roles-by-id/%s/composites/clients/%s", realmName, roleID, clientID), "realm role client role composites", func(body []byte) (T, error) {
+func (c *Client) listRoles(ctx context.Context, path, msg string) ([]v1alpha1.RoleRepresentation, error) {
+	result, err := c.list(ctx, path, msg, func(body []byte) (T, error) {
+		var roles []v1alpha1.RoleRepresentation
+		err := json.Unmarshal(body, &roles)
+		return roles, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.RoleRepresentation)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list %s response", msg)
+	}
+
+	return res, nil
+}
+
+func (c *Client) ListRealmRoles(ctx context.Context, realmName string) ([]v1alpha1.RoleRepresentation, error) {
+	return c.listRoles(ctx, fmt.Sprintf("realms/%s/roles", realmName), "realm roles")
+}
+
+func (c *Client) ListRealmRoleComposites(ctx context.Context, realmName, roleID string) ([]v1alpha1.RoleRepresentation, error) {
+	return c.listRoles(ctx, fmt.Sprintf("realms/%s/roles-by-id/%s/composites", realmName, roleID), "realm role composites")
+}
+
+func (c *Client) ListRealmRoleClientRoleComposites(ctx context.Context, realmName, roleID, clientID string) ([]v1alpha1.RoleRepresentation, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/roles-by-id/%s/composites/clients/%s", realmName, roleID, clientID), "realm role client role composites", func(body []byte) (T, error) {
 		var roles []v1alpha1.RoleRepresentation
 		err := json.Unmarshal(body, &roles)
 		return roles, err
@@ -493,8 +719,8 @@ func (c *Client) ListRealmRoleClientRoleComposites(realmName, roleID, clientID s
 	return res, nil
 }
 
-func (c *Client) ListClients(realmName string) ([]*v1alpha1.KeycloakAPIClient, error) {
-	result, err := c.list(fmt.Sprintf("realms/%s/clients", realmName), "clients", func(body []byte) (T, error) {
+func (c *Client) ListClients(ctx context.Context, realmName string) ([]*v1alpha1.KeycloakAPIClient, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/clients", realmName), "clients", func(body []byte) (T, error) {
 		var clients []*v1alpha1.KeycloakAPIClient
 		err := json.Unmarshal(body, &clients)
 		return clients, err
@@ -513,8 +739,8 @@ func (c *Client) ListClients(realmName string) ([]*v1alpha1.KeycloakAPIClient, e
 	return res, nil
 }
 
-func (c *Client) ListClientRoles(clientID, realmName string) ([]v1alpha1.RoleRepresentation, error) {
-	result, err := c.list(fmt.Sprintf("realms/%s/clients/%s/roles", realmName, clientID), "client roles", func(body []byte) (T, error) {
+func (c *Client) ListClientRoles(ctx context.Context, clientID, realmName string) ([]v1alpha1.RoleRepresentation, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/clients/%s/roles", realmName, clientID), "client roles", func(body []byte) (T, error) {
 		var roles []v1alpha1.RoleRepresentation
 		err := json.Unmarshal(body, &roles)
 		return roles, err
@@ -533,8 +759,8 @@ func (c *Client) ListClientRoles(clientID, realmName string) ([]v1alpha1.RoleRep
 	return res, nil
 }
 
-func (c *Client) ListScopeMappings(clientID, realmName string) (*v1alpha1.MappingsRepresentation, error) {
-	result, err := c.list(fmt.Sprintf("realms/%s/clients/%s/scope-mappings", realmName, clientID), "client scope mappings", func(body []byte) (T, error) {
+func (c *Client) ListScopeMappings(ctx context.Context, clientID, realmName string) (*v1alpha1.MappingsRepresentation, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/clients/%s/scope-mappings", realmName, clientID), "client scope mappings", func(body []byte) (T, error) {
 		var mappings v1alpha1.MappingsRepresentation
 		err := json.Unmarshal(body, &mappings)
 		return mappings, err
@@ -553,9 +779,9 @@ func (c *Client) ListScopeMappings(clientID, realmName string) (*v1alpha1.Mappin
 	return &res, nil
 }
 
-func (c *Client) listClientScopes(path string, msg string) ([]v1alpha1.KeycloakClientScope, error) {
-	result, err := c.list(path, msg, func(body []byte) (T, error) {
-		var assignedClientScopes []v1alpha1.KeycloakClientScope
+func (c *Client) listClientScopes(ctx context.Context, path string, msg string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	result, err := c.list(ctx, path, msg, func(body []byte) (T, error) {
+		var assignedClientScopes []v1alpha1.KeycloakAPIClientScope
 		err := json.Unmarshal(body, &assignedClientScopes)
 		return assignedClientScopes, err
 	})
@@ -564,7 +790,7 @@ func (c *Client) listClientScopes(path string, msg string) ([]v1alpha1.KeycloakC
 		return nil, err
 	}
 
-	res, ok := result.([]v1alpha1.KeycloakClientScope)
+	res, ok := result.([]v1alpha1.KeycloakAPIClientScope)
 
 	if !ok {
 		return nil, errors.Errorf("error decoding list %s response", msg)
@@ -573,20 +799,116 @@ func (c *Client) listClientScopes(path string, msg string) ([]v1alpha1.KeycloakC
 	return res, nil
 }
 
-func (c *Client) ListAvailableClientScopes(realmName string) ([]v1alpha1.KeycloakClientScope, error) {
-	return c.listClientScopes(fmt.Sprintf("realms/%s/client-scopes", realmName), "available client scopes")
+func (c *Client) ListClientProtocolMappers(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakProtocolMapper, error) {
+	return c.listProtocolMappers(ctx, fmt.Sprintf("realms/%s/clients/%s/protocol-mappers/models", realmName, clientID), "client protocol mappers")
+}
+
+func (c *Client) ListClientScopeProtocolMappers(ctx context.Context, clientScopeID, realmName string) ([]v1alpha1.KeycloakProtocolMapper, error) {
+	return c.listProtocolMappers(ctx, fmt.Sprintf("realms/%s/client-scopes/%s/protocol-mappers/models", realmName, clientScopeID), "client scope protocol mappers")
+}
+
+func (c *Client) listProtocolMappers(ctx context.Context, path, resourceName string) ([]v1alpha1.KeycloakProtocolMapper, error) {
+	result, err := c.list(ctx, path, resourceName, func(body []byte) (T, error) {
+		var mappers []v1alpha1.KeycloakProtocolMapper
+		err := json.Unmarshal(body, &mappers)
+		return mappers, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.KeycloakProtocolMapper)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list %s response", resourceName)
+	}
+
+	return res, nil
+}
+
+func (c *Client) ListAvailableClientScopes(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	return c.listClientScopes(ctx, fmt.Sprintf("realms/%s/client-scopes", realmName), "available client scopes")
+}
+
+func (c *Client) ListDefaultClientScopes(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	return c.listClientScopes(ctx, fmt.Sprintf("realms/%s/clients/%s/default-client-scopes", realmName, clientID), "default client scopes")
+}
+
+func (c *Client) ListOptionalClientScopes(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	return c.listClientScopes(ctx, fmt.Sprintf("realms/%s/clients/%s/optional-client-scopes", realmName, clientID), "optional client scopes")
+}
+
+func (c *Client) ListRealmDefaultClientScopes(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	return c.listClientScopes(ctx, fmt.Sprintf("realms/%s/default-default-client-scopes", realmName), "realm default client scopes")
+}
+
+func (c *Client) ListRealmOptionalClientScopes(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	return c.listClientScopes(ctx, fmt.Sprintf("realms/%s/default-optional-client-scopes", realmName), "realm optional client scopes")
+}
+
+func (c *Client) ListClientScopeScopeMappings(ctx context.Context, clientScopeID, realmName string) (*v1alpha1.MappingsRepresentation, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/client-scopes/%s/scope-mappings", realmName, clientScopeID), "client scope scope mappings", func(body []byte) (T, error) {
+		var mappings v1alpha1.MappingsRepresentation
+		err := json.Unmarshal(body, &mappings)
+		return mappings, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.(v1alpha1.MappingsRepresentation)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list client scope scope mappings response")
+	}
+
+	return &res, nil
 }
 
-func (c *Client) ListDefaultClientScopes(clientID, realmName string) ([]v1alpha1.KeycloakClientScope, error) {
-	return c.listClientScopes(fmt.Sprintf("realms/%s/clients/%s/default-client-scopes", realmName, clientID), "default client scopes")
+func (c *Client) ListGroupRoleMappings(ctx context.Context, groupID, realmName string) (*v1alpha1.MappingsRepresentation, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/groups/%s/role-mappings", realmName, groupID), "group role mappings", func(body []byte) (T, error) {
+		var mappings v1alpha1.MappingsRepresentation
+		err := json.Unmarshal(body, &mappings)
+		return mappings, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.(v1alpha1.MappingsRepresentation)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list group role mappings response")
+	}
+
+	return &res, nil
 }
 
-func (c *Client) ListOptionalClientScopes(clientID, realmName string) ([]v1alpha1.KeycloakClientScope, error) {
-	return c.listClientScopes(fmt.Sprintf("realms/%s/clients/%s/optional-client-scopes", realmName, clientID), "optional client scopes")
+func (c *Client) ListRealmDefaultGroups(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIGroup, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/default-groups", realmName), "realm default groups", func(body []byte) (T, error) {
+		var groups []v1alpha1.KeycloakAPIGroup
+		err := json.Unmarshal(body, &groups)
+		return groups, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.KeycloakAPIGroup)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list realm default groups response")
+	}
+
+	return res, nil
 }
 
-func (c *Client) ListUserClientRoles(realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
-	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/clients/"+clientID, "userClientRoles", func(body []byte) (t T, e error) {
+func (c *Client) ListUserClientRoles(ctx context.Context, realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
+	objects, err := c.list(ctx, "realms/"+realmName+"/users/"+userID+"/role-mappings/clients/"+clientID, "userClientRoles", func(body []byte) (t T, e error) {
 		var userClientRoles []*v1alpha1.KeycloakUserRole
 		err := json.Unmarshal(body, &userClientRoles)
 		return userClientRoles, err
@@ -600,8 +922,8 @@ func (c *Client) ListUserClientRoles(realmName, clientID, userID string) ([]*v1a
 	return objects.([]*v1alpha1.KeycloakUserRole), err
 }
 
-func (c *Client) ListAvailableUserClientRoles(realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
-	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/clients/"+clientID+"/available", "userClientRoles", func(body []byte) (t T, e error) {
+func (c *Client) ListAvailableUserClientRoles(ctx context.Context, realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
+	objects, err := c.list(ctx, "realms/"+realmName+"/users/"+userID+"/role-mappings/clients/"+clientID+"/available", "userClientRoles", func(body []byte) (t T, e error) {
 		var userClientRoles []*v1alpha1.KeycloakUserRole
 		err := json.Unmarshal(body, &userClientRoles)
 		return userClientRoles, err
@@ -615,8 +937,8 @@ func (c *Client) ListAvailableUserClientRoles(realmName, clientID, userID string
 	return objects.([]*v1alpha1.KeycloakUserRole), err
 }
 
-func (c *Client) ListUserRealmRoles(realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
-	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/realm", "userRealmRoles", func(body []byte) (t T, e error) {
+func (c *Client) ListUserRealmRoles(ctx context.Context, realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
+	objects, err := c.list(ctx, "realms/"+realmName+"/users/"+userID+"/role-mappings/realm", "userRealmRoles", func(body []byte) (t T, e error) {
 		var userRealmRoles []*v1alpha1.KeycloakUserRole
 		err := json.Unmarshal(body, &userRealmRoles)
 		return userRealmRoles, err
@@ -630,8 +952,8 @@ func (c *Client) ListUserRealmRoles(realmName, userID string) ([]*v1alpha1.Keycl
 	return objects.([]*v1alpha1.KeycloakUserRole), err
 }
 
-func (c *Client) ListAvailableUserRealmRoles(realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
-	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/realm/available", "userClientRoles", func(body []byte) (t T, e error) {
+func (c *Client) ListAvailableUserRealmRoles(ctx context.Context, realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
+	objects, err := c.list(ctx, "realms/"+realmName+"/users/"+userID+"/role-mappings/realm/available", "userClientRoles", func(body []byte) (t T, e error) {
 		var userRealmRoles []*v1alpha1.KeycloakUserRole
 		err := json.Unmarshal(body, &userRealmRoles)
 		return userRealmRoles, err
@@ -645,9 +967,9 @@ func (c *Client) ListAvailableUserRealmRoles(realmName, userID string) ([]*v1alp
 	return objects.([]*v1alpha1.KeycloakUserRole), err
 }
 
-func (c *Client) Ping() error {
+func (c *Client) Ping(ctx context.Context) error {
 	u := c.URL + "/auth/"
-	req, err := http.NewRequest("GET", u, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		logrus.Errorf("error creating ping request %+v", err)
 		return errors.Wrap(err, "error creating ping request")
@@ -658,18 +980,462 @@ func (c *Client) Ping() error {
 		logrus.Errorf("error on request %+v", err)
 		return errors.Wrapf(err, "error performing ping request")
 	}
+	defer res.Body.Close()
 
 	logrus.Debugf("response status: %v, %v", res.StatusCode, res.Status)
 	if res.StatusCode != 200 {
 		return errors.Errorf("failed to ping, response status code: %v", res.StatusCode)
 	}
-	defer res.Body.Close()
 
 	return nil
 }
 
-func (c *Client) GetServiceAccountUser(realmName, clientID string) (*v1alpha1.KeycloakAPIUser, error) {
-	result, err := c.get(fmt.Sprintf("realms/%s/clients/%s/service-account-user", realmName, clientID), "service-account-user", func(body []byte) (T, error) {
+// GetUserByUsername returns the user with the given username or nil if it doesn't exist
+func (c *Client) GetUserByUsername(ctx context.Context, userName, realmName string) (*v1alpha1.KeycloakAPIUser, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/users?username=%s&exact=true", realmName, url.QueryEscape(userName)), "user", func(body []byte) (T, error) {
+		var users []*v1alpha1.KeycloakAPIUser
+		err := json.Unmarshal(body, &users)
+		return users, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	// older keycloak versions ignore the exact parameter, usernames are stored in lower case
+	for _, user := range result.([]*v1alpha1.KeycloakAPIUser) {
+		if strings.EqualFold(user.UserName, userName) {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) UpdateUser(ctx context.Context, user *v1alpha1.KeycloakAPIUser, realmName string) error {
+	return c.update(ctx, user, fmt.Sprintf("realms/%s/users/%s", realmName, user.ID), "user")
+}
+
+func (c *Client) DeleteUser(ctx context.Context, userID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/users/%s", realmName, userID), "user", nil)
+}
+
+func (c *Client) ListUserGroups(ctx context.Context, userID, realmName string) ([]v1alpha1.KeycloakAPIGroup, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/users/%s/groups", realmName, userID), "user groups", func(body []byte) (T, error) {
+		var groups []v1alpha1.KeycloakAPIGroup
+		err := json.Unmarshal(body, &groups)
+		return groups, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.KeycloakAPIGroup)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list user groups response")
+	}
+
+	return res, nil
+}
+
+func (c *Client) AddUserToGroup(ctx context.Context, userID string, group *v1alpha1.KeycloakAPIGroup, realmName string) error {
+	return c.update(ctx, group, fmt.Sprintf("realms/%s/users/%s/groups/%s", realmName, userID, group.ID), "user group")
+}
+
+func (c *Client) RemoveUserFromGroup(ctx context.Context, userID string, group *v1alpha1.KeycloakAPIGroup, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/users/%s/groups/%s", realmName, userID, group.ID), "user group", nil)
+}
+
+func (c *Client) CreateIdentityProvider(ctx context.Context, identityProvider *v1alpha1.KeycloakAPIIdentityProvider, realmName string) (string, error) {
+	return c.create(ctx, identityProvider, fmt.Sprintf("realms/%s/identity-provider/instances", realmName), "identity provider")
+}
+
+// GetIdentityProvider returns the identity provider with the given alias or nil if it doesn't exist
+func (c *Client) GetIdentityProvider(ctx context.Context, alias, realmName string) (*v1alpha1.KeycloakAPIIdentityProvider, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/identity-provider/instances/%s", realmName, url.PathEscape(alias)), "identity provider", func(body []byte) (T, error) {
+		identityProvider := &v1alpha1.KeycloakAPIIdentityProvider{}
+		err := json.Unmarshal(body, identityProvider)
+		return identityProvider, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakAPIIdentityProvider), nil
+}
+
+func (c *Client) UpdateIdentityProvider(ctx context.Context, identityProvider *v1alpha1.KeycloakAPIIdentityProvider, realmName string) error {
+	return c.update(ctx, identityProvider, fmt.Sprintf("realms/%s/identity-provider/instances/%s", realmName, url.PathEscape(identityProvider.Alias)), "identity provider")
+}
+
+func (c *Client) DeleteIdentityProvider(ctx context.Context, alias, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/identity-provider/instances/%s", realmName, url.PathEscape(alias)), "identity provider", nil)
+}
+
+func (c *Client) ListIdentityProviderMappers(ctx context.Context, alias, realmName string) ([]v1alpha1.KeycloakAPIIdentityProviderMapper, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers", realmName, url.PathEscape(alias)), "identity provider mappers", func(body []byte) (T, error) {
+		var mappers []v1alpha1.KeycloakAPIIdentityProviderMapper
+		err := json.Unmarshal(body, &mappers)
+		return mappers, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.KeycloakAPIIdentityProviderMapper)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list identity provider mappers response")
+	}
+
+	return res, nil
+}
+
+func (c *Client) CreateIdentityProviderMapper(ctx context.Context, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realmName string) (string, error) {
+	return c.create(ctx, mapper, fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers", realmName, url.PathEscape(mapper.IdentityProviderAlias)), "identity provider mapper")
+}
+
+func (c *Client) UpdateIdentityProviderMapper(ctx context.Context, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realmName string) error {
+	return c.update(ctx, mapper, fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers/%s", realmName, url.PathEscape(mapper.IdentityProviderAlias), mapper.ID), "identity provider mapper")
+}
+
+func (c *Client) DeleteIdentityProviderMapper(ctx context.Context, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers/%s", realmName, url.PathEscape(mapper.IdentityProviderAlias), mapper.ID), "identity provider mapper", nil)
+}
+
+func (c *Client) ListAuthenticationFlows(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIAuthenticationFlow, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/authentication/flows", realmName), "authentication flows", func(body []byte) (T, error) {
+		var flows []v1alpha1.KeycloakAPIAuthenticationFlow
+		err := json.Unmarshal(body, &flows)
+		return flows, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.KeycloakAPIAuthenticationFlow)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list authentication flows response")
+	}
+
+	return res, nil
+}
+
+func (c *Client) CreateAuthenticationFlow(ctx context.Context, flow *v1alpha1.KeycloakAPIAuthenticationFlow, realmName string) (string, error) {
+	return c.create(ctx, flow, fmt.Sprintf("realms/%s/authentication/flows", realmName), "authentication flow")
+}
+
+func (c *Client) UpdateAuthenticationFlow(ctx context.Context, flow *v1alpha1.KeycloakAPIAuthenticationFlow, realmName string) error {
+	return c.update(ctx, flow, fmt.Sprintf("realms/%s/authentication/flows/%s", realmName, flow.ID), "authentication flow")
+}
+
+func (c *Client) DeleteAuthenticationFlow(ctx context.Context, flowID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/authentication/flows/%s", realmName, flowID), "authentication flow", nil)
+}
+
+// ListAuthenticationExecutions returns the executions of the flow and of all of its sub-flows
+func (c *Client) ListAuthenticationExecutions(ctx context.Context, flowAlias, realmName string) ([]v1alpha1.KeycloakAPIAuthenticationExecutionInfo, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/authentication/flows/%s/executions", realmName, url.PathEscape(flowAlias)), "authentication executions", func(body []byte) (T, error) {
+		var executions []v1alpha1.KeycloakAPIAuthenticationExecutionInfo
+		err := json.Unmarshal(body, &executions)
+		return executions, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.KeycloakAPIAuthenticationExecutionInfo)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list authentication executions response")
+	}
+
+	return res, nil
+}
+
+// CreateAuthenticationExecution adds an execution of the given authenticator to the end of the flow
+func (c *Client) CreateAuthenticationExecution(ctx context.Context, flowAlias, authenticator, realmName string) (string, error) {
+	execution := map[string]string{
+		"provider": authenticator,
+	}
+	return c.create(ctx, execution, fmt.Sprintf("realms/%s/authentication/flows/%s/executions/execution", realmName, url.PathEscape(flowAlias)), "authentication execution")
+}
+
+// CreateAuthenticationSubFlow adds the sub-flow to the end of the flow
+func (c *Client) CreateAuthenticationSubFlow(ctx context.Context, flowAlias string, subFlow *v1alpha1.KeycloakAuthenticationSubFlow, realmName string) (string, error) {
+	flowType := subFlow.Type
+	if flowType == "" {
+		flowType = "basic-flow"
+	}
+	execution := map[string]string{
+		"alias":       subFlow.Alias,
+		"description": subFlow.Description,
+		"type":        flowType,
+		"provider":    subFlow.Provider,
+	}
+	return c.create(ctx, execution, fmt.Sprintf("realms/%s/authentication/flows/%s/executions/flow", realmName, url.PathEscape(flowAlias)), "authentication sub-flow")
+}
+
+func (c *Client) UpdateAuthenticationExecution(ctx context.Context, flowAlias string, execution *v1alpha1.KeycloakAPIAuthenticationExecutionInfo, realmName string) error {
+	return c.update(ctx, execution, fmt.Sprintf("realms/%s/authentication/flows/%s/executions", realmName, url.PathEscape(flowAlias)), "authentication execution")
+}
+
+func (c *Client) DeleteAuthenticationExecution(ctx context.Context, executionID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/authentication/executions/%s", realmName, executionID), "authentication execution", nil)
+}
+
+func (c *Client) CreateAuthenticatorConfig(ctx context.Context, executionID string, config *v1alpha1.KeycloakAPIAuthenticatorConfig, realmName string) (string, error) {
+	return c.create(ctx, config, fmt.Sprintf("realms/%s/authentication/executions/%s/config", realmName, executionID), "authenticator config")
+}
+
+func (c *Client) GetAuthenticatorConfig(ctx context.Context, configID, realmName string) (*v1alpha1.KeycloakAPIAuthenticatorConfig, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/authentication/config/%s", realmName, configID), "authenticator config", func(body []byte) (T, error) {
+		config := &v1alpha1.KeycloakAPIAuthenticatorConfig{}
+		err := json.Unmarshal(body, config)
+		return config, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakAPIAuthenticatorConfig), nil
+}
+
+func (c *Client) UpdateAuthenticatorConfig(ctx context.Context, config *v1alpha1.KeycloakAPIAuthenticatorConfig, realmName string) error {
+	return c.update(ctx, config, fmt.Sprintf("realms/%s/authentication/config/%s", realmName, config.ID), "authenticator config")
+}
+
+func (c *Client) DeleteAuthenticatorConfig(ctx context.Context, configID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/authentication/config/%s", realmName, configID), "authenticator config", nil)
+}
+
+func (c *Client) GetRealmFlowBindings(ctx context.Context, realmName string) (*v1alpha1.KeycloakAPIRealmFlowBindings, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s", realmName), "realm flow bindings", func(body []byte) (T, error) {
+		bindings := &v1alpha1.KeycloakAPIRealmFlowBindings{}
+		err := json.Unmarshal(body, bindings)
+		return bindings, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakAPIRealmFlowBindings), nil
+}
+
+// UpdateRealmFlowBindings updates the flow bindings of the realm, bindings that are not set are not changed
+func (c *Client) UpdateRealmFlowBindings(ctx context.Context, bindings *v1alpha1.KeycloakAPIRealmFlowBindings, realmName string) error {
+	return c.update(ctx, bindings, fmt.Sprintf("realms/%s", realmName), "realm flow bindings")
+}
+
+func (c *Client) GetResourceServer(ctx context.Context, clientID, realmName string) (*v1alpha1.KeycloakResourceServer, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server", realmName, clientID), "resource server", func(body []byte) (T, error) {
+		resourceServer := &v1alpha1.KeycloakResourceServer{}
+		err := json.Unmarshal(body, resourceServer)
+		return resourceServer, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakResourceServer), nil
+}
+
+// UpdateResourceServer updates the settings of the resource server, resources, scopes and policies are not changed
+func (c *Client) UpdateResourceServer(ctx context.Context, clientID string, resourceServer *v1alpha1.KeycloakResourceServer, realmName string) error {
+	return c.update(ctx, resourceServer, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server", realmName, clientID), "resource server")
+}
+
+func (c *Client) ListAuthorizationScopes(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakScope, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/scope?max=-1", realmName, clientID), "authorization scopes", func(body []byte) (T, error) {
+		var scopes []v1alpha1.KeycloakScope
+		err := json.Unmarshal(body, &scopes)
+		return scopes, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.KeycloakScope)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list authorization scopes response")
+	}
+
+	return res, nil
+}
+
+func (c *Client) CreateAuthorizationScope(ctx context.Context, clientID string, scope *v1alpha1.KeycloakScope, realmName string) (string, error) {
+	return c.create(ctx, scope, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/scope", realmName, clientID), "authorization scope")
+}
+
+func (c *Client) UpdateAuthorizationScope(ctx context.Context, clientID string, scope *v1alpha1.KeycloakScope, realmName string) error {
+	return c.update(ctx, scope, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/scope/%s", realmName, clientID, scope.ID), "authorization scope")
+}
+
+func (c *Client) DeleteAuthorizationScope(ctx context.Context, clientID, scopeID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/scope/%s", realmName, clientID, scopeID), "authorization scope", nil)
+}
+
+// authorizationResource is the representation of a resource sent to and received from Keycloak,
+// Keycloak expects a list of values for each attribute
+type authorizationResource struct {
+	v1alpha1.KeycloakResource
+	Attributes map[string][]string `json:"attributes,omitempty"`
+}
+
+func newAuthorizationResource(resource *v1alpha1.KeycloakResource) *authorizationResource {
+	res := &authorizationResource{KeycloakResource: *resource}
+	if resource.Attributes != nil {
+		res.Attributes = map[string][]string{}
+		for key, value := range resource.Attributes {
+			res.Attributes[key] = []string{value}
+		}
+	}
+	return res
+}
+
+func (c *Client) ListAuthorizationResources(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakResource, error) {
+	result, err := c.list(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/resource?deep=true&max=-1", realmName, clientID), "authorization resources", func(body []byte) (T, error) {
+		var resources []authorizationResource
+		err := json.Unmarshal(body, &resources)
+		return resources, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]authorizationResource)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list authorization resources response")
+	}
+
+	resources := make([]v1alpha1.KeycloakResource, 0, len(res))
+	for _, resource := range res {
+		if resource.Attributes != nil {
+			resource.KeycloakResource.Attributes = map[string]string{}
+			for key, values := range resource.Attributes {
+				resource.KeycloakResource.Attributes[key] = strings.Join(values, ",")
+			}
+		}
+		resources = append(resources, resource.KeycloakResource)
+	}
+
+	return resources, nil
+}
+
+func (c *Client) CreateAuthorizationResource(ctx context.Context, clientID string, resource *v1alpha1.KeycloakResource, realmName string) (string, error) {
+	return c.create(ctx, newAuthorizationResource(resource), fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/resource", realmName, clientID), "authorization resource")
+}
+
+func (c *Client) UpdateAuthorizationResource(ctx context.Context, clientID string, resource *v1alpha1.KeycloakResource, realmName string) error {
+	return c.update(ctx, newAuthorizationResource(resource), fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/resource/%s", realmName, clientID, resource.ID), "authorization resource")
+}
+
+func (c *Client) DeleteAuthorizationResource(ctx context.Context, clientID, resourceID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/resource/%s", realmName, clientID, resourceID), "authorization resource", nil)
+}
+
+// ListAuthorizationPolicies returns the policies and permissions of the resource server without their associated
+// policies, resources and scopes
+func (c *Client) ListAuthorizationPolicies(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakPolicy, error) {
+	return c.listAuthorizationPolicies(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/policy?max=-1", realmName, clientID), "authorization policies")
+}
+
+func (c *Client) ListAssociatedAuthorizationPolicies(ctx context.Context, clientID, policyID, realmName string) ([]v1alpha1.KeycloakPolicy, error) {
+	return c.listAuthorizationPolicies(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/policy/%s/associatedPolicies", realmName, clientID, policyID), "associated authorization policies")
+}
+
+func (c *Client) listAuthorizationPolicies(ctx context.Context, resourcePath, resourceName string) ([]v1alpha1.KeycloakPolicy, error) {
+	result, err := c.list(ctx, resourcePath, resourceName, func(body []byte) (T, error) {
+		var policies []v1alpha1.KeycloakPolicy
+		err := json.Unmarshal(body, &policies)
+		return policies, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.KeycloakPolicy)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list %s response", resourceName)
+	}
+
+	return res, nil
+}
+
+// ListAuthorizationPolicyResources returns the names of the resources a policy is applied to
+func (c *Client) ListAuthorizationPolicyResources(ctx context.Context, clientID, policyID, realmName string) ([]string, error) {
+	return c.listAuthorizationPolicyDependents(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/policy/%s/resources", realmName, clientID, policyID), "authorization policy resources")
+}
+
+// ListAuthorizationPolicyScopes returns the names of the scopes a policy is applied to
+func (c *Client) ListAuthorizationPolicyScopes(ctx context.Context, clientID, policyID, realmName string) ([]string, error) {
+	return c.listAuthorizationPolicyDependents(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/policy/%s/scopes", realmName, clientID, policyID), "authorization policy scopes")
+}
+
+func (c *Client) listAuthorizationPolicyDependents(ctx context.Context, resourcePath, resourceName string) ([]string, error) {
+	result, err := c.list(ctx, resourcePath, resourceName, func(body []byte) (T, error) {
+		var dependents []struct {
+			Name string `json:"name"`
+		}
+		err := json.Unmarshal(body, &dependents)
+		if err != nil {
+			return nil, err
+		}
+		names := []string{}
+		for _, dependent := range dependents {
+			names = append(names, dependent.Name)
+		}
+		return names, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]string)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list %s response", resourceName)
+	}
+
+	return res, nil
+}
+
+// CreateAuthorizationPolicy creates a policy or permission, the type of the policy is taken from the representation
+func (c *Client) CreateAuthorizationPolicy(ctx context.Context, clientID string, policy *v1alpha1.KeycloakPolicy, realmName string) (string, error) {
+	return c.create(ctx, policy, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/policy", realmName, clientID), "authorization policy")
+}
+
+func (c *Client) UpdateAuthorizationPolicy(ctx context.Context, clientID string, policy *v1alpha1.KeycloakPolicy, realmName string) error {
+	return c.update(ctx, policy, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/policy/%s", realmName, clientID, policy.ID), "authorization policy")
+}
+
+func (c *Client) DeleteAuthorizationPolicy(ctx context.Context, clientID, policyID, realmName string) error {
+	return c.delete(ctx, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/policy/%s", realmName, clientID, policyID), "authorization policy", nil)
+}
+
+func (c *Client) GetServiceAccountUser(ctx context.Context, realmName, clientID string) (*v1alpha1.KeycloakAPIUser, error) {
+	result, err := c.get(ctx, fmt.Sprintf("realms/%s/clients/%s/service-account-user", realmName, clientID), "service-account-user", func(body []byte) (T, error) {
 		user := &v1alpha1.KeycloakAPIUser{}
 		err := json.Unmarshal(body, user)
 		return user, err
@@ -684,15 +1450,30 @@ func (c *Client) GetServiceAccountUser(realmName, clientID string) (*v1alpha1.Ke
 	return ret, err
 }
 
+// GetServerInfo returns the version and the capabilities of the keycloak
+func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
+	result, err := c.get(ctx, "serverinfo", "server info", func(body []byte) (T, error) {
+		return parseServerInfo(body)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.Errorf("keycloak didn't return its server info")
+	}
+	return result.(*ServerInfo), nil
+}
+
 // login requests a new auth token from Keycloak
-func (c *Client) login(user, pass string) error {
+func (c *Client) login(ctx context.Context, user, pass string) error {
 	form := url.Values{}
 	form.Add("username", user)
 	form.Add("password", pass)
 	form.Add("client_id", "admin-cli")
 	form.Add("grant_type", "password")
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		fmt.Sprintf("%s/%s", c.URL, authURL),
 		strings.NewReader(form.Encode()),
@@ -730,91 +1511,213 @@ func (c *Client) login(user, pass string) error {
 	return nil
 }
 
-// defaultRequester returns a default client for requesting http endpoints
-func defaultRequester(serverCert []byte) (Requester, error) {
-	tlsConfig, err := createTLSConfig(serverCert)
+// requestTimeout is the timeout of the requests to a keycloak that doesn't configure one
+const requestTimeout = 10 * time.Second
+
+// requesterSettings are the HTTP settings of the requests to a keycloak
+type requesterSettings struct {
+	timeout            time.Duration
+	proxyURL           *url.URL
+	caBundle           []byte
+	clientCertificate  *tls.Certificate
+	insecureSkipVerify bool
+}
+
+// defaultRequester returns a client for requesting http endpoints with the given settings
+func defaultRequester(settings requesterSettings) (Requester, error) {
+	tlsConfig, err := createTLSConfig(settings)
 	if err != nil {
 		return nil, err
 	}
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	transport.TLSClientConfig = tlsConfig
+	if settings.proxyURL != nil {
+		transport.Proxy = http.ProxyURL(settings.proxyURL)
+	}
 
-	c := &http.Client{Transport: transport, Timeout: time.Second * 10}
+	timeout := settings.timeout
+	if timeout <= 0 {
+		timeout = requestTimeout
+	}
+	c := &http.Client{Transport: transport, Timeout: timeout}
 	return c, nil
 }
 
-// createTLSConfig constructs and returns a TLS Config with a root CA read
-// from the serverCert param if present, or a permissive config which
-// is insecure otherwise
-func createTLSConfig(serverCert []byte) (*tls.Config, error) {
-	if serverCert == nil {
-		return &tls.Config{InsecureSkipVerify: true}, nil // nolint
+// createTLSConfig constructs and returns a TLS Config that verifies the server with the CA bundle if present, or with
+// the CAs of the system otherwise. The server is only not verified when insecureSkipVerify is set explicitly.
+func createTLSConfig(settings requesterSettings) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: settings.insecureSkipVerify} // nolint
+	if settings.clientCertificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*settings.clientCertificate}
+	}
+	if settings.caBundle == nil {
+		return tlsConfig, nil
 	}
 
 	rootCAPool := x509.NewCertPool()
-	if ok := rootCAPool.AppendCertsFromPEM(serverCert); !ok {
+	if ok := rootCAPool.AppendCertsFromPEM(settings.caBundle); !ok {
 		return nil, errors.Errorf("unable to successfully load certificate")
 	}
-	return &tls.Config{RootCAs: rootCAPool}, nil
+	tlsConfig.RootCAs = rootCAPool
+	return tlsConfig, nil
 }
 
 //go:generate moq -out keycloakClient_moq.go . KeycloakInterface
 
 type KeycloakInterface interface {
-	Ping() error
+	Ping(ctx context.Context) error
 
 	Endpoint() string
 
-	CreateRealm(realm *v1alpha1.KeycloakRealm) (string, error)
-	GetRealm(realmName string) (*v1alpha1.KeycloakRealm, error)
-	UpdateRealm(specRealm *v1alpha1.KeycloakRealm) error
-	DeleteRealm(realmName string) error
-	ListRealms() ([]*v1alpha1.KeycloakRealm, error)
-
-	ListRealmRoleClientRoleComposites(realmName, roleID, clientID string) ([]v1alpha1.RoleRepresentation, error)
-	AddRealmRoleComposites(realmName, roleID string, roles *[]v1alpha1.RoleRepresentation) error
-	DeleteRealmRoleComposites(realmName, roleID string, roles *[]v1alpha1.RoleRepresentation) error
-
-	CreateClient(client *v1alpha1.KeycloakAPIClient, realmName string) (string, error)
-	GetClient(clientID, realmName string) (*v1alpha1.KeycloakAPIClient, error)
-	GetClientID(clientID, realmName string) (string, error)
-	GetClientSecret(clientID, realmName string) (string, error)
-	GetClientInstall(clientID, realmName string) ([]byte, error)
-	UpdateClient(specClient *v1alpha1.KeycloakAPIClient, realmName string) error
-	DeleteClient(clientID, realmName string) error
-	ListClients(realmName string) ([]*v1alpha1.KeycloakAPIClient, error)
-	ListClientRoles(clientID, realmName string) ([]v1alpha1.RoleRepresentation, error)
-	ListScopeMappings(clientID, realmName string) (*v1alpha1.MappingsRepresentation, error)
-	ListAvailableClientScopes(realmName string) ([]v1alpha1.KeycloakClientScope, error)
-	ListDefaultClientScopes(clientID, realmName string) ([]v1alpha1.KeycloakClientScope, error)
-	ListOptionalClientScopes(clientID, realmName string) ([]v1alpha1.KeycloakClientScope, error)
-	CreateClientRole(clientID string, role *v1alpha1.RoleRepresentation, realmName string) (string, error)
-	UpdateClientRole(clientID string, role, oldRole *v1alpha1.RoleRepresentation, realmName string) error
-	DeleteClientRole(clientID, role, realmName string) error
-	CreateClientRealmScopeMappings(specClient *v1alpha1.KeycloakAPIClient, mappings *[]v1alpha1.RoleRepresentation, realmName string) error
-	DeleteClientRealmScopeMappings(specClient *v1alpha1.KeycloakAPIClient, mappings *[]v1alpha1.RoleRepresentation, realmName string) error
-	CreateClientClientScopeMappings(specClient *v1alpha1.KeycloakAPIClient, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error
-	DeleteClientClientScopeMappings(specClient *v1alpha1.KeycloakAPIClient, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error
-	UpdateClientDefaultClientScope(specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakClientScope, realmName string) error
-	DeleteClientDefaultClientScope(specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakClientScope, realmName string) error
-	UpdateClientOptionalClientScope(specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakClientScope, realmName string) error
-	DeleteClientOptionalClientScope(specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakClientScope, realmName string) error
-
-	CreateFederatedIdentity(fid v1alpha1.FederatedIdentity, userID string, realmName string) (string, error)
-	RemoveFederatedIdentity(fid v1alpha1.FederatedIdentity, userID string, realmName string) error
-	GetUserFederatedIdentities(userName string, realmName string) ([]v1alpha1.FederatedIdentity, error)
-
-	CreateUserClientRole(role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) (string, error)
-	ListUserClientRoles(realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error)
-	ListAvailableUserClientRoles(realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error)
-	DeleteUserClientRole(role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) error
-
-	CreateUserRealmRole(role *v1alpha1.KeycloakUserRole, realmName, userID string) (string, error)
-	ListUserRealmRoles(realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error)
-	ListAvailableUserRealmRoles(realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error)
-	DeleteUserRealmRole(role *v1alpha1.KeycloakUserRole, realmName, userID string) error
-
-	GetServiceAccountUser(realmName, clientID string) (*v1alpha1.KeycloakAPIUser, error)
+	GetServerInfo(ctx context.Context) (*ServerInfo, error)
+
+	CreateRealm(ctx context.Context, realm *v1alpha1.KeycloakRealm) (string, error)
+	GetRealm(ctx context.Context, realmName string) (*v1alpha1.KeycloakRealm, error)
+	UpdateRealm(ctx context.Context, specRealm *v1alpha1.KeycloakRealm) error
+	DeleteRealm(ctx context.Context, realmName string) error
+	ListRealms(ctx context.Context) ([]*v1alpha1.KeycloakRealm, error)
+
+	ListRealmRoleClientRoleComposites(ctx context.Context, realmName, roleID, clientID string) ([]v1alpha1.RoleRepresentation, error)
+	AddRealmRoleComposites(ctx context.Context, realmName, roleID string, roles *[]v1alpha1.RoleRepresentation) error
+	DeleteRealmRoleComposites(ctx context.Context, realmName, roleID string, roles *[]v1alpha1.RoleRepresentation) error
+
+	CreateRealmRole(ctx context.Context, role *v1alpha1.RoleRepresentation, realmName string) (string, error)
+	GetRealmRole(ctx context.Context, roleName, realmName string) (*v1alpha1.RoleRepresentation, error)
+	UpdateRealmRole(ctx context.Context, role *v1alpha1.RoleRepresentation, realmName string) error
+	DeleteRealmRole(ctx context.Context, roleID, realmName string) error
+	ListRealmRoles(ctx context.Context, realmName string) ([]v1alpha1.RoleRepresentation, error)
+	ListRealmRoleComposites(ctx context.Context, realmName, roleID string) ([]v1alpha1.RoleRepresentation, error)
+
+	CreateClient(ctx context.Context, client *v1alpha1.KeycloakAPIClient, realmName string) (string, error)
+	GetClient(ctx context.Context, clientID, realmName string) (*v1alpha1.KeycloakAPIClient, error)
+	GetClientID(ctx context.Context, clientID, realmName string) (string, error)
+	GetClientSecret(ctx context.Context, clientID, realmName string) (string, error)
+	GetClientInstall(ctx context.Context, clientID, realmName string) ([]byte, error)
+	GetClientInstallation(ctx context.Context, clientID, providerID, realmName string) ([]byte, error)
+	UpdateClient(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, realmName string) error
+	DeleteClient(ctx context.Context, clientID, realmName string) error
+	ListClients(ctx context.Context, realmName string) ([]*v1alpha1.KeycloakAPIClient, error)
+	ListClientRoles(ctx context.Context, clientID, realmName string) ([]v1alpha1.RoleRepresentation, error)
+	ListScopeMappings(ctx context.Context, clientID, realmName string) (*v1alpha1.MappingsRepresentation, error)
+	ListAvailableClientScopes(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error)
+	ListDefaultClientScopes(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error)
+	ListOptionalClientScopes(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error)
+	CreateClientRole(ctx context.Context, clientID string, role *v1alpha1.RoleRepresentation, realmName string) (string, error)
+	UpdateClientRole(ctx context.Context, clientID string, role, oldRole *v1alpha1.RoleRepresentation, realmName string) error
+	DeleteClientRole(ctx context.Context, clientID, role, realmName string) error
+	ListClientProtocolMappers(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakProtocolMapper, error)
+	CreateClientProtocolMapper(ctx context.Context, clientID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) (string, error)
+	UpdateClientProtocolMapper(ctx context.Context, clientID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) error
+	DeleteClientProtocolMapper(ctx context.Context, clientID, mapperID, realmName string) error
+	CreateClientRealmScopeMappings(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, mappings *[]v1alpha1.RoleRepresentation, realmName string) error
+	DeleteClientRealmScopeMappings(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, mappings *[]v1alpha1.RoleRepresentation, realmName string) error
+	CreateClientClientScopeMappings(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error
+	DeleteClientClientScopeMappings(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error
+	UpdateClientDefaultClientScope(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+	DeleteClientDefaultClientScope(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+	UpdateClientOptionalClientScope(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+	DeleteClientOptionalClientScope(ctx context.Context, specClient *v1alpha1.KeycloakAPIClient, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+
+	GetResourceServer(ctx context.Context, clientID, realmName string) (*v1alpha1.KeycloakResourceServer, error)
+	UpdateResourceServer(ctx context.Context, clientID string, resourceServer *v1alpha1.KeycloakResourceServer, realmName string) error
+	ListAuthorizationScopes(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakScope, error)
+	CreateAuthorizationScope(ctx context.Context, clientID string, scope *v1alpha1.KeycloakScope, realmName string) (string, error)
+	UpdateAuthorizationScope(ctx context.Context, clientID string, scope *v1alpha1.KeycloakScope, realmName string) error
+	DeleteAuthorizationScope(ctx context.Context, clientID, scopeID, realmName string) error
+	ListAuthorizationResources(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakResource, error)
+	CreateAuthorizationResource(ctx context.Context, clientID string, resource *v1alpha1.KeycloakResource, realmName string) (string, error)
+	UpdateAuthorizationResource(ctx context.Context, clientID string, resource *v1alpha1.KeycloakResource, realmName string) error
+	DeleteAuthorizationResource(ctx context.Context, clientID, resourceID, realmName string) error
+	ListAuthorizationPolicies(ctx context.Context, clientID, realmName string) ([]v1alpha1.KeycloakPolicy, error)
+	ListAssociatedAuthorizationPolicies(ctx context.Context, clientID, policyID, realmName string) ([]v1alpha1.KeycloakPolicy, error)
+	ListAuthorizationPolicyResources(ctx context.Context, clientID, policyID, realmName string) ([]string, error)
+	ListAuthorizationPolicyScopes(ctx context.Context, clientID, policyID, realmName string) ([]string, error)
+	CreateAuthorizationPolicy(ctx context.Context, clientID string, policy *v1alpha1.KeycloakPolicy, realmName string) (string, error)
+	UpdateAuthorizationPolicy(ctx context.Context, clientID string, policy *v1alpha1.KeycloakPolicy, realmName string) error
+	DeleteAuthorizationPolicy(ctx context.Context, clientID, policyID, realmName string) error
+
+	CreateClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) (string, error)
+	GetClientScope(ctx context.Context, clientScopeID, realmName string) (*v1alpha1.KeycloakAPIClientScope, error)
+	UpdateClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+	DeleteClientScope(ctx context.Context, clientScopeID, realmName string) error
+	ListClientScopeProtocolMappers(ctx context.Context, clientScopeID, realmName string) ([]v1alpha1.KeycloakProtocolMapper, error)
+	CreateClientScopeProtocolMapper(ctx context.Context, clientScopeID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) (string, error)
+	UpdateClientScopeProtocolMapper(ctx context.Context, clientScopeID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) error
+	DeleteClientScopeProtocolMapper(ctx context.Context, clientScopeID, mapperID, realmName string) error
+	ListClientScopeScopeMappings(ctx context.Context, clientScopeID, realmName string) (*v1alpha1.MappingsRepresentation, error)
+	CreateClientScopeRealmScopeMappings(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, mappings *[]v1alpha1.RoleRepresentation, realmName string) error
+	DeleteClientScopeRealmScopeMappings(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, mappings *[]v1alpha1.RoleRepresentation, realmName string) error
+	CreateClientScopeClientScopeMappings(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error
+	DeleteClientScopeClientScopeMappings(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error
+	ListRealmDefaultClientScopes(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error)
+	ListRealmOptionalClientScopes(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error)
+	UpdateRealmDefaultClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+	DeleteRealmDefaultClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+	UpdateRealmOptionalClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+	DeleteRealmOptionalClientScope(ctx context.Context, clientScope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+
+	CreateGroup(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, realmName string) (string, error)
+	CreateChildGroup(ctx context.Context, parentGroupID string, group *v1alpha1.KeycloakAPIGroup, realmName string) (string, error)
+	GetGroupByPath(ctx context.Context, path, realmName string) (*v1alpha1.KeycloakAPIGroup, error)
+	UpdateGroup(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, realmName string) error
+	DeleteGroup(ctx context.Context, groupID, realmName string) error
+	ListGroupRoleMappings(ctx context.Context, groupID, realmName string) (*v1alpha1.MappingsRepresentation, error)
+	CreateGroupRealmRoleMappings(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, mappings *[]v1alpha1.RoleRepresentation, realmName string) error
+	DeleteGroupRealmRoleMappings(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, mappings *[]v1alpha1.RoleRepresentation, realmName string) error
+	CreateGroupClientRoleMappings(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error
+	DeleteGroupClientRoleMappings(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, mappings *v1alpha1.ClientMappingsRepresentation, realmName string) error
+	ListRealmDefaultGroups(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIGroup, error)
+	UpdateRealmDefaultGroup(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, realmName string) error
+	DeleteRealmDefaultGroup(ctx context.Context, group *v1alpha1.KeycloakAPIGroup, realmName string) error
+
+	CreateUser(ctx context.Context, user *v1alpha1.KeycloakAPIUser, realmName string) (string, error)
+	GetUserByUsername(ctx context.Context, userName, realmName string) (*v1alpha1.KeycloakAPIUser, error)
+	UpdateUser(ctx context.Context, user *v1alpha1.KeycloakAPIUser, realmName string) error
+	DeleteUser(ctx context.Context, userID, realmName string) error
+	ListUserGroups(ctx context.Context, userID, realmName string) ([]v1alpha1.KeycloakAPIGroup, error)
+	AddUserToGroup(ctx context.Context, userID string, group *v1alpha1.KeycloakAPIGroup, realmName string) error
+	RemoveUserFromGroup(ctx context.Context, userID string, group *v1alpha1.KeycloakAPIGroup, realmName string) error
+
+	CreateIdentityProvider(ctx context.Context, identityProvider *v1alpha1.KeycloakAPIIdentityProvider, realmName string) (string, error)
+	GetIdentityProvider(ctx context.Context, alias, realmName string) (*v1alpha1.KeycloakAPIIdentityProvider, error)
+	UpdateIdentityProvider(ctx context.Context, identityProvider *v1alpha1.KeycloakAPIIdentityProvider, realmName string) error
+	DeleteIdentityProvider(ctx context.Context, alias, realmName string) error
+	ListIdentityProviderMappers(ctx context.Context, alias, realmName string) ([]v1alpha1.KeycloakAPIIdentityProviderMapper, error)
+	CreateIdentityProviderMapper(ctx context.Context, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realmName string) (string, error)
+	UpdateIdentityProviderMapper(ctx context.Context, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realmName string) error
+	DeleteIdentityProviderMapper(ctx context.Context, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realmName string) error
+
+	ListAuthenticationFlows(ctx context.Context, realmName string) ([]v1alpha1.KeycloakAPIAuthenticationFlow, error)
+	CreateAuthenticationFlow(ctx context.Context, flow *v1alpha1.KeycloakAPIAuthenticationFlow, realmName string) (string, error)
+	UpdateAuthenticationFlow(ctx context.Context, flow *v1alpha1.KeycloakAPIAuthenticationFlow, realmName string) error
+	DeleteAuthenticationFlow(ctx context.Context, flowID, realmName string) error
+	ListAuthenticationExecutions(ctx context.Context, flowAlias, realmName string) ([]v1alpha1.KeycloakAPIAuthenticationExecutionInfo, error)
+	CreateAuthenticationExecution(ctx context.Context, flowAlias, authenticator, realmName string) (string, error)
+	CreateAuthenticationSubFlow(ctx context.Context, flowAlias string, subFlow *v1alpha1.KeycloakAuthenticationSubFlow, realmName string) (string, error)
+	UpdateAuthenticationExecution(ctx context.Context, flowAlias string, execution *v1alpha1.KeycloakAPIAuthenticationExecutionInfo, realmName string) error
+	DeleteAuthenticationExecution(ctx context.Context, executionID, realmName string) error
+	CreateAuthenticatorConfig(ctx context.Context, executionID string, config *v1alpha1.KeycloakAPIAuthenticatorConfig, realmName string) (string, error)
+	GetAuthenticatorConfig(ctx context.Context, configID, realmName string) (*v1alpha1.KeycloakAPIAuthenticatorConfig, error)
+	UpdateAuthenticatorConfig(ctx context.Context, config *v1alpha1.KeycloakAPIAuthenticatorConfig, realmName string) error
+	DeleteAuthenticatorConfig(ctx context.Context, configID, realmName string) error
+	GetRealmFlowBindings(ctx context.Context, realmName string) (*v1alpha1.KeycloakAPIRealmFlowBindings, error)
+	UpdateRealmFlowBindings(ctx context.Context, bindings *v1alpha1.KeycloakAPIRealmFlowBindings, realmName string) error
+
+	CreateFederatedIdentity(ctx context.Context, fid v1alpha1.FederatedIdentity, userID string, realmName string) (string, error)
+	RemoveFederatedIdentity(ctx context.Context, fid v1alpha1.FederatedIdentity, userID string, realmName string) error
+	GetUserFederatedIdentities(ctx context.Context, userName string, realmName string) ([]v1alpha1.FederatedIdentity, error)
+
+	CreateUserClientRole(ctx context.Context, role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) (string, error)
+	ListUserClientRoles(ctx context.Context, realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error)
+	ListAvailableUserClientRoles(ctx context.Context, realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error)
+	DeleteUserClientRole(ctx context.Context, role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) error
+
+	CreateUserRealmRole(ctx context.Context, role *v1alpha1.KeycloakUserRole, realmName, userID string) (string, error)
+	ListUserRealmRoles(ctx context.Context, realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error)
+	ListAvailableUserRealmRoles(ctx context.Context, realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error)
+	DeleteUserRealmRole(ctx context.Context, role *v1alpha1.KeycloakUserRole, realmName, userID string) error
+
+	GetServiceAccountUser(ctx context.Context, realmName, clientID string) (*v1alpha1.KeycloakAPIUser, error)
 }
 
 // check if Client implements KeycloakInterface
@@ -824,68 +1727,165 @@ var _ KeycloakInterface = &Client{}
 
 // KeycloakClientFactory interface
 type KeycloakClientFactory interface {
-	AuthenticatedClient(kc v1alpha1.Keycloak) (KeycloakInterface, error)
+	AuthenticatedClient(ctx context.Context, kc v1alpha1.Keycloak) (KeycloakInterface, error)
 }
 
 type LocalConfigKeycloakFactory struct {
 }
 
-// AuthenticatedClient returns an authenticated client for requesting endpoints from the Keycloak api
-func (i *LocalConfigKeycloakFactory) AuthenticatedClient(kc v1alpha1.Keycloak, insecureSsl bool) (KeycloakInterface, error) {
-	config, err := config2.GetConfig()
+// AuthenticatedClient returns an authenticated client for requesting endpoints from the Keycloak api, the result of
+// the login is kept in Contacts
+func (i *LocalConfigKeycloakFactory) AuthenticatedClient(ctx context.Context, kc v1alpha1.Keycloak, insecureSsl bool) (KeycloakInterface, error) {
+	client, err := authenticatedClient(ctx, kc, insecureSsl)
+	recordContact(ctx, kc, err)
 	if err != nil {
 		return nil, err
 	}
+	return client, nil
+}
 
-	secretClient, err := kubernetes.NewForConfig(config)
+func authenticatedClient(ctx context.Context, kc v1alpha1.Keycloak, insecureSsl bool) (*Client, error) {
+	config, err := config2.GetConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	var credentialSecret string
-	if kc.Spec.External.Enabled {
-		credentialSecret = "credential-" + kc.Name
-	} else {
-		credentialSecret = kc.Status.CredentialSecret
+	secretClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
 	}
 
-	adminCreds, err := secretClient.CoreV1().Secrets(kc.Namespace).Get(context.TODO(), credentialSecret, v12.GetOptions{})
+	adminCreds, err := secretClient.CoreV1().Secrets(kc.Namespace).Get(ctx, KeycloakCredentialSecretName(kc), v12.GetOptions{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get the admin credentials")
 	}
 	user := string(adminCreds.Data[model.AdminUsernameProperty])
 	pass := string(adminCreds.Data[model.AdminPasswordProperty])
 
-	var serverCert []byte = nil
-	if !insecureSsl {
-		serverCert, err = getKCServerCert(secretClient, kc)
-		if err != nil {
-			return nil, err
-		}
+	settings, err := getRequesterSettings(ctx, secretClient, kc)
+	if err != nil {
+		return nil, err
 	}
+	settings.insecureSkipVerify = settings.insecureSkipVerify || insecureSsl
 
-	requester, err := defaultRequester(serverCert)
+	requester, err := defaultRequester(settings)
 	if err != nil {
 		return nil, err
 	}
+	metricsName := keycloakMetricsName(kc)
+	requester = newInstrumentedRequester(requester, metricsName)
 
-	kcURL, err := getKeycloakURL(kc, requester)
+	kcURL, err := getKeycloakURL(ctx, kc, newTracingRequester(requester, metricsName))
 	if err != nil {
 		return nil, err
 	}
 
+	// each attempt is counted in the metrics, the span covers the request with all its retries
+	options := KeycloakRequestOptions
+	requester = newInFlightLimitedRequester(requester, keycloakInFlightSlots(metricsName, options))
+	requester = newRateLimitedRequester(requester, keycloakRateLimiter(metricsName, options))
+	requester = newRetryingRequester(requester, options)
 	client := &Client{
 		URL:       kcURL,
-		requester: requester,
+		requester: newTracingRequester(requester, metricsName),
 	}
-	if err := client.login(user, pass); err != nil {
+	err = client.login(ctx, user, pass)
+	recordLogin(metricsName, err)
+	if err != nil {
 		return nil, err
 	}
 	return client, nil
 }
 
-func getKCServerCert(secretClient *kubernetes.Clientset, kc v1alpha1.Keycloak) ([]byte, error) {
-	sslCertsSecret, err := secretClient.CoreV1().Secrets(kc.Namespace).Get(context.TODO(), model.ServingCertSecretName, v12.GetOptions{})
+// KeycloakCredentialSecretName returns the name of the Secret with the admin credentials of the keycloak, it is kept in
+// the namespace of the keycloak
+func KeycloakCredentialSecretName(kc v1alpha1.Keycloak) string {
+	if kc.Spec.External.Enabled {
+		return model.KeycloakAdminSecretPrefix + kc.Name
+	}
+	return kc.Status.CredentialSecret
+}
+
+// getRequesterSettings reads the HTTP settings of the requests to the keycloak from its spec, the CA bundle and the
+// client certificate from the Secrets or ConfigMaps in the namespace of the keycloak
+func getRequesterSettings(ctx context.Context, secretClient *kubernetes.Clientset, kc v1alpha1.Keycloak) (requesterSettings, error) {
+	external := kc.Spec.External
+	settings := requesterSettings{insecureSkipVerify: external.InsecureSkipVerify}
+	if external.Timeout != nil {
+		settings.timeout = external.Timeout.Duration
+	}
+
+	if external.ProxyURL != "" {
+		proxyURL, err := url.Parse(external.ProxyURL)
+		if err != nil {
+			return settings, PermanentErrorf("invalid proxyURL %v: %v", external.ProxyURL, err)
+		}
+		settings.proxyURL = proxyURL
+	}
+
+	var err error
+	if external.CABundle != nil {
+		settings.caBundle, err = getCABundle(ctx, secretClient, kc.Namespace, external.CABundle)
+	} else if !external.InsecureSkipVerify {
+		settings.caBundle, err = getKCServerCert(ctx, secretClient, kc)
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	if external.ClientCertificateSecret != "" {
+		settings.clientCertificate, err = getClientCertificate(ctx, secretClient, kc.Namespace, external.ClientCertificateSecret)
+		if err != nil {
+			return settings, err
+		}
+	}
+	return settings, nil
+}
+
+func getCABundle(ctx context.Context, secretClient *kubernetes.Clientset, namespace string, bundle *v1alpha1.KeycloakCABundle) ([]byte, error) {
+	switch {
+	case bundle.SecretKeyRef != nil:
+		ref := bundle.SecretKeyRef
+		secret, err := secretClient.CoreV1().Secrets(namespace).Get(ctx, ref.Name, v12.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the ca bundle secret %v", ref.Name)
+		}
+		if value, ok := secret.Data[ref.Key]; ok {
+			return value, nil
+		}
+		return nil, errors.Errorf("key %v not found in secret %v/%v", ref.Key, namespace, ref.Name)
+	case bundle.ConfigMapKeyRef != nil:
+		ref := bundle.ConfigMapKeyRef
+		configMap, err := secretClient.CoreV1().ConfigMaps(namespace).Get(ctx, ref.Name, v12.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the ca bundle config map %v", ref.Name)
+		}
+		if value, ok := configMap.Data[ref.Key]; ok {
+			return []byte(value), nil
+		}
+		if value, ok := configMap.BinaryData[ref.Key]; ok {
+			return value, nil
+		}
+		return nil, errors.Errorf("key %v not found in config map %v/%v", ref.Key, namespace, ref.Name)
+	default:
+		return nil, PermanentErrorf("either secretKeyRef or configMapKeyRef of the caBundle needs to be set")
+	}
+}
+
+func getClientCertificate(ctx context.Context, secretClient *kubernetes.Clientset, namespace, secretName string) (*tls.Certificate, error) {
+	secret, err := secretClient.CoreV1().Secrets(namespace).Get(ctx, secretName, v12.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the client certificate secret %v", secretName)
+	}
+	certificate, err := tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid client certificate in secret %v/%v", namespace, secretName)
+	}
+	return &certificate, nil
+}
+
+func getKCServerCert(ctx context.Context, secretClient *kubernetes.Clientset, kc v1alpha1.Keycloak) ([]byte, error) {
+	sslCertsSecret, err := secretClient.CoreV1().Secrets(kc.Namespace).Get(ctx, model.ServingCertSecretName, v12.GetOptions{})
 	switch {
 	case err == nil:
 		return sslCertsSecret.Data["tls.crt"], nil
@@ -899,12 +1899,12 @@ func getKCServerCert(secretClient *kubernetes.Clientset, kc v1alpha1.Keycloak) (
 // At normal conditions, Keycloak should be accessible via the internalURL. However, there are some corner cases (like
 // operator running locally during development or services being inaccessible due to network policies) which requires
 // use of externalURL.
-func getKeycloakURL(kc v1alpha1.Keycloak, requester Requester) (string, error) {
+func getKeycloakURL(ctx context.Context, kc v1alpha1.Keycloak, requester Requester) (string, error) {
 	var kcURL string
 	var err error
 
 	if kcURL == "" && kc.Status.ExternalURL != "" {
-		kcURL, err = validateKeycloakURL(kc.Status.ExternalURL, requester)
+		kcURL, err = validateKeycloakURL(ctx, kc.Status.ExternalURL, requester)
 		if err != nil {
 			return "", err
 		}
@@ -919,8 +1919,9 @@ func getKeycloakURL(kc v1alpha1.Keycloak, requester Requester) (string, error) {
 	return kcURL, nil
 }
 
-func validateKeycloakURL(url string, requester Requester) (string, error) {
-	req, err := http.NewRequest(
+func validateKeycloakURL(ctx context.Context, url string, requester Requester) (string, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		url,
 		nil,
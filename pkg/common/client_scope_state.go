@@ -0,0 +1,89 @@
+package common
+
+import (
+	"context"
+	"sort"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type ClientScopeState struct {
+	ClientScope               *kc.KeycloakAPIClientScope
+	ScopeMappings             *kc.MappingsRepresentation
+	RealmDefaultClientScopes  []kc.KeycloakAPIClientScope
+	RealmOptionalClientScopes []kc.KeycloakAPIClientScope
+	// Roles and clients of the realm, used to resolve the roles and clients of the scope mappings
+	AvailableRoles
+	Context  context.Context
+	Realm    *kc.KeycloakRealm
+	Keycloak kc.Keycloak
+}
+
+func NewClientScopeState(context context.Context, realm *kc.KeycloakRealm, keycloak kc.Keycloak) *ClientScopeState {
+	return &ClientScopeState{
+		Context:  context,
+		Realm:    realm,
+		Keycloak: keycloak,
+	}
+}
+
+func (i *ClientScopeState) Read(context context.Context, cr *kc.KeycloakClientScope, realmClient KeycloakInterface, controllerClient client.Client) error {
+	clientScopes, err := realmClient.ListAvailableClientScopes(context, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	err = i.readScopeMappingReferences(context, cr, realmClient)
+	if err != nil {
+		return err
+	}
+
+	i.ClientScope = nil
+	for _, clientScope := range clientScopes {
+		if clientScope.Name == cr.Spec.ClientScope.Name {
+			i.ClientScope = clientScope.DeepCopy()
+			break
+		}
+	}
+
+	if i.ClientScope == nil {
+		cr.Spec.ClientScope.ID = ""
+		return nil
+	}
+
+	cr.Spec.ClientScope.ID = i.ClientScope.ID
+
+	i.ClientScope.ProtocolMappers, err = realmClient.ListClientScopeProtocolMappers(context, i.ClientScope.ID, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.ScopeMappings, err = realmClient.ListClientScopeScopeMappings(context, i.ClientScope.ID, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.RealmDefaultClientScopes, err = realmClient.ListRealmDefaultClientScopes(context, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.RealmOptionalClientScopes, err = realmClient.ListRealmOptionalClientScopes(context, i.Realm.Spec.Realm.Realm)
+	return err
+}
+
+// readScopeMappingReferences reads the roles and clients of the realm the scope mappings refer to by name
+func (i *ClientScopeState) readScopeMappingReferences(context context.Context, cr *kc.KeycloakClientScope, realmClient KeycloakInterface) error {
+	if cr.Spec.ScopeMappings == nil || (len(cr.Spec.ScopeMappings.RealmMappings) == 0 && len(cr.Spec.ScopeMappings.ClientMappings) == 0) {
+		return nil
+	}
+
+	clientIDs := make([]string, 0, len(cr.Spec.ScopeMappings.ClientMappings))
+	for clientID := range cr.Spec.ScopeMappings.ClientMappings {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+
+	return i.readAvailableRoles(context, realmClient, i.Realm.Spec.Realm.Realm, clientIDs)
+}
@@ -2,6 +2,8 @@ package common
 
 import (
 	"context"
+	"sort"
+	"strings"
 
 	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
 	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
@@ -16,15 +18,25 @@ type ClientState struct {
 	Context                 context.Context
 	Realm                   *kc.KeycloakRealm
 	Roles                   []kc.RoleRepresentation
+	ProtocolMappers         []kc.KeycloakProtocolMapper
 	DefaultRoleID           string
 	DefaultRoles            []kc.RoleRepresentation
 	ScopeMappings           *kc.MappingsRepresentation
-	AvailableClientScopes   []kc.KeycloakClientScope
-	DefaultClientScopes     []kc.KeycloakClientScope
-	OptionalClientScopes    []kc.KeycloakClientScope
+	AvailableClientScopes   []kc.KeycloakAPIClientScope
+	DefaultClientScopes     []kc.KeycloakAPIClientScope
+	OptionalClientScopes    []kc.KeycloakAPIClientScope
 	DeprecatedClientSecret  *v1.Secret // keycloak-client-secret-<clientID>
 	Keycloak                kc.Keycloak
 	ServiceAccountUserState *UserState
+	// Authorization settings of the client, only read when they are managed by the resource
+	AuthorizationSettings *kc.KeycloakResourceServer
+	// Roles and clients of the realm, used to resolve the roles and clients referenced by name
+	AvailableRoles
+	// Authentication flows of the realm, only read when the client overrides flow bindings
+	AuthenticationFlows []kc.KeycloakAPIAuthenticationFlow
+	// SAML metadata of the identity provider and the ConfigMap it is published to, only read when it is published
+	SAMLIdentityProviderMetadata          []byte
+	SAMLIdentityProviderMetadataConfigMap *v1.ConfigMap
 }
 
 func NewClientState(context context.Context, realm *kc.KeycloakRealm, keycloak kc.Keycloak) *ClientState {
@@ -36,11 +48,27 @@ func NewClientState(context context.Context, realm *kc.KeycloakRealm, keycloak k
 }
 
 func (i *ClientState) Read(context context.Context, cr *kc.KeycloakClient, realmClient KeycloakInterface, controllerClient client.Client) error {
+	attributes := append(ResourceAttributes(cr),
+		RealmAttribute.String(i.Realm.Spec.Realm.Realm),
+		KeycloakInstanceAttribute(i.Keycloak))
+	context, span := StartSpan(context, "ClientState.Read", attributes...)
+	err := i.read(context, cr, realmClient, controllerClient)
+	EndSpan(span, err)
+	return err
+}
+
+func (i *ClientState) read(context context.Context, cr *kc.KeycloakClient, realmClient KeycloakInterface, controllerClient client.Client) error {
+	// references are resolved for new clients as well
+	err := i.readReferences(context, cr, realmClient)
+	if err != nil {
+		return err
+	}
+
 	if cr.Spec.Client.ID == "" {
 		return nil
 	}
 
-	client, err := realmClient.GetClient(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	client, err := realmClient.GetClient(context, cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
 
 	if err != nil {
 		return err
@@ -51,7 +79,7 @@ func (i *ClientState) Read(context context.Context, cr *kc.KeycloakClient, realm
 	// CR could have updated with new secret, so set saved secret to Spec only when empty
 	// Otherwise let reconcile loop to update secret with desired secret in CR
 	if cr.Spec.Client.Secret == "" {
-		clientSecret, err := realmClient.GetClientSecret(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+		clientSecret, err := realmClient.GetClientSecret(context, cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
 		if err != nil {
 			return err
 		}
@@ -76,34 +104,53 @@ func (i *ClientState) Read(context context.Context, cr *kc.KeycloakClient, realm
 		return nil
 	}
 
-	i.Roles, err = realmClient.ListClientRoles(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	i.Roles, err = realmClient.ListClientRoles(context, cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.ProtocolMappers, err = realmClient.ListClientProtocolMappers(context, cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
 	if err != nil {
 		return err
 	}
 
-	i.ScopeMappings, err = realmClient.ListScopeMappings(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	i.ScopeMappings, err = realmClient.ListScopeMappings(context, cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
 	if err != nil {
 		return err
 	}
 
-	err = i.readClientScopes(cr, realmClient)
+	err = i.readClientScopes(context, cr, realmClient)
 	if err != nil {
 		return err
 	}
 
-	err = i.readDefaultRoles(cr, realmClient)
+	err = i.readDefaultRoles(context, cr, realmClient)
 	if err != nil {
 		return err
 	}
 
+	if i.Client.AuthorizationServicesEnabled && cr.Spec.Client.AuthorizationSettings != nil {
+		err = i.readAuthorizationSettings(context, cr, realmClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.SAML != nil && cr.Spec.SAML.IdentityProviderMetadataConfigMap != "" {
+		err = i.readSAMLIdentityProviderMetadata(context, cr, realmClient, controllerClient)
+		if err != nil {
+			return err
+		}
+	}
+
 	if i.Client.ServiceAccountsEnabled {
-		user, err := realmClient.GetServiceAccountUser(i.Realm.Spec.Realm.Realm, cr.Spec.Client.ID)
+		user, err := realmClient.GetServiceAccountUser(context, i.Realm.Spec.Realm.Realm, cr.Spec.Client.ID)
 		if err != nil {
 			return err
 		}
 
 		i.ServiceAccountUserState = NewUserState(i.Keycloak)
-		err = i.ServiceAccountUserState.ReadWithExistingAPIUser(realmClient, controllerClient, user, *i.Realm)
+		err = i.ServiceAccountUserState.ReadWithExistingAPIUser(context, realmClient, controllerClient, user, *i.Realm)
 		if err != nil {
 			return err
 		}
@@ -112,27 +159,127 @@ func (i *ClientState) Read(context context.Context, cr *kc.KeycloakClient, realm
 	return nil
 }
 
-func (i *ClientState) readClientScopes(cr *kc.KeycloakClient, realmClient KeycloakInterface) (err error) {
+// readReferences reads the roles, clients and authentication flows of the realm the client refers to by name
+func (i *ClientState) readReferences(context context.Context, cr *kc.KeycloakClient, realmClient KeycloakInterface) (err error) {
+	realmName := i.Realm.Spec.Realm.Realm
+
+	if len(cr.Spec.Client.AuthenticationFlowBindingOverrides) > 0 {
+		i.AuthenticationFlows, err = realmClient.ListAuthenticationFlows(context, realmName)
+		if err != nil {
+			return err
+		}
+	}
+
+	// client roles are read for the clients of the scope mappings and of role policies
+	clientIDs := map[string]bool{}
+	if cr.Spec.ScopeMappings != nil {
+		for clientID := range cr.Spec.ScopeMappings.ClientMappings {
+			clientIDs[clientID] = true
+		}
+	}
+	referencesRoles := cr.Spec.ScopeMappings != nil && (len(cr.Spec.ScopeMappings.RealmMappings) > 0 || len(cr.Spec.ScopeMappings.ClientMappings) > 0)
+	if cr.Spec.Client.AuthorizationSettings != nil {
+		for _, policy := range cr.Spec.Client.AuthorizationSettings.Policies {
+			switch policy.Type {
+			case "role":
+				referencesRoles = true
+				for _, role := range model.AuthorizationPolicyRoles(policy.Config) {
+					if separator := strings.Index(role.ID, "/"); separator > 0 {
+						clientIDs[role.ID[:separator]] = true
+					}
+				}
+			case "client":
+				referencesRoles = true
+			}
+		}
+	}
+
+	if !referencesRoles {
+		return nil
+	}
+
+	sortedClientIDs := make([]string, 0, len(clientIDs))
+	for clientID := range clientIDs {
+		sortedClientIDs = append(sortedClientIDs, clientID)
+	}
+	sort.Strings(sortedClientIDs)
+	return i.readAvailableRoles(context, realmClient, realmName, sortedClientIDs)
+}
+
+func (i *ClientState) readClientScopes(context context.Context, cr *kc.KeycloakClient, realmClient KeycloakInterface) (err error) {
 	// It is not strictly a property of the client but rather of the realm.
 	// However could not figure out a better way to convey it to populate default and optional
 	// client scopes which requires client scope IDs.
-	i.AvailableClientScopes, err = realmClient.ListAvailableClientScopes(i.Realm.Spec.Realm.Realm)
+	i.AvailableClientScopes, err = realmClient.ListAvailableClientScopes(context, i.Realm.Spec.Realm.Realm)
 	if err != nil {
 		return err
 	}
 
-	i.DefaultClientScopes, err = realmClient.ListDefaultClientScopes(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	i.DefaultClientScopes, err = realmClient.ListDefaultClientScopes(context, cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
 	if err != nil {
 		return err
 	}
 
-	i.OptionalClientScopes, err = realmClient.ListOptionalClientScopes(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	i.OptionalClientScopes, err = realmClient.ListOptionalClientScopes(context, cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// readAuthorizationSettings reads the resource server of the client with its scopes, resources and policies.
+// Policies refer to their associated policies, resources and scopes by name.
+func (i *ClientState) readAuthorizationSettings(context context.Context, cr *kc.KeycloakClient, realmClient KeycloakInterface) (err error) {
+	realmName := i.Realm.Spec.Realm.Realm
+	clientID := cr.Spec.Client.ID
+
+	i.AuthorizationSettings, err = realmClient.GetResourceServer(context, clientID, realmName)
+	if err != nil || i.AuthorizationSettings == nil {
+		return err
+	}
+
+	i.AuthorizationSettings.Scopes, err = realmClient.ListAuthorizationScopes(context, clientID, realmName)
+	if err != nil {
+		return err
+	}
+
+	i.AuthorizationSettings.Resources, err = realmClient.ListAuthorizationResources(context, clientID, realmName)
+	if err != nil {
+		return err
+	}
+
+	policies, err := realmClient.ListAuthorizationPolicies(context, clientID, realmName)
+	if err != nil {
+		return err
+	}
+
+	for index := range policies {
+		policy := &policies[index]
+
+		associatedPolicies, err := realmClient.ListAssociatedAuthorizationPolicies(context, clientID, policy.ID, realmName)
+		if err != nil {
+			return err
+		}
+		policy.Policies = nil
+		for _, associatedPolicy := range associatedPolicies {
+			policy.Policies = append(policy.Policies, associatedPolicy.Name)
+		}
+
+		policy.Resources, err = realmClient.ListAuthorizationPolicyResources(context, clientID, policy.ID, realmName)
+		if err != nil {
+			return err
+		}
+
+		policy.Scopes, err = realmClient.ListAuthorizationPolicyScopes(context, clientID, policy.ID, realmName)
+		if err != nil {
+			return err
+		}
+	}
+	i.AuthorizationSettings.Policies = policies
+
+	return nil
+}
+
 func (i *ClientState) readClientSecret(context context.Context, cr *kc.KeycloakClient, clientSpec *kc.KeycloakAPIClient, controllerClient client.Client) error {
 	key := model.ClientSecretSelector(cr)
 	secret := model.ClientSecret(cr)
@@ -149,15 +296,35 @@ func (i *ClientState) readClientSecret(context context.Context, cr *kc.KeycloakC
 	return nil
 }
 
-func (i *ClientState) readDefaultRoles(cr *kc.KeycloakClient, realmClient KeycloakInterface) error {
+// readSAMLIdentityProviderMetadata reads the SAML metadata of the identity provider of the client and the ConfigMap it
+// is published to
+func (i *ClientState) readSAMLIdentityProviderMetadata(context context.Context, cr *kc.KeycloakClient, realmClient KeycloakInterface, controllerClient client.Client) (err error) {
+	i.SAMLIdentityProviderMetadata, err = realmClient.GetClientInstallation(context, cr.Spec.Client.ID, model.SAMLIdentityProviderDescriptorProvider, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	configMap := &v1.ConfigMap{}
+	err = controllerClient.Get(context, model.SAMLIdentityProviderMetadataConfigMapSelector(cr), configMap)
+	if err != nil {
+		if !apiErrors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		i.SAMLIdentityProviderMetadataConfigMap = configMap.DeepCopy()
+	}
+	return nil
+}
+
+func (i *ClientState) readDefaultRoles(context context.Context, cr *kc.KeycloakClient, realmClient KeycloakInterface) error {
 	// we can't use state.Realm as it is the CR, not actual Realm state, and is missing defaultRole
-	realm, err := realmClient.GetRealm(i.Realm.Spec.Realm.Realm)
+	realm, err := realmClient.GetRealm(context, i.Realm.Spec.Realm.Realm)
 	if err != nil {
 		return err
 	}
 
 	i.DefaultRoleID = realm.Spec.Realm.DefaultRole.ID
-	i.DefaultRoles, err = realmClient.ListRealmRoleClientRoleComposites(i.Realm.Spec.Realm.Realm, i.DefaultRoleID, cr.Spec.Client.ID)
+	i.DefaultRoles, err = realmClient.ListRealmRoleClientRoleComposites(context, i.Realm.Spec.Realm.Realm, i.DefaultRoleID, cr.Spec.Client.ID)
 	return err
 }
 
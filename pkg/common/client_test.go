@@ -1,15 +1,20 @@
 package common
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/pem"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 
 	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,7 +54,7 @@ func TestClient_CreateRealm(t *testing.T) {
 	realm := getDummyRealm()
 
 	// when
-	_, err := client.CreateRealm(realm)
+	_, err := client.CreateRealm(context.TODO(), realm)
 
 	// then
 	// no error expected
@@ -75,7 +80,7 @@ func TestClient_DeleteRealmRealm(t *testing.T) {
 	}
 
 	// when
-	err := client.DeleteRealm(realm.Spec.Realm.Realm)
+	err := client.DeleteRealm(context.TODO(), realm.Spec.Realm.Realm)
 
 	// then
 	// correct path expected on httptest server
@@ -111,7 +116,7 @@ func TestClient_login(t *testing.T) {
 	}
 
 	// when
-	err := client.login("dummy", "dummy")
+	err := client.login(context.TODO(), "dummy", "dummy")
 
 	// then
 	// token must be set on the client now
@@ -131,7 +136,7 @@ func TestClient_useKeycloakServerCertificate(t *testing.T) {
 
 	pemCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
 
-	requester, err := defaultRequester(pemCert)
+	requester, err := defaultRequester(requesterSettings{caBundle: pemCert})
 	assert.NoError(t, err)
 	httpClient, ok := requester.(*http.Client)
 	assert.True(t, ok)
@@ -144,3 +149,417 @@ func TestClient_useKeycloakServerCertificate(t *testing.T) {
 	defer resp.Body.Close()
 	assert.Equal(t, resp.StatusCode, 200)
 }
+
+func TestClient_verifyKeycloakServerCertificateByDefault(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(200)
+	})
+	ts := httptest.NewTLSServer(handler)
+	defer ts.Close()
+
+	verifying, err := defaultRequester(requesterSettings{})
+	assert.NoError(t, err)
+	insecure, err := defaultRequester(requesterSettings{insecureSkipVerify: true, timeout: time.Minute})
+	assert.NoError(t, err)
+
+	// when
+	request, err := http.NewRequest("GET", ts.URL, nil)
+	assert.NoError(t, err)
+	_, verifyingErr := verifying.Do(request)
+	insecureResp, insecureErr := insecure.Do(request)
+
+	// then
+	// the self-signed certificate is only accepted when verification is skipped explicitly
+	assert.ErrorContains(t, verifyingErr, "certificate")
+	assert.Equal(t, 10*time.Second, verifying.(*http.Client).Timeout)
+	assert.NoError(t, insecureErr)
+	defer insecureResp.Body.Close()
+	assert.Equal(t, 200, insecureResp.StatusCode)
+	assert.Equal(t, time.Minute, insecure.(*http.Client).Timeout)
+}
+
+func TestClient_useClientCertificate(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Len(t, req.TLS.PeerCertificates, 1)
+		assert.Equal(t, "keycloak-controller", req.TLS.PeerCertificates[0].Subject.CommonName)
+		w.WriteHeader(200)
+	})
+	ts := httptest.NewUnstartedServer(handler)
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	secret, err := model.GenerateClientAuthenticationSecret(&v1alpha1.KeycloakClient{
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client:                  &v1alpha1.KeycloakAPIClient{ClientID: "keycloak-controller"},
+			ClientAuthenticationKey: &v1alpha1.ClientAuthenticationKey{SecretName: "mtls"},
+		},
+	}, time.Now())
+	assert.NoError(t, err)
+	certificate, err := tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+	assert.NoError(t, err)
+	pemCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	requester, err := defaultRequester(requesterSettings{caBundle: pemCert, clientCertificate: &certificate})
+	assert.NoError(t, err)
+
+	// when
+	request, err := http.NewRequest("GET", ts.URL, nil)
+	assert.NoError(t, err)
+	resp, err := requester.Do(request)
+
+	// then
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestClient_useProxy(t *testing.T) {
+	// given
+	// the proxy answers plain http requests for any host
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "keycloak.example.com", req.URL.Host)
+		w.WriteHeader(200)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	assert.NoError(t, err)
+
+	requester, err := defaultRequester(requesterSettings{proxyURL: proxyURL})
+	assert.NoError(t, err)
+
+	// when
+	request, err := http.NewRequest("GET", "http://keycloak.example.com/auth", nil)
+	assert.NoError(t, err)
+	resp, err := requester.Do(request)
+
+	// then
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestClient_CreateClientScope(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+	clientScope := &v1alpha1.KeycloakAPIClientScope{
+		Name:     "dummy",
+		Protocol: "openid-connect",
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/auth/admin/realms/%s/client-scopes", realm.Spec.Realm.Realm), req.URL.Path)
+		assert.Equal(t, http.MethodPost, req.Method)
+		w.Header().Set("Location", fmt.Sprintf("%s/auth/admin/realms/%s/client-scopes/dummyID", req.Host, realm.Spec.Realm.Realm))
+		w.WriteHeader(201)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	uid, err := client.CreateClientScope(context.TODO(), clientScope, realm.Spec.Realm.Realm)
+
+	// then
+	// correct path expected on httptest server
+	// id of the new client scope taken from the location header
+	assert.NoError(t, err)
+	assert.Equal(t, "dummyID", uid)
+}
+
+func TestClient_UpdateRealmOptionalClientScope(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+	clientScope := &v1alpha1.KeycloakAPIClientScope{
+		ID:   "dummyID",
+		Name: "dummy",
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/auth/admin/realms/%s/default-optional-client-scopes/%s", realm.Spec.Realm.Realm, clientScope.ID), req.URL.Path)
+		assert.Equal(t, http.MethodPut, req.Method)
+		w.WriteHeader(204)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	err := client.UpdateRealmOptionalClientScope(context.TODO(), clientScope, realm.Spec.Realm.Realm)
+
+	// then
+	// correct path expected on httptest server
+	assert.NoError(t, err)
+}
+
+func TestClient_GetRealmRole(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/auth/admin/realms/%s/roles/order manager", realm.Spec.Realm.Realm), req.URL.Path)
+		json, err := jsoniter.Marshal(v1alpha1.RoleRepresentation{ID: "dummyID", Name: "order manager"})
+		assert.NoError(t, err)
+		w.WriteHeader(200)
+		_, err = w.Write(json)
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	role, err := client.GetRealmRole(context.TODO(), "order manager", realm.Spec.Realm.Realm)
+
+	// then
+	// role names are escaped in the path
+	assert.NoError(t, err)
+	assert.Equal(t, "dummyID", role.ID)
+}
+
+func TestClient_GetGroupByPath(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/auth/admin/realms/%s/group-by-path/parent/child group", realm.Spec.Realm.Realm), req.URL.Path)
+		json, err := jsoniter.Marshal(v1alpha1.KeycloakAPIGroup{ID: "dummyID", Name: "child group", Path: "/parent/child group"})
+		assert.NoError(t, err)
+		w.WriteHeader(200)
+		_, err = w.Write(json)
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	group, err := client.GetGroupByPath(context.TODO(), "/parent/child group", realm.Spec.Realm.Realm)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "dummyID", group.ID)
+}
+
+func TestClient_CreateIdentityProviderMapper(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+	mapper := &v1alpha1.KeycloakAPIIdentityProviderMapper{
+		Name:                   "department",
+		IdentityProviderAlias:  "azure-ad",
+		IdentityProviderMapper: "oidc-user-attribute-idp-mapper",
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/auth/admin/realms/%s/identity-provider/instances/azure-ad/mappers", realm.Spec.Realm.Realm), req.URL.Path)
+		assert.Equal(t, http.MethodPost, req.Method)
+		w.Header().Set("Location", fmt.Sprintf("%s/mappers/dummyID", req.URL.Path))
+		w.WriteHeader(201)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	id, err := client.CreateIdentityProviderMapper(context.TODO(), mapper, realm.Spec.Realm.Realm)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "dummyID", id)
+}
+
+func TestClient_CreateAuthenticationExecution(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/auth/admin/realms/%s/authentication/flows/first broker login/executions/execution", realm.Spec.Realm.Realm), req.URL.Path)
+		assert.Equal(t, http.MethodPost, req.Method)
+		body := map[string]string{}
+		assert.NoError(t, jsoniter.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, "idp-review-profile", body["provider"])
+		w.Header().Set("Location", fmt.Sprintf("%s/auth/admin/realms/%s/authentication/executions/dummyID", req.Host, realm.Spec.Realm.Realm))
+		w.WriteHeader(201)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	id, err := client.CreateAuthenticationExecution(context.TODO(), "first broker login", "idp-review-profile", realm.Spec.Realm.Realm)
+
+	// then
+	// flow aliases are escaped in the path
+	assert.NoError(t, err)
+	assert.Equal(t, "dummyID", id)
+}
+
+func TestClient_ListAuthorizationResources(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/auth/admin/realms/%s/clients/dummyClientID/authz/resource-server/resource", realm.Spec.Realm.Realm), req.URL.Path)
+		assert.Equal(t, "-1", req.URL.Query().Get("max"))
+		w.WriteHeader(200)
+		_, err := w.Write([]byte(`[{"_id":"dummyID","name":"orders","attributes":{"department":["sales"]},"scopes":[{"id":"scopeID","name":"read"}]}]`))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	resources, err := client.ListAuthorizationResources(context.TODO(), "dummyClientID", realm.Spec.Realm.Realm)
+
+	// then
+	// attributes are returned by Keycloak with a list of values
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, "dummyID", resources[0].ID)
+	assert.Equal(t, map[string]string{"department": "sales"}, resources[0].Attributes)
+	assert.Len(t, resources[0].Scopes, 1)
+}
+
+func TestClient_ListClientProtocolMappers(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/auth/admin/realms/%s/clients/dummyClientID/protocol-mappers/models", realm.Spec.Realm.Realm), req.URL.Path)
+		assert.Equal(t, http.MethodGet, req.Method)
+		json, err := jsoniter.Marshal([]v1alpha1.KeycloakProtocolMapper{{ID: "dummyID", Name: "audience", ProtocolMapper: "oidc-audience-mapper"}})
+		assert.NoError(t, err)
+		w.WriteHeader(200)
+		_, err = w.Write(json)
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	mappers, err := client.ListClientProtocolMappers(context.TODO(), "dummyClientID", realm.Spec.Realm.Realm)
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, mappers, 1)
+	assert.Equal(t, "dummyID", mappers[0].ID)
+}
+
+func TestClient_Request_Cancelled(t *testing.T) {
+	// given
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// keycloak doesn't answer until the test is done
+		<-release
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer close(release)
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// when
+	start := time.Now()
+	_, err := client.GetRealm(ctx, "dummy")
+
+	// then
+	// the request is cancelled with the context
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClient_GetServerInfo(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/auth/admin/serverinfo", req.URL.Path)
+		assert.Equal(t, http.MethodGet, req.Method)
+		w.WriteHeader(200)
+		_, err := w.Write([]byte(`{
+			"systemInfo": {"version": "21.1.2"},
+			"profileInfo": {"name": "community", "disabledFeatures": ["TOKEN_EXCHANGE", "ADMIN_FINE_GRAINED_AUTHZ"]},
+			"providers": {
+				"authenticator": {"internal": true, "providers": {"auth-username-password-form": {"order": 0}, "auth-cookie": {"order": 0}}},
+				"client-authenticator": {"internal": true, "providers": {"client-secret": {"order": 0}, "client-jwt": {"order": 0}}}
+			},
+			"protocolMapperTypes": {
+				"openid-connect": [{"id": "oidc-audience-mapper", "name": "Audience"}, {"id": "oidc-usermodel-attribute-mapper", "name": "User Attribute"}],
+				"saml": [{"id": "saml-role-list-mapper", "name": "Role list"}]
+			}
+		}`))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	info, err := client.GetServerInfo(context.TODO())
+
+	// then
+	// features of older keycloaks are named like the options of the keycloak cli, only disabled ones are known
+	assert.NoError(t, err)
+	assert.Equal(t, "21.1.2", info.Version)
+	assert.Empty(t, info.Capabilities.EnabledFeatures)
+	assert.Equal(t, []string{"admin-fine-grained-authz", "token-exchange"}, info.Capabilities.DisabledFeatures)
+	assert.Equal(t, map[string][]string{
+		"openid-connect": {"oidc-audience-mapper", "oidc-usermodel-attribute-mapper"},
+		"saml":           {"saml-role-list-mapper"},
+	}, info.Capabilities.ProtocolMapperTypes)
+	assert.Equal(t, []string{"auth-cookie", "auth-username-password-form"}, info.Capabilities.Authenticators)
+	assert.Equal(t, []string{"client-jwt", "client-secret"}, info.Capabilities.ClientAuthenticators)
+}
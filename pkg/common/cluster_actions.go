@@ -3,13 +3,17 @@ package common
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -31,14 +35,83 @@ type ActionRunner interface {
 	CreateClientRole(keycloakClient *v1alpha1.KeycloakClient, role *v1alpha1.RoleRepresentation, realm string) error
 	UpdateClientRole(keycloakClient *v1alpha1.KeycloakClient, role, oldRole *v1alpha1.RoleRepresentation, realm string) error
 	DeleteClientRole(keycloakClient *v1alpha1.KeycloakClient, role, Realm string) error
+	CreateClientProtocolMapper(keycloakClient *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	UpdateClientProtocolMapper(keycloakClient *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	DeleteClientProtocolMapper(keycloakClient *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
 	CreateClientRealmScopeMappings(keycloakClient *v1alpha1.KeycloakClient, mappings *[]v1alpha1.RoleRepresentation, realm string) error
 	DeleteClientRealmScopeMappings(keycloakClient *v1alpha1.KeycloakClient, mappings *[]v1alpha1.RoleRepresentation, realm string) error
 	CreateClientClientScopeMappings(keycloakClient *v1alpha1.KeycloakClient, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error
 	DeleteClientClientScopeMappings(keycloakClient *v1alpha1.KeycloakClient, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error
-	UpdateClientDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakClientScope, realm string) error
-	DeleteClientDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakClientScope, realm string) error
-	UpdateClientOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakClientScope, realm string) error
-	DeleteClientOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakClientScope, realm string) error
+	UpdateClientDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error
+	DeleteClientDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error
+	UpdateClientOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error
+	DeleteClientOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error
+	UpdateResourceServer(keycloakClient *v1alpha1.KeycloakClient, resourceServer *v1alpha1.KeycloakResourceServer, realm string) error
+	CreateAuthorizationScope(keycloakClient *v1alpha1.KeycloakClient, scope *v1alpha1.KeycloakScope, realm string) error
+	UpdateAuthorizationScope(keycloakClient *v1alpha1.KeycloakClient, scope *v1alpha1.KeycloakScope, realm string) error
+	DeleteAuthorizationScope(keycloakClient *v1alpha1.KeycloakClient, scope *v1alpha1.KeycloakScope, realm string) error
+	CreateAuthorizationResource(keycloakClient *v1alpha1.KeycloakClient, resource *v1alpha1.KeycloakResource, realm string) error
+	UpdateAuthorizationResource(keycloakClient *v1alpha1.KeycloakClient, resource *v1alpha1.KeycloakResource, realm string) error
+	DeleteAuthorizationResource(keycloakClient *v1alpha1.KeycloakClient, resource *v1alpha1.KeycloakResource, realm string) error
+	CreateAuthorizationPolicy(keycloakClient *v1alpha1.KeycloakClient, policy *v1alpha1.KeycloakPolicy, realm string) error
+	UpdateAuthorizationPolicy(keycloakClient *v1alpha1.KeycloakClient, policy *v1alpha1.KeycloakPolicy, realm string) error
+	DeleteAuthorizationPolicy(keycloakClient *v1alpha1.KeycloakClient, policy *v1alpha1.KeycloakPolicy, realm string) error
+
+	CreateClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+	UpdateClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+	DeleteClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+	CreateClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	UpdateClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	DeleteClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	CreateClientScopeRealmScopeMappings(obj *v1alpha1.KeycloakClientScope, mappings *[]v1alpha1.RoleRepresentation, realm string) error
+	DeleteClientScopeRealmScopeMappings(obj *v1alpha1.KeycloakClientScope, mappings *[]v1alpha1.RoleRepresentation, realm string) error
+	CreateClientScopeClientScopeMappings(obj *v1alpha1.KeycloakClientScope, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error
+	DeleteClientScopeClientScopeMappings(obj *v1alpha1.KeycloakClientScope, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error
+	UpdateRealmDefaultClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+	DeleteRealmDefaultClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+	UpdateRealmOptionalClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+	DeleteRealmOptionalClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+
+	CreateRealmRole(obj *v1alpha1.KeycloakRealmRole, realm string) error
+	UpdateRealmRole(obj *v1alpha1.KeycloakRealmRole, realm string) error
+	DeleteRealmRole(obj *v1alpha1.KeycloakRealmRole, realm string) error
+	AddRealmRoleComposites(obj *v1alpha1.KeycloakRealmRole, roles *[]v1alpha1.RoleRepresentation, realm string) error
+	DeleteRealmRoleComposites(obj *v1alpha1.KeycloakRealmRole, roles *[]v1alpha1.RoleRepresentation, realm string) error
+
+	CreateGroup(obj *v1alpha1.KeycloakGroup, group, parentGroup *v1alpha1.KeycloakAPIGroup, realm string) error
+	UpdateGroup(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, realm string) error
+	DeleteGroup(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, realm string) error
+	CreateGroupRealmRoleMappings(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, mappings *[]v1alpha1.RoleRepresentation, realm string) error
+	DeleteGroupRealmRoleMappings(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, mappings *[]v1alpha1.RoleRepresentation, realm string) error
+	CreateGroupClientRoleMappings(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error
+	DeleteGroupClientRoleMappings(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error
+	UpdateRealmDefaultGroup(obj *v1alpha1.KeycloakGroup, realm string) error
+	DeleteRealmDefaultGroup(obj *v1alpha1.KeycloakGroup, realm string) error
+
+	CreateUser(obj *v1alpha1.KeycloakUser, password, realm string) error
+	UpdateUser(obj *v1alpha1.KeycloakUser, realm string) error
+	DeleteUser(obj *v1alpha1.KeycloakUser, realm string) error
+	AddUserToGroup(obj *v1alpha1.KeycloakUser, groupPath, realm string) error
+	RemoveUserFromGroup(obj *v1alpha1.KeycloakUser, group *v1alpha1.KeycloakAPIGroup, realm string) error
+	AddUserFederatedIdentity(obj *v1alpha1.KeycloakUser, fid v1alpha1.FederatedIdentity, realm string) error
+	RemoveUserFederatedIdentity(obj *v1alpha1.KeycloakUser, fid v1alpha1.FederatedIdentity, realm string) error
+
+	CreateIdentityProvider(obj *v1alpha1.KeycloakIdentityProvider, identityProvider *v1alpha1.KeycloakAPIIdentityProvider, realm string) error
+	UpdateIdentityProvider(obj *v1alpha1.KeycloakIdentityProvider, identityProvider *v1alpha1.KeycloakAPIIdentityProvider, realm string) error
+	DeleteIdentityProvider(obj *v1alpha1.KeycloakIdentityProvider, realm string) error
+	CreateIdentityProviderMapper(obj *v1alpha1.KeycloakIdentityProvider, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realm string) error
+	UpdateIdentityProviderMapper(obj *v1alpha1.KeycloakIdentityProvider, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realm string) error
+	DeleteIdentityProviderMapper(obj *v1alpha1.KeycloakIdentityProvider, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realm string) error
+
+	CreateAuthenticationFlow(obj *v1alpha1.KeycloakAuthenticationFlow, realm string) error
+	UpdateAuthenticationFlow(obj *v1alpha1.KeycloakAuthenticationFlow, flowID, realm string) error
+	DeleteAuthenticationFlow(obj *v1alpha1.KeycloakAuthenticationFlow, flowID, realm string) error
+	ReplaceAuthenticationExecutions(obj *v1alpha1.KeycloakAuthenticationFlow, existing []v1alpha1.KeycloakAPIAuthenticationExecutionInfo, realm string) error
+	UpdateAuthenticationExecution(obj *v1alpha1.KeycloakAuthenticationFlow, flowAlias string, execution *v1alpha1.KeycloakAPIAuthenticationExecutionInfo, realm string) error
+	CreateAuthenticatorConfig(obj *v1alpha1.KeycloakAuthenticationFlow, executionID string, config *v1alpha1.KeycloakAPIAuthenticatorConfig, realm string) error
+	UpdateAuthenticatorConfig(obj *v1alpha1.KeycloakAuthenticationFlow, config *v1alpha1.KeycloakAPIAuthenticatorConfig, realm string) error
+	DeleteAuthenticatorConfig(obj *v1alpha1.KeycloakAuthenticationFlow, configID, realm string) error
+	UpdateRealmFlowBindings(obj *v1alpha1.KeycloakAuthenticationFlow, bindings *v1alpha1.KeycloakAPIRealmFlowBindings, realm string) error
 
 	AssignRealmRole(obj *v1alpha1.KeycloakUserRole, userID, realm string) error
 	RemoveRealmRole(obj *v1alpha1.KeycloakUserRole, userID, realm string) error
@@ -83,8 +156,24 @@ func NewClusterAndKeycloakActionRunner(context context.Context, client client.Cl
 }
 
 func (i *ClusterActionRunner) RunAll(desiredState DesiredClusterState) error {
+	resource := resourceKind(i.cr)
+	recordDrift(resource, desiredState)
+
+	var attributes []attribute.KeyValue
+	if obj, ok := i.cr.(client.Object); ok {
+		attributes = ResourceAttributes(obj)
+	}
+
 	for index, action := range desiredState {
-		msg, err := action.Run(i)
+		// each action runs with its own span, the requests to keycloak become its children
+		name := actionName(action)
+		ctx, span := StartSpan(i.context, name, append(attributes, ActionAttribute.String(name))...)
+		runner := *i
+		runner.context = ctx
+
+		msg, err := action.Run(&runner)
+		EndSpan(span, err)
+		recordAction(resource, action, err)
 		if err != nil {
 			log.Info(fmt.Sprintf("(%5d) %10s %s : %s", index, "FAILED", msg, err))
 			return err
@@ -128,7 +217,7 @@ func (i *ClusterActionRunner) CreateRealm(obj *v1alpha1.KeycloakRealm) error {
 		return errors.Errorf("cannot perform realm create when client is nil")
 	}
 
-	_, err := i.keycloakClient.CreateRealm(obj)
+	_, err := i.keycloakClient.CreateRealm(i.context, obj)
 	return err
 }
 
@@ -137,53 +226,118 @@ func (i *ClusterActionRunner) CreateClient(obj *v1alpha1.KeycloakClient, realm s
 		return errors.Errorf("cannot perform client create when client is nil")
 	}
 
-	uid, err := i.keycloakClient.CreateClient(obj.Spec.Client, realm)
+	specClient, err := i.clientWithFlowBindingOverrideIDs(obj, realm)
+	if err != nil {
+		return err
+	}
+
+	uid, err := i.keycloakClient.CreateClient(i.context, specClient, realm)
 
 	if err == nil {
 		obj.Spec.Client.ID = uid
 
-		return i.client.Update(i.context, obj)
+		return i.patchClientID(obj)
 	}
 
 	log.Info(fmt.Sprintf("FAILED: create client failed for client %s with error %s", obj.Spec.Client.Name, err.Error()))
 
-	if err.Error() == "failed to create client: (409) 409 Conflict" {
+	var apiError *APIError
+	if errors.As(err, &apiError) && apiError.StatusCode == http.StatusConflict {
 		log.Info(" retry create client after 409 Conclict")
 
-		uid, err2 := i.keycloakClient.GetClientID(obj.Spec.Client.ClientID, realm)
+		uid, err2 := i.keycloakClient.GetClientID(i.context, obj.Spec.Client.ClientID, realm)
 
 		if err2 != nil {
 			return errors.Errorf(fmt.Sprintf("cannot perform client create because of %s followed by %s", err.Error(), err2.Error()))
 		}
-		err3 := i.keycloakClient.DeleteClient(uid, realm)
+		err3 := i.keycloakClient.DeleteClient(i.context, uid, realm)
 		if err3 != nil {
 			return errors.Errorf(fmt.Sprintf("cannot perform client create because of %s followed by %s", err.Error(), err3.Error()))
 		}
 		log.Info(fmt.Sprintf(" client %s deleted", obj.Spec.Client.Name))
 
-		uid, err := i.keycloakClient.CreateClient(obj.Spec.Client, realm)
+		uid, err := i.keycloakClient.CreateClient(i.context, specClient, realm)
 
 		if err == nil {
 			obj.Spec.Client.ID = uid
-			return i.client.Update(i.context, obj)
+			return i.patchClientID(obj)
 		}
 	}
 
 	return err
 }
 
+// patchClientID stores the ID of a new client in the resource. Only the ID is patched, the client of the
+// resource may contain the defaults of a client template that must not be stored.
+func (i *ClusterActionRunner) patchClientID(obj *v1alpha1.KeycloakClient) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"client":{"id":%q}}}`, obj.Spec.Client.ID))
+	patched := obj.DeepCopy()
+	err := i.client.Patch(i.context, patched, client.RawPatch(types.MergePatchType, patch))
+	if err != nil {
+		return err
+	}
+	obj.ResourceVersion = patched.ResourceVersion
+	return nil
+}
+
 func (i *ClusterActionRunner) UpdateClient(obj *v1alpha1.KeycloakClient, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client update when client is nil")
 	}
-	return i.keycloakClient.UpdateClient(obj.Spec.Client, realm)
+
+	specClient, err := i.clientWithFlowBindingOverrideIDs(obj, realm)
+	if err != nil {
+		return err
+	}
+
+	// protocol mappers and authorization settings are reconciled one by one after the client has been updated,
+	// Keycloak ignores them when the client is updated
+	if specClient.ProtocolMappers != nil || specClient.AuthorizationSettings != nil {
+		specClient = specClient.DeepCopy()
+		specClient.ProtocolMappers = nil
+		specClient.AuthorizationSettings = nil
+	}
+	return i.keycloakClient.UpdateClient(i.context, specClient, realm)
+}
+
+// clientWithFlowBindingOverrideIDs returns the client of the resource. Authentication flow binding overrides
+// may be given by the alias of the flow, they are replaced by the ID of the flow in a copy of the client. Overrides
+// with flows that don't exist are left out.
+func (i *ClusterActionRunner) clientWithFlowBindingOverrideIDs(obj *v1alpha1.KeycloakClient, realm string) (*v1alpha1.KeycloakAPIClient, error) {
+	if len(obj.Spec.Client.AuthenticationFlowBindingOverrides) == 0 {
+		return obj.Spec.Client, nil
+	}
+
+	flows, err := i.keycloakClient.ListAuthenticationFlows(i.context, realm)
+	if err != nil {
+		return nil, err
+	}
+
+	specClient := obj.Spec.Client.DeepCopy()
+	for binding, flow := range specClient.AuthenticationFlowBindingOverrides {
+		if flow == "" {
+			continue
+		}
+		found := false
+		for _, existing := range flows {
+			if existing.Alias == flow || existing.ID == flow {
+				specClient.AuthenticationFlowBindingOverrides[binding] = existing.ID
+				found = true
+				break
+			}
+		}
+		if !found {
+			delete(specClient.AuthenticationFlowBindingOverrides, binding)
+		}
+	}
+	return specClient, nil
 }
 
 func (i *ClusterActionRunner) CreateClientRole(obj *v1alpha1.KeycloakClient, role *v1alpha1.RoleRepresentation, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client role create when client is nil")
 	}
-	_, err := i.keycloakClient.CreateClientRole(obj.Spec.Client.ID, role, realm)
+	_, err := i.keycloakClient.CreateClientRole(i.context, obj.Spec.Client.ID, role, realm)
 	return err
 }
 
@@ -191,70 +345,200 @@ func (i *ClusterActionRunner) UpdateClientRole(obj *v1alpha1.KeycloakClient, rol
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client role update when client is nil")
 	}
-	return i.keycloakClient.UpdateClientRole(obj.Spec.Client.ID, role, oldRole, realm)
+	return i.keycloakClient.UpdateClientRole(i.context, obj.Spec.Client.ID, role, oldRole, realm)
 }
 
 func (i *ClusterActionRunner) DeleteClientRole(obj *v1alpha1.KeycloakClient, role, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client role delete when client is nil")
 	}
-	return i.keycloakClient.DeleteClientRole(obj.Spec.Client.ID, role, realm)
+	return i.keycloakClient.DeleteClientRole(i.context, obj.Spec.Client.ID, role, realm)
+}
+
+func (i *ClusterActionRunner) CreateClientProtocolMapper(obj *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client protocol mapper create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateClientProtocolMapper(i.context, obj.Spec.Client.ID, mapper, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateClientProtocolMapper(obj *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client protocol mapper update when client is nil")
+	}
+	return i.keycloakClient.UpdateClientProtocolMapper(i.context, obj.Spec.Client.ID, mapper, realm)
+}
+
+func (i *ClusterActionRunner) DeleteClientProtocolMapper(obj *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client protocol mapper delete when client is nil")
+	}
+	return i.keycloakClient.DeleteClientProtocolMapper(i.context, obj.Spec.Client.ID, mapper.ID, realm)
 }
 
 func (i *ClusterActionRunner) CreateClientRealmScopeMappings(keycloakClient *v1alpha1.KeycloakClient, mappings *[]v1alpha1.RoleRepresentation, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client realm scope create when client is nil")
 	}
-	return i.keycloakClient.CreateClientRealmScopeMappings(keycloakClient.Spec.Client, mappings, realm)
+	return i.keycloakClient.CreateClientRealmScopeMappings(i.context, keycloakClient.Spec.Client, mappings, realm)
 }
 
 func (i *ClusterActionRunner) DeleteClientRealmScopeMappings(keycloakClient *v1alpha1.KeycloakClient, mappings *[]v1alpha1.RoleRepresentation, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client realm scope delete when client is nil")
 	}
-	return i.keycloakClient.DeleteClientRealmScopeMappings(keycloakClient.Spec.Client, mappings, realm)
+	return i.keycloakClient.DeleteClientRealmScopeMappings(i.context, keycloakClient.Spec.Client, mappings, realm)
 }
 
 func (i *ClusterActionRunner) CreateClientClientScopeMappings(keycloakClient *v1alpha1.KeycloakClient, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client client scope create when client is nil")
 	}
-	return i.keycloakClient.CreateClientClientScopeMappings(keycloakClient.Spec.Client, mappings, realm)
+	return i.keycloakClient.CreateClientClientScopeMappings(i.context, keycloakClient.Spec.Client, mappings, realm)
 }
 
-func (i *ClusterActionRunner) DeleteClientDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakClientScope, realm string) error {
+func (i *ClusterActionRunner) DeleteClientDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client default client scope delete when client is nil")
 	}
-	return i.keycloakClient.DeleteClientDefaultClientScope(keycloakClient.Spec.Client, clientScope, realm)
+	return i.keycloakClient.DeleteClientDefaultClientScope(i.context, keycloakClient.Spec.Client, clientScope, realm)
 }
 
-func (i *ClusterActionRunner) UpdateClientDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakClientScope, realm string) error {
+func (i *ClusterActionRunner) UpdateClientDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client default client scope create when client is nil")
 	}
-	return i.keycloakClient.UpdateClientDefaultClientScope(keycloakClient.Spec.Client, clientScope, realm)
+	return i.keycloakClient.UpdateClientDefaultClientScope(i.context, keycloakClient.Spec.Client, clientScope, realm)
 }
 
-func (i *ClusterActionRunner) DeleteClientOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakClientScope, realm string) error {
+func (i *ClusterActionRunner) DeleteClientOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client optional client scope delete when client is nil")
 	}
-	return i.keycloakClient.DeleteClientOptionalClientScope(keycloakClient.Spec.Client, clientScope, realm)
+	return i.keycloakClient.DeleteClientOptionalClientScope(i.context, keycloakClient.Spec.Client, clientScope, realm)
 }
 
-func (i *ClusterActionRunner) UpdateClientOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakClientScope, realm string) error {
+func (i *ClusterActionRunner) UpdateClientOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client optional client scope create when client is nil")
 	}
-	return i.keycloakClient.UpdateClientOptionalClientScope(keycloakClient.Spec.Client, clientScope, realm)
+	return i.keycloakClient.UpdateClientOptionalClientScope(i.context, keycloakClient.Spec.Client, clientScope, realm)
 }
 
 func (i *ClusterActionRunner) DeleteClientClientScopeMappings(keycloakClient *v1alpha1.KeycloakClient, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client client scope delete when client is nil")
 	}
-	return i.keycloakClient.DeleteClientClientScopeMappings(keycloakClient.Spec.Client, mappings, realm)
+	return i.keycloakClient.DeleteClientClientScopeMappings(i.context, keycloakClient.Spec.Client, mappings, realm)
+}
+
+func (i *ClusterActionRunner) CreateClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope create when client is nil")
+	}
+	uid, err := i.keycloakClient.CreateClientScope(i.context, obj.Spec.ClientScope, realm)
+	if err != nil {
+		return err
+	}
+	// Client scopes are matched by name, the ID is only needed by the actions that follow
+	obj.Spec.ClientScope.ID = uid
+	return nil
+}
+
+func (i *ClusterActionRunner) UpdateClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope update when client is nil")
+	}
+	// protocol mappers are reconciled one by one, Keycloak ignores them when the client scope is updated
+	clientScope := obj.Spec.ClientScope.DeepCopy()
+	clientScope.ProtocolMappers = nil
+	return i.keycloakClient.UpdateClientScope(i.context, clientScope, realm)
+}
+
+func (i *ClusterActionRunner) DeleteClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope delete when client is nil")
+	}
+	return i.keycloakClient.DeleteClientScope(i.context, obj.Spec.ClientScope.ID, realm)
+}
+
+func (i *ClusterActionRunner) CreateClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope protocol mapper create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateClientScopeProtocolMapper(i.context, obj.Spec.ClientScope.ID, mapper, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope protocol mapper update when client is nil")
+	}
+	return i.keycloakClient.UpdateClientScopeProtocolMapper(i.context, obj.Spec.ClientScope.ID, mapper, realm)
+}
+
+func (i *ClusterActionRunner) DeleteClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope protocol mapper delete when client is nil")
+	}
+	return i.keycloakClient.DeleteClientScopeProtocolMapper(i.context, obj.Spec.ClientScope.ID, mapper.ID, realm)
+}
+
+func (i *ClusterActionRunner) CreateClientScopeRealmScopeMappings(obj *v1alpha1.KeycloakClientScope, mappings *[]v1alpha1.RoleRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope realm scope create when client is nil")
+	}
+	return i.keycloakClient.CreateClientScopeRealmScopeMappings(i.context, obj.Spec.ClientScope, mappings, realm)
+}
+
+func (i *ClusterActionRunner) DeleteClientScopeRealmScopeMappings(obj *v1alpha1.KeycloakClientScope, mappings *[]v1alpha1.RoleRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope realm scope delete when client is nil")
+	}
+	return i.keycloakClient.DeleteClientScopeRealmScopeMappings(i.context, obj.Spec.ClientScope, mappings, realm)
+}
+
+func (i *ClusterActionRunner) CreateClientScopeClientScopeMappings(obj *v1alpha1.KeycloakClientScope, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope client scope create when client is nil")
+	}
+	return i.keycloakClient.CreateClientScopeClientScopeMappings(i.context, obj.Spec.ClientScope, mappings, realm)
+}
+
+func (i *ClusterActionRunner) DeleteClientScopeClientScopeMappings(obj *v1alpha1.KeycloakClientScope, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope client scope delete when client is nil")
+	}
+	return i.keycloakClient.DeleteClientScopeClientScopeMappings(i.context, obj.Spec.ClientScope, mappings, realm)
+}
+
+func (i *ClusterActionRunner) UpdateRealmDefaultClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm default client scope create when client is nil")
+	}
+	return i.keycloakClient.UpdateRealmDefaultClientScope(i.context, obj.Spec.ClientScope, realm)
+}
+
+func (i *ClusterActionRunner) DeleteRealmDefaultClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm default client scope delete when client is nil")
+	}
+	return i.keycloakClient.DeleteRealmDefaultClientScope(i.context, obj.Spec.ClientScope, realm)
+}
+
+func (i *ClusterActionRunner) UpdateRealmOptionalClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm optional client scope create when client is nil")
+	}
+	return i.keycloakClient.UpdateRealmOptionalClientScope(i.context, obj.Spec.ClientScope, realm)
+}
+
+func (i *ClusterActionRunner) DeleteRealmOptionalClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm optional client scope delete when client is nil")
+	}
+	return i.keycloakClient.DeleteRealmOptionalClientScope(i.context, obj.Spec.ClientScope, realm)
 }
 
 // Delete a realm using the keycloak api
@@ -262,14 +546,14 @@ func (i *ClusterActionRunner) DeleteRealm(obj *v1alpha1.KeycloakRealm) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform realm delete when client is nil")
 	}
-	return i.keycloakClient.DeleteRealm(obj.Spec.Realm.Realm)
+	return i.keycloakClient.DeleteRealm(i.context, obj.Spec.Realm.Realm)
 }
 
 func (i *ClusterActionRunner) DeleteClient(obj *v1alpha1.KeycloakClient, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client delete when client is nil")
 	}
-	return i.keycloakClient.DeleteClient(obj.Spec.Client.ID, realm)
+	return i.keycloakClient.DeleteClient(i.context, obj.Spec.Client.ID, realm)
 }
 
 // Check if Keycloak is available
@@ -277,7 +561,7 @@ func (i *ClusterActionRunner) Ping() error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform keycloak ping when client is nil")
 	}
-	return i.keycloakClient.Ping()
+	return i.keycloakClient.Ping(i.context)
 }
 
 func (i *ClusterActionRunner) AssignRealmRole(obj *v1alpha1.KeycloakUserRole, userID, realm string) error {
@@ -285,7 +569,7 @@ func (i *ClusterActionRunner) AssignRealmRole(obj *v1alpha1.KeycloakUserRole, us
 		return errors.Errorf("cannot perform role assign when client is nil")
 	}
 
-	_, err := i.keycloakClient.CreateUserRealmRole(obj, realm, userID)
+	_, err := i.keycloakClient.CreateUserRealmRole(i.context, obj, realm, userID)
 	return err
 }
 
@@ -293,7 +577,7 @@ func (i *ClusterActionRunner) RemoveRealmRole(obj *v1alpha1.KeycloakUserRole, us
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform role remove when client is nil")
 	}
-	return i.keycloakClient.DeleteUserRealmRole(obj, realm, userID)
+	return i.keycloakClient.DeleteUserRealmRole(i.context, obj, realm, userID)
 }
 
 func (i *ClusterActionRunner) AssignClientRole(obj *v1alpha1.KeycloakUserRole, clientID, userID, realm string) error {
@@ -301,7 +585,7 @@ func (i *ClusterActionRunner) AssignClientRole(obj *v1alpha1.KeycloakUserRole, c
 		return errors.Errorf("cannot perform role assign when client is nil")
 	}
 
-	_, err := i.keycloakClient.CreateUserClientRole(obj, realm, clientID, userID)
+	_, err := i.keycloakClient.CreateUserClientRole(i.context, obj, realm, clientID, userID)
 	return err
 }
 
@@ -309,161 +593,1097 @@ func (i *ClusterActionRunner) RemoveClientRole(obj *v1alpha1.KeycloakUserRole, c
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform role remove when client is nil")
 	}
-	return i.keycloakClient.DeleteUserClientRole(obj, realm, clientID, userID)
+	return i.keycloakClient.DeleteUserClientRole(i.context, obj, realm, clientID, userID)
 }
 
 func (i *ClusterActionRunner) AddDefaultRoles(obj *[]v1alpha1.RoleRepresentation, defaultRealmRoleID, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform default role add when client is nil")
 	}
-	return i.keycloakClient.AddRealmRoleComposites(realm, defaultRealmRoleID, obj)
+	return i.keycloakClient.AddRealmRoleComposites(i.context, realm, defaultRealmRoleID, obj)
 }
 
 func (i *ClusterActionRunner) DeleteDefaultRoles(obj *[]v1alpha1.RoleRepresentation, defaultRealmRoleID, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform default role delete when client is nil")
 	}
-	return i.keycloakClient.DeleteRealmRoleComposites(realm, defaultRealmRoleID, obj)
+	return i.keycloakClient.DeleteRealmRoleComposites(i.context, realm, defaultRealmRoleID, obj)
 }
 
-// An action to create generic kubernetes resources
-// (resources that don't require special treatment)
-type GenericCreateAction struct {
-	Ref client.Object
-	Msg string
+// realmRoleWithoutComposites returns the role of the resource without its composites,
+// they are added and removed one by one after the role exists
+func realmRoleWithoutComposites(obj *v1alpha1.KeycloakRealmRole) *v1alpha1.RoleRepresentation {
+	role := obj.Spec.Role.DeepCopy()
+	role.Composite = nil
+	role.Composites = nil
+	return role
 }
 
-// An action to update generic kubernetes resources
-// (resources that don't require special treatment)
-type GenericUpdateAction struct {
-	Ref client.Object
-	Msg string
+func (i *ClusterActionRunner) CreateRealmRole(obj *v1alpha1.KeycloakRealmRole, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateRealmRole(i.context, realmRoleWithoutComposites(obj), realm)
+	if err != nil {
+		return err
+	}
+
+	// Keycloak only returns the name of the new role, the composites are added by ID
+	role, err := i.keycloakClient.GetRealmRole(i.context, obj.Spec.Role.Name, realm)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return errors.Errorf("realm role %s not found after creation", obj.Spec.Role.Name)
+	}
+	obj.Spec.Role.ID = role.ID
+	return nil
 }
 
-// An action to delete generic kubernetes resources
-// (resources that don't require special treatment)
-type GenericDeleteAction struct {
-	Ref client.Object
-	Msg string
+func (i *ClusterActionRunner) UpdateRealmRole(obj *v1alpha1.KeycloakRealmRole, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role update when client is nil")
+	}
+	return i.keycloakClient.UpdateRealmRole(i.context, realmRoleWithoutComposites(obj), realm)
 }
 
-type CreateClientAction struct {
-	Ref   *v1alpha1.KeycloakClient
-	Msg   string
-	Realm string
+func (i *ClusterActionRunner) DeleteRealmRole(obj *v1alpha1.KeycloakRealmRole, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role delete when client is nil")
+	}
+	return i.keycloakClient.DeleteRealmRole(i.context, obj.Spec.Role.ID, realm)
 }
 
-type UpdateClientAction struct {
-	Ref   *v1alpha1.KeycloakClient
-	Msg   string
-	Realm string
+func (i *ClusterActionRunner) AddRealmRoleComposites(obj *v1alpha1.KeycloakRealmRole, roles *[]v1alpha1.RoleRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role composites add when client is nil")
+	}
+	return i.keycloakClient.AddRealmRoleComposites(i.context, realm, obj.Spec.Role.ID, roles)
 }
 
-type DeleteClientAction struct {
-	Ref   *v1alpha1.KeycloakClient
-	Realm string
-	Msg   string
+func (i *ClusterActionRunner) DeleteRealmRoleComposites(obj *v1alpha1.KeycloakRealmRole, roles *[]v1alpha1.RoleRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role composites delete when client is nil")
+	}
+	return i.keycloakClient.DeleteRealmRoleComposites(i.context, realm, obj.Spec.Role.ID, roles)
 }
 
-type CreateClientRoleAction struct {
-	Role  *v1alpha1.RoleRepresentation
-	Ref   *v1alpha1.KeycloakClient
-	Msg   string
-	Realm string
+// groupWithoutChildren returns the group without its subgroups and role mappings,
+// they are reconciled one by one
+func groupWithoutChildren(group *v1alpha1.KeycloakAPIGroup) *v1alpha1.KeycloakAPIGroup {
+	return &v1alpha1.KeycloakAPIGroup{
+		ID:         group.ID,
+		Name:       group.Name,
+		Attributes: group.Attributes,
+	}
 }
 
-type UpdateClientRoleAction struct {
-	Role    *v1alpha1.RoleRepresentation
-	OldRole *v1alpha1.RoleRepresentation
-	Ref     *v1alpha1.KeycloakClient
-	Msg     string
-	Realm   string
+func (i *ClusterActionRunner) CreateGroup(obj *v1alpha1.KeycloakGroup, group, parentGroup *v1alpha1.KeycloakAPIGroup, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group create when client is nil")
+	}
+
+	var uid string
+	var err error
+	if parentGroup == nil {
+		uid, err = i.keycloakClient.CreateGroup(i.context, groupWithoutChildren(group), realm)
+	} else {
+		uid, err = i.keycloakClient.CreateChildGroup(i.context, parentGroup.ID, groupWithoutChildren(group), realm)
+	}
+	if err != nil {
+		return err
+	}
+	// The ID is needed by the subgroups and role mappings that follow
+	group.ID = uid
+	return nil
 }
 
-type DeleteClientRoleAction struct {
-	Role  *v1alpha1.RoleRepresentation
-	Ref   *v1alpha1.KeycloakClient
-	Msg   string
-	Realm string
+func (i *ClusterActionRunner) UpdateGroup(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group update when client is nil")
+	}
+	return i.keycloakClient.UpdateGroup(i.context, groupWithoutChildren(group), realm)
 }
 
-type AddDefaultRolesAction struct {
-	Roles              *[]v1alpha1.RoleRepresentation
-	DefaultRealmRoleID string
-	Ref                *v1alpha1.KeycloakClient
-	Msg                string
-	Realm              string
+func (i *ClusterActionRunner) DeleteGroup(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group delete when client is nil")
+	}
+	return i.keycloakClient.DeleteGroup(i.context, group.ID, realm)
 }
 
-type DeleteDefaultRolesAction struct {
-	Roles              *[]v1alpha1.RoleRepresentation
-	DefaultRealmRoleID string
-	Ref                *v1alpha1.KeycloakClient
-	Msg                string
-	Realm              string
+func (i *ClusterActionRunner) CreateGroupRealmRoleMappings(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, mappings *[]v1alpha1.RoleRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group realm role mappings create when client is nil")
+	}
+	return i.keycloakClient.CreateGroupRealmRoleMappings(i.context, group, mappings, realm)
 }
 
-type CreateClientRealmScopeMappingsAction struct {
-	Mappings *[]v1alpha1.RoleRepresentation
-	Ref      *v1alpha1.KeycloakClient
-	Msg      string
-	Realm    string
+func (i *ClusterActionRunner) DeleteGroupRealmRoleMappings(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, mappings *[]v1alpha1.RoleRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group realm role mappings delete when client is nil")
+	}
+	return i.keycloakClient.DeleteGroupRealmRoleMappings(i.context, group, mappings, realm)
 }
 
-type DeleteClientRealmScopeMappingsAction struct {
-	Mappings *[]v1alpha1.RoleRepresentation
-	Ref      *v1alpha1.KeycloakClient
-	Msg      string
-	Realm    string
+func (i *ClusterActionRunner) CreateGroupClientRoleMappings(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group client role mappings create when client is nil")
+	}
+	return i.keycloakClient.CreateGroupClientRoleMappings(i.context, group, mappings, realm)
 }
 
-type CreateClientClientScopeMappingsAction struct {
-	Mappings *v1alpha1.ClientMappingsRepresentation
-	Ref      *v1alpha1.KeycloakClient
-	Msg      string
-	Realm    string
+func (i *ClusterActionRunner) DeleteGroupClientRoleMappings(obj *v1alpha1.KeycloakGroup, group *v1alpha1.KeycloakAPIGroup, mappings *v1alpha1.ClientMappingsRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group client role mappings delete when client is nil")
+	}
+	return i.keycloakClient.DeleteGroupClientRoleMappings(i.context, group, mappings, realm)
 }
 
-type DeleteClientClientScopeMappingsAction struct {
-	Mappings *v1alpha1.ClientMappingsRepresentation
-	Ref      *v1alpha1.KeycloakClient
-	Msg      string
-	Realm    string
+func (i *ClusterActionRunner) UpdateRealmDefaultGroup(obj *v1alpha1.KeycloakGroup, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm default group update when client is nil")
+	}
+	return i.keycloakClient.UpdateRealmDefaultGroup(i.context, obj.Spec.Group, realm)
 }
 
-type UpdateClientDefaultClientScopeAction struct {
-	ClientScope *v1alpha1.KeycloakClientScope
-	Ref         *v1alpha1.KeycloakClient
-	Msg         string
-	Realm       string
+func (i *ClusterActionRunner) DeleteRealmDefaultGroup(obj *v1alpha1.KeycloakGroup, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm default group delete when client is nil")
+	}
+	return i.keycloakClient.DeleteRealmDefaultGroup(i.context, obj.Spec.Group, realm)
 }
 
-type DeleteClientDefaultClientScopeAction struct {
-	ClientScope *v1alpha1.KeycloakClientScope
-	Ref         *v1alpha1.KeycloakClient
-	Msg         string
-	Realm       string
+// userWithoutMappings returns the user of the resource without roles, groups, federated identities and
+// credentials, they are reconciled one by one
+func userWithoutMappings(obj *v1alpha1.KeycloakUser) *v1alpha1.KeycloakAPIUser {
+	user := obj.Spec.User.DeepCopy()
+	user.RealmRoles = nil
+	user.ClientRoles = nil
+	user.Groups = nil
+	user.FederatedIdentities = nil
+	user.Credentials = nil
+	return user
 }
 
-type UpdateClientOptionalClientScopeAction struct {
-	ClientScope *v1alpha1.KeycloakClientScope
-	Ref         *v1alpha1.KeycloakClient
-	Msg         string
-	Realm       string
+func (i *ClusterActionRunner) CreateUser(obj *v1alpha1.KeycloakUser, password, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user create when client is nil")
+	}
+
+	user := userWithoutMappings(obj)
+	user.ID = ""
+	if password != "" {
+		user.Credentials = []v1alpha1.KeycloakCredential{
+			{
+				Type:  "password",
+				Value: password,
+			},
+		}
+	}
+
+	uid, err := i.keycloakClient.CreateUser(i.context, user, realm)
+	if err != nil {
+		return err
+	}
+	obj.Spec.User.ID = uid
+	return nil
 }
 
-type DeleteClientOptionalClientScopeAction struct {
-	ClientScope *v1alpha1.KeycloakClientScope
-	Ref         *v1alpha1.KeycloakClient
-	Msg         string
-	Realm       string
+func (i *ClusterActionRunner) UpdateUser(obj *v1alpha1.KeycloakUser, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user update when client is nil")
+	}
+	return i.keycloakClient.UpdateUser(i.context, userWithoutMappings(obj), realm)
 }
 
-type PingAction struct {
-	Msg string
+func (i *ClusterActionRunner) DeleteUser(obj *v1alpha1.KeycloakUser, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user delete when client is nil")
+	}
+	return i.keycloakClient.DeleteUser(i.context, obj.Spec.User.ID, realm)
 }
 
-type AssignRealmRoleAction struct {
+func (i *ClusterActionRunner) AddUserToGroup(obj *v1alpha1.KeycloakUser, groupPath, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user group add when client is nil")
+	}
+
+	group, err := i.keycloakClient.GetGroupByPath(i.context, groupPath, realm)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		return errors.Errorf("group %s not found", groupPath)
+	}
+	return i.keycloakClient.AddUserToGroup(i.context, obj.Spec.User.ID, group, realm)
+}
+
+func (i *ClusterActionRunner) RemoveUserFromGroup(obj *v1alpha1.KeycloakUser, group *v1alpha1.KeycloakAPIGroup, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user group remove when client is nil")
+	}
+	return i.keycloakClient.RemoveUserFromGroup(i.context, obj.Spec.User.ID, group, realm)
+}
+
+func (i *ClusterActionRunner) AddUserFederatedIdentity(obj *v1alpha1.KeycloakUser, fid v1alpha1.FederatedIdentity, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform federated identity add when client is nil")
+	}
+	_, err := i.keycloakClient.CreateFederatedIdentity(i.context, fid, obj.Spec.User.ID, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) RemoveUserFederatedIdentity(obj *v1alpha1.KeycloakUser, fid v1alpha1.FederatedIdentity, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform federated identity remove when client is nil")
+	}
+	return i.keycloakClient.RemoveFederatedIdentity(i.context, fid, obj.Spec.User.ID, realm)
+}
+
+func (i *ClusterActionRunner) CreateIdentityProvider(obj *v1alpha1.KeycloakIdentityProvider, identityProvider *v1alpha1.KeycloakAPIIdentityProvider, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform identity provider create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateIdentityProvider(i.context, identityProvider, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateIdentityProvider(obj *v1alpha1.KeycloakIdentityProvider, identityProvider *v1alpha1.KeycloakAPIIdentityProvider, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform identity provider update when client is nil")
+	}
+	return i.keycloakClient.UpdateIdentityProvider(i.context, identityProvider, realm)
+}
+
+func (i *ClusterActionRunner) DeleteIdentityProvider(obj *v1alpha1.KeycloakIdentityProvider, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform identity provider delete when client is nil")
+	}
+	return i.keycloakClient.DeleteIdentityProvider(i.context, obj.Spec.IdentityProvider.Alias, realm)
+}
+
+func (i *ClusterActionRunner) CreateIdentityProviderMapper(obj *v1alpha1.KeycloakIdentityProvider, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform identity provider mapper create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateIdentityProviderMapper(i.context, mapper, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateIdentityProviderMapper(obj *v1alpha1.KeycloakIdentityProvider, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform identity provider mapper update when client is nil")
+	}
+	return i.keycloakClient.UpdateIdentityProviderMapper(i.context, mapper, realm)
+}
+
+func (i *ClusterActionRunner) DeleteIdentityProviderMapper(obj *v1alpha1.KeycloakIdentityProvider, mapper *v1alpha1.KeycloakAPIIdentityProviderMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform identity provider mapper delete when client is nil")
+	}
+	return i.keycloakClient.DeleteIdentityProviderMapper(i.context, mapper, realm)
+}
+
+func (i *ClusterActionRunner) CreateAuthenticationFlow(obj *v1alpha1.KeycloakAuthenticationFlow, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authentication flow create when client is nil")
+	}
+
+	providerID := obj.Spec.Flow.ProviderID
+	if providerID == "" {
+		providerID = "basic-flow"
+	}
+	_, err := i.keycloakClient.CreateAuthenticationFlow(i.context, &v1alpha1.KeycloakAPIAuthenticationFlow{
+		Alias:       obj.Spec.Flow.Alias,
+		Description: obj.Spec.Flow.Description,
+		ProviderID:  providerID,
+		TopLevel:    true,
+	}, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateAuthenticationFlow(obj *v1alpha1.KeycloakAuthenticationFlow, flowID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authentication flow update when client is nil")
+	}
+	return i.keycloakClient.UpdateAuthenticationFlow(i.context, &v1alpha1.KeycloakAPIAuthenticationFlow{
+		ID:          flowID,
+		Alias:       obj.Spec.Flow.Alias,
+		Description: obj.Spec.Flow.Description,
+		ProviderID:  obj.Spec.Flow.ProviderID,
+		TopLevel:    true,
+	}, realm)
+}
+
+func (i *ClusterActionRunner) DeleteAuthenticationFlow(obj *v1alpha1.KeycloakAuthenticationFlow, flowID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authentication flow delete when client is nil")
+	}
+	return i.keycloakClient.DeleteAuthenticationFlow(i.context, flowID, realm)
+}
+
+// ReplaceAuthenticationExecutions deletes the existing executions of the flow and creates the executions of the resource
+func (i *ClusterActionRunner) ReplaceAuthenticationExecutions(obj *v1alpha1.KeycloakAuthenticationFlow, existing []v1alpha1.KeycloakAPIAuthenticationExecutionInfo, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authentication executions replace when client is nil")
+	}
+
+	// sub-flows are deleted together with their executions
+	for _, execution := range existing {
+		if execution.Level != 0 {
+			continue
+		}
+		err := i.keycloakClient.DeleteAuthenticationExecution(i.context, execution.ID, realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	return i.createAuthenticationExecutions(obj.Spec.Flow.Alias, obj.Spec.Flow.Executions, realm)
+}
+
+// createAuthenticationExecutions adds the executions to the end of the given flow, new executions are disabled
+// so the requirement is set afterwards
+func (i *ClusterActionRunner) createAuthenticationExecutions(flowAlias string, executions []v1alpha1.KeycloakAuthenticationExecution, realm string) error {
+	executions = model.SortAuthenticationExecutions(executions)
+	for index := range executions {
+		var err error
+		if executions[index].SubFlow != nil {
+			_, err = i.keycloakClient.CreateAuthenticationSubFlow(i.context, flowAlias, executions[index].SubFlow, realm)
+		} else {
+			_, err = i.keycloakClient.CreateAuthenticationExecution(i.context, flowAlias, executions[index].Authenticator, realm)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	created, err := i.keycloakClient.ListAuthenticationExecutions(i.context, flowAlias, realm)
+	if err != nil {
+		return err
+	}
+
+	index := 0
+	for _, info := range created {
+		if info.Level != 0 {
+			continue
+		}
+		if index >= len(executions) {
+			return errors.Errorf("unexpected execution %s in authentication flow %s", info.DisplayName, flowAlias)
+		}
+
+		execution := executions[index]
+		index++
+
+		info.Requirement = execution.Requirement
+		err = i.keycloakClient.UpdateAuthenticationExecution(i.context, flowAlias, info.DeepCopy(), realm)
+		if err != nil {
+			return err
+		}
+
+		if execution.AuthenticatorConfig != nil {
+			config := execution.AuthenticatorConfig.DeepCopy()
+			config.ID = ""
+			_, err = i.keycloakClient.CreateAuthenticatorConfig(i.context, info.ID, config, realm)
+			if err != nil {
+				return err
+			}
+		}
+
+		if execution.SubFlow != nil {
+			err = i.createAuthenticationExecutions(execution.SubFlow.Alias, execution.SubFlow.Executions, realm)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if index != len(executions) {
+		return errors.Errorf("missing executions in authentication flow %s", flowAlias)
+	}
+	return nil
+}
+
+func (i *ClusterActionRunner) UpdateAuthenticationExecution(obj *v1alpha1.KeycloakAuthenticationFlow, flowAlias string, execution *v1alpha1.KeycloakAPIAuthenticationExecutionInfo, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authentication execution update when client is nil")
+	}
+	return i.keycloakClient.UpdateAuthenticationExecution(i.context, flowAlias, execution, realm)
+}
+
+func (i *ClusterActionRunner) CreateAuthenticatorConfig(obj *v1alpha1.KeycloakAuthenticationFlow, executionID string, config *v1alpha1.KeycloakAPIAuthenticatorConfig, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authenticator config create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateAuthenticatorConfig(i.context, executionID, config, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateAuthenticatorConfig(obj *v1alpha1.KeycloakAuthenticationFlow, config *v1alpha1.KeycloakAPIAuthenticatorConfig, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authenticator config update when client is nil")
+	}
+	return i.keycloakClient.UpdateAuthenticatorConfig(i.context, config, realm)
+}
+
+func (i *ClusterActionRunner) DeleteAuthenticatorConfig(obj *v1alpha1.KeycloakAuthenticationFlow, configID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authenticator config delete when client is nil")
+	}
+	return i.keycloakClient.DeleteAuthenticatorConfig(i.context, configID, realm)
+}
+
+func (i *ClusterActionRunner) UpdateRealmFlowBindings(obj *v1alpha1.KeycloakAuthenticationFlow, bindings *v1alpha1.KeycloakAPIRealmFlowBindings, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm flow bindings update when client is nil")
+	}
+	return i.keycloakClient.UpdateRealmFlowBindings(i.context, bindings, realm)
+}
+
+func (i *ClusterActionRunner) UpdateResourceServer(obj *v1alpha1.KeycloakClient, resourceServer *v1alpha1.KeycloakResourceServer, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform resource server update when client is nil")
+	}
+	return i.keycloakClient.UpdateResourceServer(i.context, obj.Spec.Client.ID, resourceServer, realm)
+}
+
+func (i *ClusterActionRunner) CreateAuthorizationScope(obj *v1alpha1.KeycloakClient, scope *v1alpha1.KeycloakScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authorization scope create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateAuthorizationScope(i.context, obj.Spec.Client.ID, scope, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateAuthorizationScope(obj *v1alpha1.KeycloakClient, scope *v1alpha1.KeycloakScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authorization scope update when client is nil")
+	}
+	return i.keycloakClient.UpdateAuthorizationScope(i.context, obj.Spec.Client.ID, scope, realm)
+}
+
+func (i *ClusterActionRunner) DeleteAuthorizationScope(obj *v1alpha1.KeycloakClient, scope *v1alpha1.KeycloakScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authorization scope delete when client is nil")
+	}
+	return i.keycloakClient.DeleteAuthorizationScope(i.context, obj.Spec.Client.ID, scope.ID, realm)
+}
+
+func (i *ClusterActionRunner) CreateAuthorizationResource(obj *v1alpha1.KeycloakClient, resource *v1alpha1.KeycloakResource, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authorization resource create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateAuthorizationResource(i.context, obj.Spec.Client.ID, resource, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateAuthorizationResource(obj *v1alpha1.KeycloakClient, resource *v1alpha1.KeycloakResource, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authorization resource update when client is nil")
+	}
+	return i.keycloakClient.UpdateAuthorizationResource(i.context, obj.Spec.Client.ID, resource, realm)
+}
+
+func (i *ClusterActionRunner) DeleteAuthorizationResource(obj *v1alpha1.KeycloakClient, resource *v1alpha1.KeycloakResource, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authorization resource delete when client is nil")
+	}
+	return i.keycloakClient.DeleteAuthorizationResource(i.context, obj.Spec.Client.ID, resource.ID, realm)
+}
+
+func (i *ClusterActionRunner) CreateAuthorizationPolicy(obj *v1alpha1.KeycloakClient, policy *v1alpha1.KeycloakPolicy, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authorization policy create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateAuthorizationPolicy(i.context, obj.Spec.Client.ID, policy, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateAuthorizationPolicy(obj *v1alpha1.KeycloakClient, policy *v1alpha1.KeycloakPolicy, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authorization policy update when client is nil")
+	}
+	return i.keycloakClient.UpdateAuthorizationPolicy(i.context, obj.Spec.Client.ID, policy, realm)
+}
+
+func (i *ClusterActionRunner) DeleteAuthorizationPolicy(obj *v1alpha1.KeycloakClient, policy *v1alpha1.KeycloakPolicy, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authorization policy delete when client is nil")
+	}
+	return i.keycloakClient.DeleteAuthorizationPolicy(i.context, obj.Spec.Client.ID, policy.ID, realm)
+}
+
+// An action to create generic kubernetes resources
+// (resources that don't require special treatment)
+type GenericCreateAction struct {
+	Ref client.Object
+	Msg string
+}
+
+// An action to update generic kubernetes resources
+// (resources that don't require special treatment)
+type GenericUpdateAction struct {
+	Ref client.Object
+	Msg string
+}
+
+// An action to delete generic kubernetes resources
+// (resources that don't require special treatment)
+type GenericDeleteAction struct {
+	Ref client.Object
+	Msg string
+}
+
+type CreateClientAction struct {
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type UpdateClientAction struct {
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type DeleteClientAction struct {
+	Ref   *v1alpha1.KeycloakClient
+	Realm string
+	Msg   string
+}
+
+type CreateClientRoleAction struct {
+	Role  *v1alpha1.RoleRepresentation
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type UpdateClientRoleAction struct {
+	Role    *v1alpha1.RoleRepresentation
+	OldRole *v1alpha1.RoleRepresentation
+	Ref     *v1alpha1.KeycloakClient
+	Msg     string
+	Realm   string
+}
+
+type DeleteClientRoleAction struct {
+	Role  *v1alpha1.RoleRepresentation
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type CreateClientProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClient
+	Msg    string
+	Realm  string
+}
+
+type UpdateClientProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClient
+	Msg    string
+	Realm  string
+}
+
+type DeleteClientProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClient
+	Msg    string
+	Realm  string
+}
+
+type AddDefaultRolesAction struct {
+	Roles              *[]v1alpha1.RoleRepresentation
+	DefaultRealmRoleID string
+	Ref                *v1alpha1.KeycloakClient
+	Msg                string
+	Realm              string
+}
+
+type DeleteDefaultRolesAction struct {
+	Roles              *[]v1alpha1.RoleRepresentation
+	DefaultRealmRoleID string
+	Ref                *v1alpha1.KeycloakClient
+	Msg                string
+	Realm              string
+}
+
+type CreateClientRealmScopeMappingsAction struct {
+	Mappings *[]v1alpha1.RoleRepresentation
+	Ref      *v1alpha1.KeycloakClient
+	Msg      string
+	Realm    string
+}
+
+type DeleteClientRealmScopeMappingsAction struct {
+	Mappings *[]v1alpha1.RoleRepresentation
+	Ref      *v1alpha1.KeycloakClient
+	Msg      string
+	Realm    string
+}
+
+type CreateClientClientScopeMappingsAction struct {
+	Mappings *v1alpha1.ClientMappingsRepresentation
+	Ref      *v1alpha1.KeycloakClient
+	Msg      string
+	Realm    string
+}
+
+type DeleteClientClientScopeMappingsAction struct {
+	Mappings *v1alpha1.ClientMappingsRepresentation
+	Ref      *v1alpha1.KeycloakClient
+	Msg      string
+	Realm    string
+}
+
+type UpdateClientDefaultClientScopeAction struct {
+	ClientScope *v1alpha1.KeycloakAPIClientScope
+	Ref         *v1alpha1.KeycloakClient
+	Msg         string
+	Realm       string
+}
+
+type DeleteClientDefaultClientScopeAction struct {
+	ClientScope *v1alpha1.KeycloakAPIClientScope
+	Ref         *v1alpha1.KeycloakClient
+	Msg         string
+	Realm       string
+}
+
+type UpdateClientOptionalClientScopeAction struct {
+	ClientScope *v1alpha1.KeycloakAPIClientScope
+	Ref         *v1alpha1.KeycloakClient
+	Msg         string
+	Realm       string
+}
+
+type DeleteClientOptionalClientScopeAction struct {
+	ClientScope *v1alpha1.KeycloakAPIClientScope
+	Ref         *v1alpha1.KeycloakClient
+	Msg         string
+	Realm       string
+}
+
+type CreateRealmRoleAction struct {
+	Ref   *v1alpha1.KeycloakRealmRole
+	Msg   string
+	Realm string
+}
+
+type UpdateRealmRoleAction struct {
+	Ref   *v1alpha1.KeycloakRealmRole
+	Msg   string
+	Realm string
+}
+
+type DeleteRealmRoleAction struct {
+	Ref   *v1alpha1.KeycloakRealmRole
+	Msg   string
+	Realm string
+}
+
+type AddRealmRoleCompositesAction struct {
+	Roles *[]v1alpha1.RoleRepresentation
+	Ref   *v1alpha1.KeycloakRealmRole
+	Msg   string
+	Realm string
+}
+
+type DeleteRealmRoleCompositesAction struct {
+	Roles *[]v1alpha1.RoleRepresentation
+	Ref   *v1alpha1.KeycloakRealmRole
+	Msg   string
+	Realm string
+}
+
+type CreateGroupAction struct {
+	Group       *v1alpha1.KeycloakAPIGroup
+	ParentGroup *v1alpha1.KeycloakAPIGroup
+	Ref         *v1alpha1.KeycloakGroup
+	Msg         string
+	Realm       string
+}
+
+type UpdateGroupAction struct {
+	Group *v1alpha1.KeycloakAPIGroup
+	Ref   *v1alpha1.KeycloakGroup
+	Msg   string
+	Realm string
+}
+
+type DeleteGroupAction struct {
+	Group *v1alpha1.KeycloakAPIGroup
+	Ref   *v1alpha1.KeycloakGroup
+	Msg   string
+	Realm string
+}
+
+type CreateGroupRealmRoleMappingsAction struct {
+	Group    *v1alpha1.KeycloakAPIGroup
+	Mappings *[]v1alpha1.RoleRepresentation
+	Ref      *v1alpha1.KeycloakGroup
+	Msg      string
+	Realm    string
+}
+
+type DeleteGroupRealmRoleMappingsAction struct {
+	Group    *v1alpha1.KeycloakAPIGroup
+	Mappings *[]v1alpha1.RoleRepresentation
+	Ref      *v1alpha1.KeycloakGroup
+	Msg      string
+	Realm    string
+}
+
+type CreateGroupClientRoleMappingsAction struct {
+	Group    *v1alpha1.KeycloakAPIGroup
+	Mappings *v1alpha1.ClientMappingsRepresentation
+	Ref      *v1alpha1.KeycloakGroup
+	Msg      string
+	Realm    string
+}
+
+type DeleteGroupClientRoleMappingsAction struct {
+	Group    *v1alpha1.KeycloakAPIGroup
+	Mappings *v1alpha1.ClientMappingsRepresentation
+	Ref      *v1alpha1.KeycloakGroup
+	Msg      string
+	Realm    string
+}
+
+type UpdateRealmDefaultGroupAction struct {
+	Ref   *v1alpha1.KeycloakGroup
+	Msg   string
+	Realm string
+}
+
+type DeleteRealmDefaultGroupAction struct {
+	Ref   *v1alpha1.KeycloakGroup
+	Msg   string
+	Realm string
+}
+
+type CreateUserAction struct {
+	Password string
+	Ref      *v1alpha1.KeycloakUser
+	Msg      string
+	Realm    string
+}
+
+type UpdateUserAction struct {
+	Ref   *v1alpha1.KeycloakUser
+	Msg   string
+	Realm string
+}
+
+type DeleteUserAction struct {
+	Ref   *v1alpha1.KeycloakUser
+	Msg   string
+	Realm string
+}
+
+type AddUserToGroupAction struct {
+	GroupPath string
+	Ref       *v1alpha1.KeycloakUser
+	Msg       string
+	Realm     string
+}
+
+type RemoveUserFromGroupAction struct {
+	Group *v1alpha1.KeycloakAPIGroup
+	Ref   *v1alpha1.KeycloakUser
+	Msg   string
+	Realm string
+}
+
+type AddUserFederatedIdentityAction struct {
+	FederatedIdentity v1alpha1.FederatedIdentity
+	Ref               *v1alpha1.KeycloakUser
+	Msg               string
+	Realm             string
+}
+
+type RemoveUserFederatedIdentityAction struct {
+	FederatedIdentity v1alpha1.FederatedIdentity
+	Ref               *v1alpha1.KeycloakUser
+	Msg               string
+	Realm             string
+}
+
+type CreateIdentityProviderAction struct {
+	IdentityProvider *v1alpha1.KeycloakAPIIdentityProvider
+	Ref              *v1alpha1.KeycloakIdentityProvider
+	Msg              string
+	Realm            string
+}
+
+type UpdateIdentityProviderAction struct {
+	IdentityProvider *v1alpha1.KeycloakAPIIdentityProvider
+	Ref              *v1alpha1.KeycloakIdentityProvider
+	Msg              string
+	Realm            string
+}
+
+type DeleteIdentityProviderAction struct {
+	Ref   *v1alpha1.KeycloakIdentityProvider
+	Msg   string
+	Realm string
+}
+
+type CreateIdentityProviderMapperAction struct {
+	Mapper *v1alpha1.KeycloakAPIIdentityProviderMapper
+	Ref    *v1alpha1.KeycloakIdentityProvider
+	Msg    string
+	Realm  string
+}
+
+type UpdateIdentityProviderMapperAction struct {
+	Mapper *v1alpha1.KeycloakAPIIdentityProviderMapper
+	Ref    *v1alpha1.KeycloakIdentityProvider
+	Msg    string
+	Realm  string
+}
+
+type DeleteIdentityProviderMapperAction struct {
+	Mapper *v1alpha1.KeycloakAPIIdentityProviderMapper
+	Ref    *v1alpha1.KeycloakIdentityProvider
+	Msg    string
+	Realm  string
+}
+
+type CreateAuthenticationFlowAction struct {
+	Ref   *v1alpha1.KeycloakAuthenticationFlow
+	Msg   string
+	Realm string
+}
+
+type UpdateAuthenticationFlowAction struct {
+	FlowID string
+	Ref    *v1alpha1.KeycloakAuthenticationFlow
+	Msg    string
+	Realm  string
+}
+
+type DeleteAuthenticationFlowAction struct {
+	FlowID string
+	Ref    *v1alpha1.KeycloakAuthenticationFlow
+	Msg    string
+	Realm  string
+}
+
+type ReplaceAuthenticationExecutionsAction struct {
+	Existing []v1alpha1.KeycloakAPIAuthenticationExecutionInfo
+	Ref      *v1alpha1.KeycloakAuthenticationFlow
+	Msg      string
+	Realm    string
+}
+
+type UpdateAuthenticationExecutionAction struct {
+	FlowAlias string
+	Execution *v1alpha1.KeycloakAPIAuthenticationExecutionInfo
+	Ref       *v1alpha1.KeycloakAuthenticationFlow
+	Msg       string
+	Realm     string
+}
+
+type CreateAuthenticatorConfigAction struct {
+	ExecutionID string
+	Config      *v1alpha1.KeycloakAPIAuthenticatorConfig
+	Ref         *v1alpha1.KeycloakAuthenticationFlow
+	Msg         string
+	Realm       string
+}
+
+type UpdateAuthenticatorConfigAction struct {
+	Config *v1alpha1.KeycloakAPIAuthenticatorConfig
+	Ref    *v1alpha1.KeycloakAuthenticationFlow
+	Msg    string
+	Realm  string
+}
+
+type DeleteAuthenticatorConfigAction struct {
+	ConfigID string
+	Ref      *v1alpha1.KeycloakAuthenticationFlow
+	Msg      string
+	Realm    string
+}
+
+type UpdateRealmFlowBindingsAction struct {
+	Bindings *v1alpha1.KeycloakAPIRealmFlowBindings
+	Ref      *v1alpha1.KeycloakAuthenticationFlow
+	Msg      string
+	Realm    string
+}
+
+type UpdateResourceServerAction struct {
+	ResourceServer *v1alpha1.KeycloakResourceServer
+	Ref            *v1alpha1.KeycloakClient
+	Msg            string
+	Realm          string
+}
+
+type CreateAuthorizationScopeAction struct {
+	Scope *v1alpha1.KeycloakScope
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type UpdateAuthorizationScopeAction struct {
+	Scope *v1alpha1.KeycloakScope
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type DeleteAuthorizationScopeAction struct {
+	Scope *v1alpha1.KeycloakScope
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type CreateAuthorizationResourceAction struct {
+	Resource *v1alpha1.KeycloakResource
+	Ref      *v1alpha1.KeycloakClient
+	Msg      string
+	Realm    string
+}
+
+type UpdateAuthorizationResourceAction struct {
+	Resource *v1alpha1.KeycloakResource
+	Ref      *v1alpha1.KeycloakClient
+	Msg      string
+	Realm    string
+}
+
+type DeleteAuthorizationResourceAction struct {
+	Resource *v1alpha1.KeycloakResource
+	Ref      *v1alpha1.KeycloakClient
+	Msg      string
+	Realm    string
+}
+
+type CreateAuthorizationPolicyAction struct {
+	Policy *v1alpha1.KeycloakPolicy
+	Ref    *v1alpha1.KeycloakClient
+	Msg    string
+	Realm  string
+}
+
+type UpdateAuthorizationPolicyAction struct {
+	Policy *v1alpha1.KeycloakPolicy
+	Ref    *v1alpha1.KeycloakClient
+	Msg    string
+	Realm  string
+}
+
+type DeleteAuthorizationPolicyAction struct {
+	Policy *v1alpha1.KeycloakPolicy
+	Ref    *v1alpha1.KeycloakClient
+	Msg    string
+	Realm  string
+}
+
+type CreateClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type UpdateClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type DeleteClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type CreateClientScopeProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClientScope
+	Msg    string
+	Realm  string
+}
+
+type UpdateClientScopeProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClientScope
+	Msg    string
+	Realm  string
+}
+
+type DeleteClientScopeProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClientScope
+	Msg    string
+	Realm  string
+}
+
+type CreateClientScopeRealmScopeMappingsAction struct {
+	Mappings *[]v1alpha1.RoleRepresentation
+	Ref      *v1alpha1.KeycloakClientScope
+	Msg      string
+	Realm    string
+}
+
+type DeleteClientScopeRealmScopeMappingsAction struct {
+	Mappings *[]v1alpha1.RoleRepresentation
+	Ref      *v1alpha1.KeycloakClientScope
+	Msg      string
+	Realm    string
+}
+
+type CreateClientScopeClientScopeMappingsAction struct {
+	Mappings *v1alpha1.ClientMappingsRepresentation
+	Ref      *v1alpha1.KeycloakClientScope
+	Msg      string
+	Realm    string
+}
+
+type DeleteClientScopeClientScopeMappingsAction struct {
+	Mappings *v1alpha1.ClientMappingsRepresentation
+	Ref      *v1alpha1.KeycloakClientScope
+	Msg      string
+	Realm    string
+}
+
+type UpdateRealmDefaultClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type DeleteRealmDefaultClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type UpdateRealmOptionalClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type DeleteRealmOptionalClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type PingAction struct {
+	Msg string
+}
+
+type AssignRealmRoleAction struct {
 	UserID string
 	Ref    *v1alpha1.KeycloakUserRole
 	Realm  string
@@ -525,6 +1745,18 @@ func (i DeleteClientRoleAction) Run(runner ActionRunner) (string, error) {
 	return i.Msg, runner.DeleteClientRole(i.Ref, i.Role.Name, i.Realm)
 }
 
+func (i CreateClientProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientProtocolMapper(i.Ref, i.Mapper, i.Realm)
+}
+
+func (i UpdateClientProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientProtocolMapper(i.Ref, i.Mapper, i.Realm)
+}
+
+func (i DeleteClientProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteClientProtocolMapper(i.Ref, i.Mapper, i.Realm)
+}
+
 func (i AddDefaultRolesAction) Run(runner ActionRunner) (string, error) {
 	return i.Msg, runner.AddDefaultRoles(i.Roles, i.DefaultRealmRoleID, i.Realm)
 }
@@ -569,6 +1801,246 @@ func (i DeleteClientAction) Run(runner ActionRunner) (string, error) {
 	return i.Msg, runner.DeleteClient(i.Ref, i.Realm)
 }
 
+func (i CreateRealmRoleAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateRealmRole(i.Ref, i.Realm)
+}
+
+func (i UpdateRealmRoleAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateRealmRole(i.Ref, i.Realm)
+}
+
+func (i DeleteRealmRoleAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteRealmRole(i.Ref, i.Realm)
+}
+
+func (i AddRealmRoleCompositesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddRealmRoleComposites(i.Ref, i.Roles, i.Realm)
+}
+
+func (i DeleteRealmRoleCompositesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteRealmRoleComposites(i.Ref, i.Roles, i.Realm)
+}
+
+func (i CreateGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateGroup(i.Ref, i.Group, i.ParentGroup, i.Realm)
+}
+
+func (i UpdateGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateGroup(i.Ref, i.Group, i.Realm)
+}
+
+func (i DeleteGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteGroup(i.Ref, i.Group, i.Realm)
+}
+
+func (i CreateGroupRealmRoleMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateGroupRealmRoleMappings(i.Ref, i.Group, i.Mappings, i.Realm)
+}
+
+func (i DeleteGroupRealmRoleMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteGroupRealmRoleMappings(i.Ref, i.Group, i.Mappings, i.Realm)
+}
+
+func (i CreateGroupClientRoleMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateGroupClientRoleMappings(i.Ref, i.Group, i.Mappings, i.Realm)
+}
+
+func (i DeleteGroupClientRoleMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteGroupClientRoleMappings(i.Ref, i.Group, i.Mappings, i.Realm)
+}
+
+func (i UpdateRealmDefaultGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateRealmDefaultGroup(i.Ref, i.Realm)
+}
+
+func (i DeleteRealmDefaultGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteRealmDefaultGroup(i.Ref, i.Realm)
+}
+
+func (i CreateUserAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateUser(i.Ref, i.Password, i.Realm)
+}
+
+func (i UpdateUserAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateUser(i.Ref, i.Realm)
+}
+
+func (i DeleteUserAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteUser(i.Ref, i.Realm)
+}
+
+func (i AddUserToGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddUserToGroup(i.Ref, i.GroupPath, i.Realm)
+}
+
+func (i RemoveUserFromGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveUserFromGroup(i.Ref, i.Group, i.Realm)
+}
+
+func (i AddUserFederatedIdentityAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddUserFederatedIdentity(i.Ref, i.FederatedIdentity, i.Realm)
+}
+
+func (i RemoveUserFederatedIdentityAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveUserFederatedIdentity(i.Ref, i.FederatedIdentity, i.Realm)
+}
+
+func (i CreateIdentityProviderAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateIdentityProvider(i.Ref, i.IdentityProvider, i.Realm)
+}
+
+func (i UpdateIdentityProviderAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateIdentityProvider(i.Ref, i.IdentityProvider, i.Realm)
+}
+
+func (i DeleteIdentityProviderAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteIdentityProvider(i.Ref, i.Realm)
+}
+
+func (i CreateIdentityProviderMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateIdentityProviderMapper(i.Ref, i.Mapper, i.Realm)
+}
+
+func (i UpdateIdentityProviderMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateIdentityProviderMapper(i.Ref, i.Mapper, i.Realm)
+}
+
+func (i DeleteIdentityProviderMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteIdentityProviderMapper(i.Ref, i.Mapper, i.Realm)
+}
+
+func (i CreateAuthenticationFlowAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateAuthenticationFlow(i.Ref, i.Realm)
+}
+
+func (i UpdateAuthenticationFlowAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateAuthenticationFlow(i.Ref, i.FlowID, i.Realm)
+}
+
+func (i DeleteAuthenticationFlowAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteAuthenticationFlow(i.Ref, i.FlowID, i.Realm)
+}
+
+func (i ReplaceAuthenticationExecutionsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.ReplaceAuthenticationExecutions(i.Ref, i.Existing, i.Realm)
+}
+
+func (i UpdateAuthenticationExecutionAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateAuthenticationExecution(i.Ref, i.FlowAlias, i.Execution, i.Realm)
+}
+
+func (i CreateAuthenticatorConfigAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateAuthenticatorConfig(i.Ref, i.ExecutionID, i.Config, i.Realm)
+}
+
+func (i UpdateAuthenticatorConfigAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateAuthenticatorConfig(i.Ref, i.Config, i.Realm)
+}
+
+func (i DeleteAuthenticatorConfigAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteAuthenticatorConfig(i.Ref, i.ConfigID, i.Realm)
+}
+
+func (i UpdateRealmFlowBindingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateRealmFlowBindings(i.Ref, i.Bindings, i.Realm)
+}
+
+func (i UpdateResourceServerAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateResourceServer(i.Ref, i.ResourceServer, i.Realm)
+}
+
+func (i CreateAuthorizationScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateAuthorizationScope(i.Ref, i.Scope, i.Realm)
+}
+
+func (i UpdateAuthorizationScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateAuthorizationScope(i.Ref, i.Scope, i.Realm)
+}
+
+func (i DeleteAuthorizationScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteAuthorizationScope(i.Ref, i.Scope, i.Realm)
+}
+
+func (i CreateAuthorizationResourceAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateAuthorizationResource(i.Ref, i.Resource, i.Realm)
+}
+
+func (i UpdateAuthorizationResourceAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateAuthorizationResource(i.Ref, i.Resource, i.Realm)
+}
+
+func (i DeleteAuthorizationResourceAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteAuthorizationResource(i.Ref, i.Resource, i.Realm)
+}
+
+func (i CreateAuthorizationPolicyAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateAuthorizationPolicy(i.Ref, i.Policy, i.Realm)
+}
+
+func (i UpdateAuthorizationPolicyAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateAuthorizationPolicy(i.Ref, i.Policy, i.Realm)
+}
+
+func (i DeleteAuthorizationPolicyAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteAuthorizationPolicy(i.Ref, i.Policy, i.Realm)
+}
+
+func (i CreateClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientScope(i.Ref, i.Realm)
+}
+
+func (i UpdateClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientScope(i.Ref, i.Realm)
+}
+
+func (i DeleteClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteClientScope(i.Ref, i.Realm)
+}
+
+func (i CreateClientScopeProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientScopeProtocolMapper(i.Ref, i.Mapper, i.Realm)
+}
+
+func (i UpdateClientScopeProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientScopeProtocolMapper(i.Ref, i.Mapper, i.Realm)
+}
+
+func (i DeleteClientScopeProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteClientScopeProtocolMapper(i.Ref, i.Mapper, i.Realm)
+}
+
+func (i CreateClientScopeRealmScopeMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientScopeRealmScopeMappings(i.Ref, i.Mappings, i.Realm)
+}
+
+func (i DeleteClientScopeRealmScopeMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteClientScopeRealmScopeMappings(i.Ref, i.Mappings, i.Realm)
+}
+
+func (i CreateClientScopeClientScopeMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientScopeClientScopeMappings(i.Ref, i.Mappings, i.Realm)
+}
+
+func (i DeleteClientScopeClientScopeMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteClientScopeClientScopeMappings(i.Ref, i.Mappings, i.Realm)
+}
+
+func (i UpdateRealmDefaultClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateRealmDefaultClientScope(i.Ref, i.Realm)
+}
+
+func (i DeleteRealmDefaultClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteRealmDefaultClientScope(i.Ref, i.Realm)
+}
+
+func (i UpdateRealmOptionalClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateRealmOptionalClientScope(i.Ref, i.Realm)
+}
+
+func (i DeleteRealmOptionalClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteRealmOptionalClientScope(i.Ref, i.Realm)
+}
+
 func (i PingAction) Run(runner ActionRunner) (string, error) {
 	return i.Msg, runner.Ping()
 }
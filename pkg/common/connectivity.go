@@ -0,0 +1,100 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// KeycloakContact is the result of the last attempt to connect and log in to a Keycloak
+type KeycloakContact struct {
+	// Reachable is true when the last login succeeded
+	Reachable bool
+	// Error is the error of the last login, if it failed
+	Error string
+	// LastAttempt is the time of the last login
+	LastAttempt time.Time
+	// LastSuccess is the time of the last successful login, zero if there was none
+	LastSuccess time.Time
+}
+
+// KeycloakContacts keeps the result of the last login to each Keycloak, so the readiness of the controller and the
+// status of the Keycloaks can be reported without sending requests to them
+type KeycloakContacts struct {
+	mutex    sync.RWMutex
+	contacts map[string]KeycloakContact
+	now      func() time.Time
+}
+
+func NewKeycloakContacts() *KeycloakContacts {
+	return &KeycloakContacts{contacts: map[string]KeycloakContact{}, now: time.Now}
+}
+
+// Contacts are the results of the logins of all clients, see LocalConfigKeycloakFactory.AuthenticatedClient
+var Contacts = NewKeycloakContacts()
+
+// Record records the result of a login to the keycloak
+func (c *KeycloakContacts) Record(kc v1alpha1.Keycloak, err error) {
+	name := keycloakMetricsName(kc)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	contact := c.contacts[name]
+	contact.LastAttempt = c.now()
+	contact.Reachable = err == nil
+	contact.Error = ""
+	if err != nil {
+		contact.Error = err.Error()
+	} else {
+		contact.LastSuccess = contact.LastAttempt
+	}
+	c.contacts[name] = contact
+}
+
+// Get returns the result of the last login to the keycloak, false if there was none
+func (c *KeycloakContacts) Get(kc v1alpha1.Keycloak) (KeycloakContact, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	contact, ok := c.contacts[keycloakMetricsName(kc)]
+	return contact, ok
+}
+
+// Forget removes the results of a deleted keycloak
+func (c *KeycloakContacts) Forget(kc v1alpha1.Keycloak) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.contacts, keycloakMetricsName(kc))
+}
+
+// Check is a readiness check that fails when none of the keycloaks could be logged in to the last time. The controller
+// is ready as long as it doesn't know any keycloak.
+func (c *KeycloakContacts) Check(_ *http.Request) error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if len(c.contacts) == 0 {
+		return nil
+	}
+
+	var failures []string
+	for name, contact := range c.contacts {
+		if contact.Reachable {
+			return nil
+		}
+		failures = append(failures, name+": "+contact.Error)
+	}
+	sort.Strings(failures)
+	return errors.Errorf("no keycloak is reachable: %v", strings.Join(failures, "; "))
+}
+
+// recordContact records the result of a login, unless the login was cancelled with the reconciliation
+func recordContact(ctx context.Context, kc v1alpha1.Keycloak, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	Contacts.Record(kc, err)
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConnectivity_Test_Record(t *testing.T) {
+	// given
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	contacts := NewKeycloakContacts()
+	contacts.now = func() time.Time { return now }
+	keycloak := v1alpha1.Keycloak{ObjectMeta: v12.ObjectMeta{Namespace: "keycloak", Name: "keycloak"}}
+
+	// when
+	contacts.Record(keycloak, nil)
+	now = now.Add(time.Minute)
+	contacts.Record(keycloak, errors.New("invalid_grant"))
+	contact, ok := contacts.Get(keycloak)
+
+	// then
+	assert.True(t, ok)
+	assert.False(t, contact.Reachable)
+	assert.Equal(t, "invalid_grant", contact.Error)
+	assert.Equal(t, now, contact.LastAttempt)
+	// the last successful login is kept
+	assert.Equal(t, now.Add(-time.Minute), contact.LastSuccess)
+}
+
+func TestConnectivity_Test_Record_ClusterKeycloak(t *testing.T) {
+	// given
+	contacts := NewKeycloakContacts()
+	keycloak := v1alpha1.Keycloak{ObjectMeta: v12.ObjectMeta{Namespace: "operator", Name: "keycloak"}}
+	clusterKeycloak := &v1alpha1.ClusterKeycloak{ObjectMeta: v12.ObjectMeta{Name: "keycloak"}}
+
+	// when
+	contacts.Record(keycloak, errors.New("connection refused"))
+	contacts.Record(clusterKeycloak.ToKeycloak("operator"), nil)
+	contact, _ := contacts.Get(keycloak)
+	clusterContact, _ := contacts.Get(clusterKeycloak.ToKeycloak("operator"))
+
+	// then
+	// the view of the cluster keycloak is placed in the operator namespace, but doesn't share the contact
+	assert.False(t, contact.Reachable)
+	assert.True(t, clusterContact.Reachable)
+}
+
+func TestConnectivity_Test_Check(t *testing.T) {
+	// given
+	contacts := NewKeycloakContacts()
+	keycloak := v1alpha1.Keycloak{ObjectMeta: v12.ObjectMeta{Namespace: "keycloak", Name: "keycloak"}}
+	other := v1alpha1.Keycloak{ObjectMeta: v12.ObjectMeta{Namespace: "keycloak", Name: "other"}}
+
+	// when
+	withoutKeycloaks := contacts.Check(nil)
+	contacts.Record(keycloak, errors.New("connection refused"))
+	unreachable := contacts.Check(nil)
+	contacts.Record(other, nil)
+	oneReachable := contacts.Check(nil)
+	contacts.Forget(other)
+	forgotten := contacts.Check(nil)
+
+	// then
+	assert.NoError(t, withoutKeycloaks)
+	assert.EqualError(t, unreachable, "no keycloak is reachable: keycloak/keycloak: connection refused")
+	assert.NoError(t, oneReachable)
+	assert.Error(t, forgotten)
+}
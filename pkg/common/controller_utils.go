@@ -3,10 +3,22 @@ package common
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
 
 	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -17,6 +29,9 @@ const (
 	SecretKind = "Secret"
 )
 
+// client for reading the SAML metadata of service providers
+var samlMetadataClient = &http.Client{Timeout: 30 * time.Second}
+
 func WatchSecondaryResource(c controller.Controller, controllerName string, resourceKind string, objectTypetoWatch client.Object, cr runtime.Object) error {
 	stateManager := GetStateManager()
 	stateFieldName := GetStateFieldName(controllerName, resourceKind)
@@ -71,3 +86,307 @@ func GetMatchingRealms(ctx context.Context, c client.Client, labelSelector *v1.L
 	err := c.List(ctx, &list, opts...)
 	return list, err
 }
+
+// Try to get a list of cluster keycloak instances that match the selector specified on the cluster realm
+func GetMatchingClusterKeycloaks(ctx context.Context, c client.Client, labelSelector *v1.LabelSelector) (v1alpha1.ClusterKeycloakList, error) {
+	var list v1alpha1.ClusterKeycloakList
+	opts := []client.ListOption{
+		client.MatchingLabels(labelSelector.MatchLabels),
+	}
+
+	err := c.List(ctx, &list, opts...)
+	return list, err
+}
+
+// Try to get a list of cluster realms that match the selector specified on the client
+func GetMatchingClusterRealms(ctx context.Context, c client.Client, labelSelector *v1.LabelSelector) (v1alpha1.ClusterKeycloakRealmList, error) {
+	var list v1alpha1.ClusterKeycloakRealmList
+	opts := []client.ListOption{
+		client.MatchingLabels(labelSelector.MatchLabels),
+	}
+
+	err := c.List(ctx, &list, opts...)
+	return list, err
+}
+
+// RealmInstance is a realm together with a keycloak instance it is served by
+type RealmInstance struct {
+	Realm    v1alpha1.KeycloakRealm
+	Keycloak v1alpha1.Keycloak
+}
+
+// Try to get a list of realms and the keycloak instances they are served by that match the realm selectors
+// of a resource. Cluster scoped realms and keycloaks are converted to their namespaced view, the admin
+// credentials of cluster scoped keycloaks are expected in the operator namespace. As a resource is reconciled in every
+// realm instance, it is found in Keycloak by its name, alias or path rather than by an ID of one of the instances.
+func GetMatchingRealmInstances(ctx context.Context, c client.Client, operatorNamespace string, realmSelector, clusterRealmSelector *v1.LabelSelector) ([]RealmInstance, error) {
+	if realmSelector == nil && clusterRealmSelector == nil {
+		return nil, PermanentErrorf("either realmSelector or clusterRealmSelector needs to be set")
+	}
+
+	var instances []RealmInstance
+	if realmSelector != nil {
+		realms, err := GetMatchingRealms(ctx, c, realmSelector)
+		if err != nil {
+			return nil, err
+		}
+		for _, realm := range realms.Items {
+			// realms without an instance selector are ignored by the realm controller as well
+			if realm.Spec.InstanceSelector == nil {
+				continue
+			}
+			keycloaks, err := GetMatchingKeycloaks(ctx, c, realm.Spec.InstanceSelector)
+			if err != nil {
+				return nil, err
+			}
+			log.Info(fmt.Sprintf("found %v matching keycloak(s) for realm %v/%v", len(keycloaks.Items), realm.Namespace, realm.Name))
+			for _, keycloak := range keycloaks.Items {
+				instances = append(instances, RealmInstance{Realm: realm, Keycloak: keycloak})
+			}
+		}
+	}
+
+	if clusterRealmSelector != nil {
+		if operatorNamespace == "" {
+			return nil, errors.New("operator namespace is unknown, set OPERATOR_NAMESPACE to use cluster scoped realms")
+		}
+		clusterRealms, err := GetMatchingClusterRealms(ctx, c, clusterRealmSelector)
+		if err != nil {
+			return nil, err
+		}
+		for _, clusterRealm := range clusterRealms.Items {
+			if clusterRealm.Spec.InstanceSelector == nil {
+				continue
+			}
+			clusterKeycloaks, err := GetMatchingClusterKeycloaks(ctx, c, clusterRealm.Spec.InstanceSelector)
+			if err != nil {
+				return nil, err
+			}
+			log.Info(fmt.Sprintf("found %v matching cluster keycloak(s) for cluster realm %v", len(clusterKeycloaks.Items), clusterRealm.Name))
+			for _, clusterKeycloak := range clusterKeycloaks.Items {
+				instances = append(instances, RealmInstance{
+					Realm:    clusterRealm.ToKeycloakRealm(),
+					Keycloak: clusterKeycloak.ToKeycloak(operatorNamespace),
+				})
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// GetClientTemplate returns the client defaults of the KeycloakClientTemplate or ClusterKeycloakClientTemplate the
+// client refers to, nil if the client doesn't refer to a template
+func GetClientTemplate(ctx context.Context, c client.Client, cr *v1alpha1.KeycloakClient) (*v1alpha1.KeycloakAPIClient, error) {
+	if cr.Spec.TemplateRef == nil {
+		return nil, nil
+	}
+
+	if cr.Spec.TemplateRef.Kind == v1alpha1.ClusterClientTemplateKind {
+		template := &v1alpha1.ClusterKeycloakClientTemplate{}
+		err := c.Get(ctx, types.NamespacedName{Name: cr.Spec.TemplateRef.Name}, template)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get cluster client template %v", cr.Spec.TemplateRef.Name)
+		}
+		return template.Spec.Client, nil
+	}
+
+	template := &v1alpha1.KeycloakClientTemplate{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Spec.TemplateRef.Name}, template)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get client template %v/%v", cr.Namespace, cr.Spec.TemplateRef.Name)
+	}
+	return template.Spec.Client, nil
+}
+
+// GetClientFields returns the client of the KeycloakClient as it is stored. Unlike the decoded client, fields that are
+// not set are missing, so they can be told apart from fields that are set to false or 0.
+func GetClientFields(ctx context.Context, c client.Client, cr *v1alpha1.KeycloakClient) (map[string]interface{}, error) {
+	stored := &unstructured.Unstructured{}
+	stored.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("KeycloakClient"))
+	err := c.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, stored)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get client %v/%v", cr.Namespace, cr.Name)
+	}
+	fields, _, err := unstructured.NestedMap(stored.Object, "spec", "client")
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read client %v/%v", cr.Namespace, cr.Name)
+	}
+	return fields, nil
+}
+
+// GetRedirectURIOrigins returns the origins of the hosts of the Ingresses and HTTPRoutes the redirect URIs of the
+// client are derived from, in the order of the sources. Objects selected by labels are ordered by name.
+func GetRedirectURIOrigins(ctx context.Context, c client.Client, cr *v1alpha1.KeycloakClient) ([]string, error) {
+	sources := cr.Spec.RedirectURISources
+	if sources == nil {
+		return nil, nil
+	}
+
+	var origins []string
+	for _, ref := range sources.Ingresses {
+		ingresses := &networkingv1.IngressList{}
+		err := getRedirectURISourceObjects(ctx, c, cr.Namespace, ref, ingresses, &networkingv1.Ingress{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get ingresses for redirect uris")
+		}
+		sort.Slice(ingresses.Items, func(i, j int) bool {
+			return ingresses.Items[i].Name < ingresses.Items[j].Name
+		})
+		for index := range ingresses.Items {
+			origins = append(origins, model.IngressOrigins(&ingresses.Items[index], sources.Scheme)...)
+		}
+	}
+
+	for _, ref := range sources.HTTPRoutes {
+		gvk, err := HTTPRouteGroupVersionKind(c.RESTMapper())
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get http routes for redirect uris")
+		}
+		routes := &unstructured.UnstructuredList{}
+		routes.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(gvk)
+		err = getRedirectURISourceObjects(ctx, c, cr.Namespace, ref, routes, route)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get http routes for redirect uris")
+		}
+		sort.Slice(routes.Items, func(i, j int) bool {
+			return routes.Items[i].GetName() < routes.Items[j].GetName()
+		})
+		for index := range routes.Items {
+			origins = append(origins, model.HTTPRouteOrigins(&routes.Items[index], sources.Scheme)...)
+		}
+	}
+
+	return uniqueStrings(origins), nil
+}
+
+// HTTPRouteGroupVersionKind returns the kind of the HTTPRoutes in the version preferred by the cluster, e.g. v1beta1
+// when the installed Gateway API doesn't serve v1 yet
+func HTTPRouteGroupVersionKind(mapper meta.RESTMapper) (schema.GroupVersionKind, error) {
+	mapping, err := mapper.RESTMapping(model.HTTPRouteGroupKind)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return mapping.GroupVersionKind, nil
+}
+
+// getRedirectURISourceObjects fills the list with the object of the given name or with the objects matching the
+// selector of the reference
+func getRedirectURISourceObjects(ctx context.Context, c client.Client, namespace string, ref v1alpha1.RedirectURISourceReference, list client.ObjectList, obj client.Object) error {
+	if ref.Name != "" {
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, obj)
+		if err != nil {
+			return err
+		}
+		return meta.SetList(list, []runtime.Object{obj})
+	}
+
+	if ref.Selector == nil {
+		return PermanentErrorf("either name or selector needs to be set")
+	}
+	selector, err := v1.LabelSelectorAsSelector(ref.Selector)
+	if err != nil {
+		return err
+	}
+	return c.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+}
+
+func uniqueStrings(values []string) []string {
+	var unique []string
+	seen := map[string]bool{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// GetClientSAMLSources reads the metadata of the service provider and the key material of a SAML client from the
+// URL, ConfigMap and Secrets referenced in its SAML settings
+func GetClientSAMLSources(ctx context.Context, c client.Client, cr *v1alpha1.KeycloakClient) (*model.SAMLServiceProviderMetadata, model.SAMLKeyMaterial, error) {
+	keys := model.SAMLKeyMaterial{}
+	saml := cr.Spec.SAML
+	if saml == nil {
+		return nil, keys, nil
+	}
+
+	var metadata *model.SAMLServiceProviderMetadata
+	if saml.ServiceProviderMetadata != nil {
+		data, err := getSAMLMetadata(ctx, c, cr.Namespace, saml.ServiceProviderMetadata)
+		if err != nil {
+			return nil, keys, errors.Wrap(err, "cannot read saml metadata of the service provider")
+		}
+		metadata, err = model.ParseSAMLServiceProviderMetadata(data)
+		if err != nil {
+			return nil, keys, err
+		}
+	}
+
+	if saml.SigningKeySecret != "" {
+		secret := &corev1.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: saml.SigningKeySecret}, secret)
+		if err != nil {
+			return nil, keys, errors.Wrapf(err, "cannot get saml signing key secret %v", saml.SigningKeySecret)
+		}
+		keys.SigningCertificate, err = model.SAMLCertificate(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			return nil, keys, errors.Wrapf(err, "invalid certificate in saml signing key secret %v", saml.SigningKeySecret)
+		}
+		keys.SigningPrivateKey, err = model.SAMLPrivateKey(secret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, keys, errors.Wrapf(err, "invalid private key in saml signing key secret %v", saml.SigningKeySecret)
+		}
+	}
+
+	if saml.EncryptionCertificateSecret != "" {
+		secret := &corev1.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: saml.EncryptionCertificateSecret}, secret)
+		if err != nil {
+			return nil, keys, errors.Wrapf(err, "cannot get saml encryption certificate secret %v", saml.EncryptionCertificateSecret)
+		}
+		keys.EncryptionCertificate, err = model.SAMLCertificate(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			return nil, keys, errors.Wrapf(err, "invalid certificate in saml encryption certificate secret %v", saml.EncryptionCertificateSecret)
+		}
+	}
+
+	return metadata, keys, nil
+}
+
+func getSAMLMetadata(ctx context.Context, c client.Client, namespace string, source *v1alpha1.SAMLMetadataSource) ([]byte, error) {
+	if source.ConfigMapKeyRef != nil {
+		configMap := &corev1.ConfigMap{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: source.ConfigMapKeyRef.Name}, configMap)
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := configMap.Data[source.ConfigMapKeyRef.Key]; ok {
+			return []byte(value), nil
+		}
+		if value, ok := configMap.BinaryData[source.ConfigMapKeyRef.Key]; ok {
+			return value, nil
+		}
+		return nil, errors.Errorf("key %v not found in config map %v/%v", source.ConfigMapKeyRef.Key, namespace, source.ConfigMapKeyRef.Name)
+	}
+
+	if source.URL == "" {
+		return nil, PermanentErrorf("either url or configMapKeyRef needs to be set")
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", source.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := samlMetadataClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return nil, errors.Errorf("failed to GET %v: (%d) %s", source.URL, res.StatusCode, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
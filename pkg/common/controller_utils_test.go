@@ -0,0 +1,85 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestControllerUtils_GetRedirectURIOrigins_HTTPRouteVersion(t *testing.T) {
+	// given
+	// the gateway api of the cluster only serves v1beta1
+	v1beta1 := schema.GroupVersion{Group: "gateway.networking.k8s.io", Version: "v1beta1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{v1beta1})
+	mapper.Add(v1beta1.WithKind("HTTPRoute"), meta.RESTScopeNamespace)
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(v1beta1.WithKind("HTTPRoute"))
+	route.SetNamespace("my-app")
+	route.SetName("shop")
+	assert.NoError(t, unstructured.SetNestedStringSlice(route.Object, []string{"shop.example.com"}, "spec", "hostnames"))
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(route).Build()
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v1.ObjectMeta{Namespace: "my-app", Name: "shop"},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RedirectURISources: &v1alpha1.RedirectURISources{HTTPRoutes: []v1alpha1.RedirectURISourceReference{{Name: "shop"}}},
+		},
+	}
+
+	// when
+	gvk, gvkErr := HTTPRouteGroupVersionKind(mapper)
+	origins, err := GetRedirectURIOrigins(context.TODO(), c, cr)
+
+	// then
+	assert.NoError(t, gvkErr)
+	assert.Equal(t, "v1beta1", gvk.Version)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://shop.example.com"}, origins)
+}
+
+func TestControllerUtils_GetMatchingRealmInstances_Without_InstanceSelector(t *testing.T) {
+	// given
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.ClusterKeycloak{ObjectMeta: v1.ObjectMeta{Name: "central", Labels: map[string]string{"app": "central"}}},
+		&v1alpha1.ClusterKeycloakRealm{
+			ObjectMeta: v1.ObjectMeta{Name: "employees", Labels: map[string]string{"realm": "employees"}},
+			Spec: v1alpha1.KeycloakRealmSpec{
+				InstanceSelector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "central"}},
+				Realm:            &v1alpha1.KeycloakAPIRealm{Realm: "employees"},
+			},
+		},
+		&v1alpha1.ClusterKeycloakRealm{
+			ObjectMeta: v1.ObjectMeta{Name: "unassigned", Labels: map[string]string{"realm": "employees"}},
+			Spec:       v1alpha1.KeycloakRealmSpec{Realm: &v1alpha1.KeycloakAPIRealm{Realm: "unassigned"}},
+		},
+		&v1alpha1.KeycloakRealm{
+			ObjectMeta: v1.ObjectMeta{Namespace: "keycloak", Name: "unassigned", Labels: map[string]string{"realm": "shop"}},
+			Spec:       v1alpha1.KeycloakRealmSpec{Realm: &v1alpha1.KeycloakAPIRealm{Realm: "unassigned"}},
+		},
+	).Build()
+	selector := &v1.LabelSelector{MatchLabels: map[string]string{"realm": "employees"}}
+	namespacedSelector := &v1.LabelSelector{MatchLabels: map[string]string{"realm": "shop"}}
+
+	// when
+	instances, err := GetMatchingRealmInstances(context.TODO(), c, "operator", namespacedSelector, selector)
+
+	// then
+	// the realms without instance selector are skipped
+	assert.NoError(t, err)
+	assert.Len(t, instances, 1)
+	assert.Equal(t, "employees", instances[0].Realm.Spec.Realm.Realm)
+	assert.Equal(t, "central", instances[0].Keycloak.Name)
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// PermanentError is an error that doesn't go away until the spec of the resource changes, e.g. an invalid combination
+// of fields. Resources failing with a permanent error are not requeued.
+type PermanentError struct {
+	err error
+}
+
+// NewPermanentError marks the error as permanent
+func NewPermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{err: err}
+}
+
+// PermanentErrorf formats a permanent error
+func PermanentErrorf(format string, args ...interface{}) error {
+	return &PermanentError{err: errors.Errorf(format, args...)}
+}
+
+func (e *PermanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.err
+}
+
+// APIError is returned when the Keycloak API answers a request with an unexpected status code
+type APIError struct {
+	// Message describes the request that failed
+	Message    string
+	StatusCode int
+	Status     string
+}
+
+func newAPIError(res *http.Response, format string, args ...interface{}) error {
+	return errors.WithStack(&APIError{Message: fmt.Sprintf(format, args...), StatusCode: res.StatusCode, Status: res.Status})
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: (%d) %s", e.Message, e.StatusCode, e.Status)
+}
+
+// Permanent checks whether Keycloak rejected the request as invalid, sending it again won't help until the resource
+// sent is changed. Other status codes, e.g. 401 until the admin credentials are rotated, 404 while a parent resource is
+// created or 5xx while Keycloak is unavailable, are expected to go away.
+func (e *APIError) Permanent() bool {
+	return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+}
+
+// IsPermanent checks whether the error or one of the errors it wraps is permanent. All other errors, e.g. network
+// errors or missing resources, are transient and retried with backoff.
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return true
+	}
+	var apiError *APIError
+	return errors.As(err, &apiError) && apiError.Permanent()
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors_Test_IsPermanent(t *testing.T) {
+	// given
+	invalidSpec := PermanentErrorf("either name or selector needs to be set")
+	badRequest := &APIError{Message: "failed to create client", StatusCode: 400, Status: "400 Bad Request"}
+	unavailable := &APIError{Message: "failed to create client", StatusCode: 503, Status: "503 Service Unavailable"}
+
+	// then
+	assert.True(t, IsPermanent(invalidSpec))
+	assert.True(t, IsPermanent(errors.Wrap(invalidSpec, "cannot resolve the realms")))
+	assert.True(t, IsPermanent(errors.WithStack(badRequest)))
+	assert.False(t, IsPermanent(unavailable))
+	assert.False(t, IsPermanent(errors.New("connection refused")))
+	assert.False(t, IsPermanent(nil))
+	assert.Nil(t, NewPermanentError(nil))
+}
+
+func TestErrors_Test_Client_Returns_APIError(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(400)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	_, err := client.CreateRealm(context.TODO(), getDummyRealm())
+
+	// then
+	var apiError *APIError
+	assert.True(t, errors.As(err, &apiError))
+	assert.Equal(t, 400, apiError.StatusCode)
+	assert.Equal(t, "failed to create realm: (400) 400 Bad Request", err.Error())
+	assert.True(t, IsPermanent(err))
+}
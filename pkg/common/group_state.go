@@ -0,0 +1,113 @@
+package common
+
+import (
+	"context"
+	"sort"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type GroupState struct {
+	// Existing groups by path, for the group and all desired subgroups
+	Groups map[string]*kc.KeycloakAPIGroup
+	// Role mappings of the existing groups by path
+	RoleMappings  map[string]*kc.MappingsRepresentation
+	DefaultGroups []kc.KeycloakAPIGroup
+	// Roles that can be mapped to the groups
+	AvailableRoles
+	Context  context.Context
+	Realm    *kc.KeycloakRealm
+	Keycloak kc.Keycloak
+}
+
+func NewGroupState(context context.Context, realm *kc.KeycloakRealm, keycloak kc.Keycloak) *GroupState {
+	return &GroupState{
+		Context:  context,
+		Realm:    realm,
+		Keycloak: keycloak,
+	}
+}
+
+func (i *GroupState) Read(context context.Context, cr *kc.KeycloakGroup, realmClient KeycloakInterface, controllerClient client.Client) error {
+	realmName := i.Realm.Spec.Realm.Realm
+
+	i.Groups = map[string]*kc.KeycloakAPIGroup{}
+	i.RoleMappings = map[string]*kc.MappingsRepresentation{}
+
+	err := i.readGroup(context, realmClient, realmName, cr.Spec.Group, GroupPath("", cr.Spec.Group))
+	if err != nil {
+		return err
+	}
+
+	i.DefaultGroups, err = realmClient.ListRealmDefaultGroups(context, realmName)
+	if err != nil {
+		return err
+	}
+
+	clientIDs := map[string]bool{}
+	collectGroupClientIDs(cr.Spec.Group, clientIDs)
+	sortedClientIDs := make([]string, 0, len(clientIDs))
+	for clientID := range clientIDs {
+		sortedClientIDs = append(sortedClientIDs, clientID)
+	}
+	sort.Strings(sortedClientIDs)
+
+	return i.readAvailableRoles(context, realmClient, realmName, sortedClientIDs)
+}
+
+// readGroup reads the existing group with the given path and its role mappings, followed by the desired subgroups
+func (i *GroupState) readGroup(context context.Context, realmClient KeycloakInterface, realmName string, group *kc.KeycloakAPIGroup, path string) error {
+	existing, err := realmClient.GetGroupByPath(context, path, realmName)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		group.ID = ""
+	} else {
+		group.ID = existing.ID
+		i.Groups[path] = existing
+
+		i.RoleMappings[path], err = realmClient.ListGroupRoleMappings(context, existing.ID, realmName)
+		if err != nil {
+			return err
+		}
+	}
+
+	for index := range group.SubGroups {
+		subGroup := &group.SubGroups[index]
+		if existing == nil {
+			// subgroups of a missing group can't exist
+			clearGroupIDs(subGroup)
+			continue
+		}
+		err = i.readGroup(context, realmClient, realmName, subGroup, GroupPath(path, subGroup))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GroupPath returns the path of the group below the parent group with the given path
+func GroupPath(parentPath string, group *kc.KeycloakAPIGroup) string {
+	return parentPath + "/" + group.Name
+}
+
+func clearGroupIDs(group *kc.KeycloakAPIGroup) {
+	group.ID = ""
+	for index := range group.SubGroups {
+		clearGroupIDs(&group.SubGroups[index])
+	}
+}
+
+func collectGroupClientIDs(group *kc.KeycloakAPIGroup, clientIDs map[string]bool) {
+	for clientID := range group.ClientRoles {
+		clientIDs[clientID] = true
+	}
+	for index := range group.SubGroups {
+		collectGroupClientIDs(&group.SubGroups[index], clientIDs)
+	}
+}
@@ -0,0 +1,80 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type IdentityProviderState struct {
+	IdentityProvider *kc.KeycloakAPIIdentityProvider
+	Mappers          []kc.KeycloakAPIIdentityProviderMapper
+	// Client secret read from the secret referenced by the KeycloakIdentityProvider
+	ClientSecret        string
+	ClientSecretVersion string
+	Context             context.Context
+	Realm               *kc.KeycloakRealm
+	Keycloak            kc.Keycloak
+}
+
+func NewIdentityProviderState(context context.Context, realm *kc.KeycloakRealm, keycloak kc.Keycloak) *IdentityProviderState {
+	return &IdentityProviderState{
+		Context:  context,
+		Realm:    realm,
+		Keycloak: keycloak,
+	}
+}
+
+func (i *IdentityProviderState) Read(context context.Context, cr *kc.KeycloakIdentityProvider, realmClient KeycloakInterface, controllerClient client.Client) error {
+	err := i.readClientSecret(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
+	i.IdentityProvider, err = realmClient.GetIdentityProvider(context, cr.Spec.IdentityProvider.Alias, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	if i.IdentityProvider == nil {
+		cr.Spec.IdentityProvider.InternalID = ""
+		return nil
+	}
+
+	cr.Spec.IdentityProvider.InternalID = i.IdentityProvider.InternalID
+
+	i.Mappers, err = realmClient.ListIdentityProviderMappers(context, cr.Spec.IdentityProvider.Alias, i.Realm.Spec.Realm.Realm)
+	return err
+}
+
+func (i *IdentityProviderState) readClientSecret(context context.Context, cr *kc.KeycloakIdentityProvider, controllerClient client.Client) error {
+	selector := cr.Spec.ClientSecret
+	if selector == nil {
+		return nil
+	}
+
+	secret := &v1.Secret{}
+	err := controllerClient.Get(context, client.ObjectKey{Name: selector.Name, Namespace: cr.Namespace}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) && selector.Optional != nil && *selector.Optional {
+			return nil
+		}
+		return err
+	}
+
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		if selector.Optional != nil && *selector.Optional {
+			return nil
+		}
+		return fmt.Errorf("key %v not found in client secret %v/%v", selector.Key, cr.Namespace, selector.Name)
+	}
+
+	i.ClientSecret = string(value)
+	i.ClientSecretVersion = secret.ResourceVersion
+	return nil
+}
@@ -0,0 +1,202 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "keycloak_controller"
+
+	MetricsResultSuccess = "success"
+	MetricsResultFailure = "failure"
+)
+
+var (
+	keycloakRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "keycloak_requests_total",
+		Help:      "Number of requests to the Keycloak API by Keycloak, method, path template and status code",
+	}, []string{"keycloak", "method", "path", "code"})
+
+	keycloakRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "keycloak_request_duration_seconds",
+		Help:      "Duration of the requests to the Keycloak API by Keycloak, method and path template",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"keycloak", "method", "path"})
+
+	keycloakLogins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "keycloak_logins_total",
+		Help:      "Number of logins to the Keycloak API by Keycloak and result",
+	}, []string{"keycloak", "result"})
+
+	actions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "actions_total",
+		Help:      "Number of actions run to reconcile resources by resource kind, action and result",
+	}, []string{"resource", "action", "result"})
+
+	drift = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "drift_total",
+		Help:      "Number of differences between the resources and Keycloak found in reconciliations, by resource kind and action planned to correct them",
+	}, []string{"resource", "action"})
+
+	managedClients = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "managed_clients",
+		Help:      "Number of KeycloakClients managed in a realm",
+	}, []string{"realm"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(keycloakRequests, keycloakRequestDuration, keycloakLogins, actions, drift, managedClients)
+}
+
+// instrumentedRequester records the requests to the Keycloak API of a Keycloak in the metrics
+type instrumentedRequester struct {
+	requester Requester
+	keycloak  string
+}
+
+func newInstrumentedRequester(requester Requester, keycloak string) Requester {
+	return &instrumentedRequester{requester: requester, keycloak: keycloak}
+}
+
+func (i *instrumentedRequester) Do(req *http.Request) (*http.Response, error) {
+	path := pathTemplate(req.URL.Path)
+	start := time.Now()
+	res, err := i.requester.Do(req)
+	keycloakRequestDuration.WithLabelValues(i.keycloak, req.Method, path).Observe(time.Since(start).Seconds())
+
+	// requests without response are counted with code 0
+	code := "0"
+	if err == nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	keycloakRequests.WithLabelValues(i.keycloak, req.Method, path, code).Inc()
+	return res, err
+}
+
+// literal segments of the paths of the Keycloak API, all other segments are names or IDs
+var keycloakPathSegments = map[string]bool{
+	"auth": true, "admin": true, "realms": true, "clients": true, "client-scopes": true, "roles": true,
+	"roles-by-id": true, "composites": true, "realm": true, "users": true, "groups": true, "children": true,
+	"role-mappings": true, "scope-mappings": true, "protocol-mappers": true, "models": true,
+	"default-client-scopes": true, "optional-client-scopes": true, "default-default-client-scopes": true,
+	"default-optional-client-scopes": true, "default-groups": true, "client-secret": true,
+	"service-account-user": true, "installation": true, "providers": true, "authz": true,
+	"resource-server": true, "scope": true, "resource": true, "policy": true, "permission": true,
+	"associatedPolicies": true, "resources": true, "scopes": true, "authentication": true, "flows": true,
+	"executions": true, "execution": true, "config": true, "identity-provider": true, "instances": true,
+	"mappers": true, "federated-identity": true, "available": true, "effective": true, "serverinfo": true,
+	"certificates": true, "upload-certificate": true, "protocol": true, "openid-connect": true, "token": true,
+	"search": true, "role": true, "client": true, "user": true, "group": true, "aggregate": true, "time": true,
+	"js": true, "keys": true, "components": true, "members": true, "count": true,
+}
+
+// pathTemplate replaces the names and IDs in the path of a Keycloak API request with placeholders, to keep the
+// number of label values of the metrics low
+func pathTemplate(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for index, segment := range segments {
+		switch {
+		case index > 0 && segments[index-1] == "realms":
+			segments[index] = "{realm}"
+		case !keycloakPathSegments[segment]:
+			segments[index] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// recordLogin records the result of a login to the Keycloak API
+func recordLogin(keycloak string, err error) {
+	result := MetricsResultSuccess
+	if err != nil {
+		result = MetricsResultFailure
+	}
+	keycloakLogins.WithLabelValues(keycloak, result).Inc()
+}
+
+// recordDrift records the actions planned to bring Keycloak to the state of a resource, except for pings
+func recordDrift(resource string, desiredState DesiredClusterState) {
+	for _, action := range desiredState {
+		if _, ok := action.(PingAction); ok {
+			continue
+		}
+		drift.WithLabelValues(resource, actionName(action)).Inc()
+	}
+}
+
+// recordAction records the result of an action
+func recordAction(resource string, action ClusterAction, err error) {
+	result := MetricsResultSuccess
+	if err != nil {
+		result = MetricsResultFailure
+	}
+	actions.WithLabelValues(resource, actionName(action), result).Inc()
+}
+
+func actionName(action ClusterAction) string {
+	return reflect.Indirect(reflect.ValueOf(action)).Type().Name()
+}
+
+func resourceKind(obj interface{}) string {
+	if obj == nil {
+		return ""
+	}
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
+
+// keycloakMetricsName is the name of a Keycloak in the metrics. It also keys the contacts, the rate limiters and the
+// in-flight slots, ClusterKeycloaks are prefixed with their kind to tell them apart from the Keycloaks of the namespace
+// their views are placed in.
+func keycloakMetricsName(kc v1alpha1.Keycloak) string {
+	if kc.Kind == v1alpha1.ClusterKeycloakKind {
+		return fmt.Sprintf("%v/%v", v1alpha1.ClusterKeycloakKind, kc.Name)
+	}
+	if kc.Namespace == "" {
+		return kc.Name
+	}
+	return fmt.Sprintf("%v/%v", kc.Namespace, kc.Name)
+}
+
+// ManagedClients keeps track of the realms the KeycloakClients are managed in for the managed clients metric
+type ManagedClients struct {
+	mutex  sync.Mutex
+	realms map[string][]string
+}
+
+func NewManagedClients() *ManagedClients {
+	return &ManagedClients{realms: map[string][]string{}}
+}
+
+// Set records the realms a client is managed in, no realms remove the client
+func (m *ManagedClients) Set(client string, realms []string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, realm := range m.realms[client] {
+		managedClients.WithLabelValues(realm).Dec()
+	}
+	if len(realms) == 0 {
+		delete(m.realms, client)
+		return
+	}
+	m.realms[client] = realms
+	for _, realm := range realms {
+		managedClients.WithLabelValues(realm).Inc()
+	}
+}
@@ -0,0 +1,119 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func metricValue(t *testing.T, metric prometheus.Metric) float64 {
+	value := &dto.Metric{}
+	assert.NoError(t, metric.Write(value))
+	switch {
+	case value.Counter != nil:
+		return value.Counter.GetValue()
+	case value.Gauge != nil:
+		return value.Gauge.GetValue()
+	case value.Histogram != nil:
+		return float64(value.Histogram.GetSampleCount())
+	}
+	return 0
+}
+
+func TestMetrics_Test_PathTemplate(t *testing.T) {
+	// then
+	assert.Equal(t, "/auth/admin/realms/{realm}/clients/{id}/roles/{id}", pathTemplate("/auth/admin/realms/test/clients/abc/roles/x"))
+	assert.Equal(t, "/auth/admin/realms/{realm}", pathTemplate("/auth/admin/realms/clients"))
+	assert.Equal(t, "/auth/realms/{realm}/protocol/openid-connect/token", pathTemplate(TokenPath))
+	assert.Equal(t, "/auth/admin/realms/{realm}/users/{id}/role-mappings/realm", pathTemplate("/auth/admin/realms/test/users/42/role-mappings/realm"))
+}
+
+func TestMetrics_Test_InstrumentedRequester(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(404)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	requester := newInstrumentedRequester(&http.Client{}, "metrics/requester")
+	counter := keycloakRequests.WithLabelValues("metrics/requester", http.MethodGet, "/auth/admin/realms/{realm}", "404")
+	failures := keycloakRequests.WithLabelValues("metrics/requester", http.MethodGet, "/auth/admin/realms/{realm}", "0")
+	duration := keycloakRequestDuration.WithLabelValues("metrics/requester", http.MethodGet, "/auth/admin/realms/{realm}")
+
+	// when
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/auth/admin/realms/test", nil)
+	res, err := requester.Do(req)
+	failingReq, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:0/auth/admin/realms/test", nil)
+	_, failingErr := requester.Do(failingReq)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 404, res.StatusCode)
+	assert.Error(t, failingErr)
+	assert.Equal(t, float64(1), metricValue(t, counter))
+	assert.Equal(t, float64(1), metricValue(t, failures))
+	assert.Equal(t, float64(2), metricValue(t, duration.(prometheus.Metric)))
+}
+
+type failingAction struct{}
+
+func (i failingAction) Run(runner ActionRunner) (string, error) {
+	return "fail", errors.New("failed")
+}
+
+func TestMetrics_Test_RunAll(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+	actionRunner := &ClusterActionRunner{context: context.Background()}
+	desiredState := DesiredClusterState{
+		failingAction{},
+		PingAction{},
+	}
+	resource := resourceKind(realm)
+	failingDrift := drift.WithLabelValues(resource, "failingAction")
+	pingDrift := drift.WithLabelValues(resource, "PingAction")
+	failures := actions.WithLabelValues(resource, "failingAction", MetricsResultFailure)
+	pings := actions.WithLabelValues(resource, "PingAction", MetricsResultSuccess)
+
+	// when
+	actionRunner.cr = realm
+	err := actionRunner.RunAll(desiredState)
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, "KeycloakRealm", resource)
+	// pings are no drift and the actions after a failed action are not run
+	assert.Equal(t, float64(1), metricValue(t, failingDrift))
+	assert.Equal(t, float64(0), metricValue(t, pingDrift))
+	assert.Equal(t, float64(1), metricValue(t, failures))
+	assert.Equal(t, float64(0), metricValue(t, pings))
+}
+
+func TestMetrics_Test_ManagedClients(t *testing.T) {
+	// given
+	clients := NewManagedClients()
+	first := managedClients.WithLabelValues("metrics-first")
+	second := managedClients.WithLabelValues("metrics-second")
+
+	// when
+	clients.Set("ns/a", []string{"metrics-first", "metrics-second"})
+	clients.Set("ns/b", []string{"metrics-first"})
+	clients.Set("ns/a", []string{"metrics-second"})
+	clients.Set("ns/b", nil)
+
+	// then
+	assert.Equal(t, float64(0), metricValue(t, first))
+	assert.Equal(t, float64(1), metricValue(t, second))
+	assert.Equal(t, "ns/a", keycloakMetricsName(v1alpha1.Keycloak{ObjectMeta: v12.ObjectMeta{Namespace: "ns", Name: "a"}}))
+	assert.Equal(t, "a", keycloakMetricsName(v1alpha1.Keycloak{ObjectMeta: v12.ObjectMeta{Name: "a"}}))
+	clusterKeycloak := &v1alpha1.ClusterKeycloak{ObjectMeta: v12.ObjectMeta{Name: "a"}}
+	assert.Equal(t, "ClusterKeycloak/a", keycloakMetricsName(clusterKeycloak.ToKeycloak("ns")))
+}
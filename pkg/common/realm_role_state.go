@@ -0,0 +1,57 @@
+package common
+
+import (
+	"context"
+
+	kc "github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type RealmRoleState struct {
+	Role       *kc.RoleRepresentation
+	Composites []kc.RoleRepresentation
+	// Roles that can be used as composites
+	AvailableRoles
+	Context  context.Context
+	Realm    *kc.KeycloakRealm
+	Keycloak kc.Keycloak
+}
+
+func NewRealmRoleState(context context.Context, realm *kc.KeycloakRealm, keycloak kc.Keycloak) *RealmRoleState {
+	return &RealmRoleState{
+		Context:  context,
+		Realm:    realm,
+		Keycloak: keycloak,
+	}
+}
+
+func (i *RealmRoleState) Read(context context.Context, cr *kc.KeycloakRealmRole, realmClient KeycloakInterface, controllerClient client.Client) error {
+	realmName := i.Realm.Spec.Realm.Realm
+
+	role, err := realmClient.GetRealmRole(context, cr.Spec.Role.Name, realmName)
+	if err != nil {
+		return err
+	}
+	i.Role = role
+
+	if i.Role == nil {
+		cr.Spec.Role.ID = ""
+	} else {
+		cr.Spec.Role.ID = i.Role.ID
+
+		i.Composites, err = realmClient.ListRealmRoleComposites(context, realmName, i.Role.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.Role.Composites == nil {
+		return nil
+	}
+
+	clientIDs := make([]string, 0, len(cr.Spec.Role.Composites.Client))
+	for clientID := range cr.Spec.Role.Composites.Client {
+		clientIDs = append(clientIDs, clientID)
+	}
+	return i.readAvailableRoles(context, realmClient, realmName, clientIDs)
+}
@@ -22,8 +22,8 @@ func NewRealmState(context context.Context, keycloak kc.Keycloak) *RealmState {
 	}
 }
 
-func (i *RealmState) Read(cr *kc.KeycloakRealm, realmClient KeycloakInterface, controllerClient client.Client) error {
-	realm, err := realmClient.GetRealm(cr.Spec.Realm.Realm)
+func (i *RealmState) Read(context context.Context, cr *kc.KeycloakRealm, realmClient KeycloakInterface, controllerClient client.Client) error {
+	realm, err := realmClient.GetRealm(context, cr.Spec.Realm.Realm)
 	if err != nil {
 		i.Realm = nil
 		return err
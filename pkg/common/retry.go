@@ -0,0 +1,272 @@
+package common
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// RequestOptions configure the retries and the rate limit of the requests to the Keycloak API
+type RequestOptions struct {
+	// MaxRetries is the number of times an idempotent request is retried after a network error, 429 or 5xx
+	MaxRetries int
+	// RetryBaseDelay is the delay before the first retry, it doubles with every retry
+	RetryBaseDelay time.Duration
+	// RetryMaxDelay limits the delay between two attempts, including the delay asked for with Retry-After
+	RetryMaxDelay time.Duration
+	// RateLimit is the number of requests per second sent to a Keycloak, the requests are not limited when it is zero
+	RateLimit float64
+	// RateBurst is the number of requests sent to a Keycloak at once before the rate limit applies
+	RateBurst int
+	// MaxInFlight is the number of requests to a Keycloak running at the same time, the requests are not limited
+	// when it is zero
+	MaxInFlight int
+}
+
+// KeycloakRequestOptions are the options of the requests of all clients
+var KeycloakRequestOptions = RequestOptions{
+	MaxRetries:     3,
+	RetryBaseDelay: 500 * time.Millisecond,
+	RetryMaxDelay:  30 * time.Second,
+	RateLimit:      20,
+	RateBurst:      40,
+	MaxInFlight:    10,
+}
+
+// retryingRequester retries idempotent requests that failed with a network error or a status code that is
+// expected to go away, e.g. a 502 of an ingress while Keycloak is rolled out
+type retryingRequester struct {
+	requester Requester
+	options   RequestOptions
+	// sleep waits for the given duration unless the context is done
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryingRequester(requester Requester, options RequestOptions) Requester {
+	return &retryingRequester{requester: requester, options: options, sleep: sleepWithContext}
+}
+
+func (r *retryingRequester) Do(req *http.Request) (*http.Response, error) {
+	retryable := isIdempotent(req.Method) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	for attempt := 0; ; attempt++ {
+		res, err := r.requester.Do(req)
+		if !retryable || attempt >= r.options.MaxRetries || !shouldRetry(res, err) || req.Context().Err() != nil {
+			return res, err
+		}
+
+		delay := r.delay(attempt, res)
+		if res != nil {
+			// the connection is reused when the body was read completely
+			_, _ = io.Copy(ioutil.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+		trace.SpanFromContext(req.Context()).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("retry.attempt", attempt+1),
+			attribute.String("retry.delay", delay.String()),
+		))
+		log.V(1).Info("retrying request to keycloak", "method", req.Method, "url", req.URL.String(), "attempt", attempt+1, "delay", delay.String())
+
+		if err := r.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// delay returns the exponential backoff with jitter for the attempt, or the delay the server asked for
+func (r *retryingRequester) delay(attempt int, res *http.Response) time.Duration {
+	backoff := float64(r.options.RetryBaseDelay) * math.Pow(2, float64(attempt))
+	// up to a fifth of jitter keeps the clients of a resync from retrying at the same time
+	delay := time.Duration(backoff + backoff*0.2*rand.Float64()) // nolint:gosec
+	if res != nil {
+		if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			delay = retryAfter
+		}
+	}
+	if r.options.RetryMaxDelay > 0 && delay > r.options.RetryMaxDelay {
+		delay = r.options.RetryMaxDelay
+	}
+	return delay
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry checks whether the request failed for a reason that may go away
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented, http.StatusHTTPVersionNotSupported:
+		return false
+	}
+	return res.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses the Retry-After header, either in seconds or as HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if date.Before(now) {
+			return 0, true
+		}
+		return date.Sub(now), true
+	}
+	return 0, false
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitedRequester limits the rate of the requests to a Keycloak, the limiter is shared by all clients of the
+// Keycloak so a resync of all resources cannot overwhelm it
+type rateLimitedRequester struct {
+	requester Requester
+	limiter   *rate.Limiter
+}
+
+func newRateLimitedRequester(requester Requester, limiter *rate.Limiter) Requester {
+	if limiter == nil {
+		return requester
+	}
+	return &rateLimitedRequester{requester: requester, limiter: limiter}
+}
+
+func (r *rateLimitedRequester) Do(req *http.Request) (*http.Response, error) {
+	if err := r.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return r.requester.Do(req)
+}
+
+var (
+	rateLimitersMutex sync.Mutex
+	rateLimiters      = map[string]*rate.Limiter{}
+)
+
+// keycloakRateLimiter returns the rate limiter of the requests to a Keycloak, or nil if they are not limited
+func keycloakRateLimiter(keycloak string, options RequestOptions) *rate.Limiter {
+	if options.RateLimit <= 0 {
+		return nil
+	}
+
+	rateLimitersMutex.Lock()
+	defer rateLimitersMutex.Unlock()
+	limiter, ok := rateLimiters[keycloak]
+	if !ok {
+		burst := options.RateBurst
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(options.RateLimit), burst)
+		rateLimiters[keycloak] = limiter
+	}
+	return limiter
+}
+
+// inFlightLimitedRequester limits the number of requests to a Keycloak running at the same time, the slots are shared
+// by all clients of the Keycloak so concurrent reconciliations cannot overload it. A request holds its slot until the
+// body of the response is closed.
+type inFlightLimitedRequester struct {
+	requester Requester
+	slots     chan struct{}
+}
+
+func newInFlightLimitedRequester(requester Requester, slots chan struct{}) Requester {
+	if slots == nil {
+		return requester
+	}
+	return &inFlightLimitedRequester{requester: requester, slots: slots}
+}
+
+func (r *inFlightLimitedRequester) Do(req *http.Request) (*http.Response, error) {
+	select {
+	case r.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	release := &sync.Once{}
+	res, err := r.requester.Do(req)
+	if err != nil {
+		release.Do(r.release)
+		return res, err
+	}
+	res.Body = &releasingBody{ReadCloser: res.Body, release: func() { release.Do(r.release) }}
+	return res, nil
+}
+
+func (r *inFlightLimitedRequester) release() {
+	<-r.slots
+}
+
+// releasingBody releases the slot of the request when it is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+var (
+	inFlightSlotsMutex sync.Mutex
+	inFlightSlots      = map[string]chan struct{}{}
+)
+
+// keycloakInFlightSlots returns the slots of the requests to a Keycloak, or nil if they are not limited
+func keycloakInFlightSlots(keycloak string, options RequestOptions) chan struct{} {
+	if options.MaxInFlight <= 0 {
+		return nil
+	}
+
+	inFlightSlotsMutex.Lock()
+	defer inFlightSlotsMutex.Unlock()
+	slots, ok := inFlightSlots[keycloak]
+	if !ok {
+		slots = make(chan struct{}, options.MaxInFlight)
+		inFlightSlots[keycloak] = slots
+	}
+	return slots
+}
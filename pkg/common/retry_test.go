@@ -0,0 +1,216 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRetryingRequester returns a retrying requester recording the delays instead of waiting
+func testRetryingRequester(options RequestOptions) (*retryingRequester, *[]time.Duration) {
+	delays := &[]time.Duration{}
+	return &retryingRequester{
+		requester: &http.Client{},
+		options:   options,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			*delays = append(*delays, d)
+			return nil
+		},
+	}, delays
+}
+
+func TestRetry_Test_Retries_Idempotent_Requests(t *testing.T) {
+	// given
+	var bodies []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(502)
+			return
+		}
+		w.WriteHeader(204)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	requester, delays := testRetryingRequester(RequestOptions{MaxRetries: 3, RetryBaseDelay: time.Second, RetryMaxDelay: time.Minute})
+
+	// when
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/auth/admin/realms/test", bytes.NewBufferString(`{"realm":"test"}`))
+	res, err := requester.Do(req)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 204, res.StatusCode)
+	// the body is sent again with every attempt
+	assert.Equal(t, []string{`{"realm":"test"}`, `{"realm":"test"}`, `{"realm":"test"}`}, bodies)
+	// the delay doubles with every retry, with up to a fifth of jitter
+	assert.Len(t, *delays, 2)
+	assert.True(t, (*delays)[0] >= time.Second && (*delays)[0] <= 1200*time.Millisecond)
+	assert.True(t, (*delays)[1] >= 2*time.Second && (*delays)[1] <= 2400*time.Millisecond)
+}
+
+func TestRetry_Test_Does_Not_Retry(t *testing.T) {
+	// given
+	attempts := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		if req.URL.Path == "/missing" {
+			w.WriteHeader(404)
+			return
+		}
+		w.WriteHeader(503)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	requester, _ := testRetryingRequester(RequestOptions{MaxRetries: 2})
+
+	// when
+	post, _ := http.NewRequest(http.MethodPost, server.URL+"/auth/admin/realms", bytes.NewBufferString("{}"))
+	postRes, postErr := requester.Do(post)
+	postAttempts := attempts
+	attempts = 0
+	missing, _ := http.NewRequest(http.MethodGet, server.URL+"/missing", nil)
+	missingRes, missingErr := requester.Do(missing)
+	missingAttempts := attempts
+	attempts = 0
+	get, _ := http.NewRequest(http.MethodGet, server.URL+"/unavailable", nil)
+	getRes, getErr := requester.Do(get)
+
+	// then
+	// requests that aren't idempotent are sent once
+	assert.NoError(t, postErr)
+	assert.Equal(t, 503, postRes.StatusCode)
+	assert.Equal(t, 1, postAttempts)
+	// client errors don't go away
+	assert.NoError(t, missingErr)
+	assert.Equal(t, 404, missingRes.StatusCode)
+	assert.Equal(t, 1, missingAttempts)
+	// the last response is returned when the retries are used up
+	assert.NoError(t, getErr)
+	assert.Equal(t, 503, getRes.StatusCode)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetry_Test_Retry_After(t *testing.T) {
+	// given
+	attempts := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(429)
+		case 2:
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(503)
+		default:
+			w.WriteHeader(200)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	requester, delays := testRetryingRequester(RequestOptions{MaxRetries: 3, RetryBaseDelay: time.Second, RetryMaxDelay: time.Minute})
+
+	// when
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/auth/admin/realms/test", nil)
+	res, err := requester.Do(req)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	// the delay asked for is honored up to the maximum delay
+	assert.Equal(t, []time.Duration{3 * time.Second, time.Minute}, *delays)
+}
+
+func TestRetry_Test_ParseRetryAfter(t *testing.T) {
+	// given
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// when
+	seconds, secondsOk := parseRetryAfter("7", now)
+	date, dateOk := parseRetryAfter("Mon, 02 Jan 2023 03:04:35 GMT", now)
+	past, pastOk := parseRetryAfter("Mon, 02 Jan 2023 03:00:00 GMT", now)
+	_, invalidOk := parseRetryAfter("soon", now)
+
+	// then
+	assert.True(t, secondsOk)
+	assert.Equal(t, 7*time.Second, seconds)
+	assert.True(t, dateOk)
+	assert.Equal(t, 30*time.Second, date)
+	assert.True(t, pastOk)
+	assert.Equal(t, time.Duration(0), past)
+	assert.False(t, invalidOk)
+}
+
+func TestRetry_Test_RateLimit(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(200)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	options := RequestOptions{RateLimit: 0.001, RateBurst: 1}
+	limiter := keycloakRateLimiter("ratelimit/keycloak", options)
+	requester := newRateLimitedRequester(&http.Client{}, limiter)
+
+	// when
+	first, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	firstRes, firstErr := requester.Do(first)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	second, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, secondErr := requester.Do(second)
+
+	// then
+	assert.NoError(t, firstErr)
+	assert.Equal(t, 200, firstRes.StatusCode)
+	// the second request would have to wait longer than the reconciliation lasts
+	assert.Error(t, secondErr)
+	// the clients of a keycloak share the limiter
+	assert.Same(t, limiter, keycloakRateLimiter("ratelimit/keycloak", options))
+	assert.NotSame(t, limiter, keycloakRateLimiter("ratelimit/other", options))
+	assert.Nil(t, keycloakRateLimiter("ratelimit/unlimited", RequestOptions{}))
+}
+
+func TestRetry_Test_MaxInFlight(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(200)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	options := RequestOptions{MaxInFlight: 1}
+	slots := keycloakInFlightSlots("inflight/keycloak", options)
+	requester := newInFlightLimitedRequester(&http.Client{}, slots)
+
+	// when
+	first, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	firstRes, firstErr := requester.Do(first)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	blocked, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, blockedErr := requester.Do(blocked)
+	_ = firstRes.Body.Close()
+	third, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	thirdRes, thirdErr := requester.Do(third)
+
+	// then
+	assert.NoError(t, firstErr)
+	// the second request waits for the body of the first one to be closed
+	assert.ErrorIs(t, blockedErr, context.DeadlineExceeded)
+	assert.NoError(t, thirdErr)
+	assert.NoError(t, thirdRes.Body.Close())
+	// closing the body again doesn't release another slot
+	assert.NoError(t, firstRes.Body.Close())
+	assert.Len(t, slots, 0)
+	// the clients of a keycloak share the slots
+	assert.Equal(t, slots, keycloakInFlightSlots("inflight/keycloak", options))
+	assert.Nil(t, keycloakInFlightSlots("inflight/unlimited", RequestOptions{}))
+}
@@ -0,0 +1,94 @@
+package common
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// ServerInfo is the version and the capabilities of a Keycloak
+type ServerInfo struct {
+	Version      string
+	Capabilities v1alpha1.KeycloakServerInfo
+}
+
+// serverInfoRepresentation is the part of the server info of the Keycloak admin API used by the controller
+type serverInfoRepresentation struct {
+	SystemInfo struct {
+		Version string `json:"version"`
+	} `json:"systemInfo"`
+	ProfileInfo struct {
+		DisabledFeatures []string `json:"disabledFeatures"`
+	} `json:"profileInfo"`
+	// Features are reported since Keycloak 22, older versions only report the disabled ones in the profile info
+	Features []struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+	} `json:"features"`
+	Providers map[string]struct {
+		Providers map[string]json.RawMessage `json:"providers"`
+	} `json:"providers"`
+	ProtocolMapperTypes map[string][]struct {
+		ID string `json:"id"`
+	} `json:"protocolMapperTypes"`
+}
+
+// parseServerInfo reads the version and the capabilities from the server info, features are named like the options
+// of the Keycloak CLI, e.g. token-exchange
+func parseServerInfo(body []byte) (*ServerInfo, error) {
+	representation := &serverInfoRepresentation{}
+	err := json.Unmarshal(body, representation)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing server info")
+	}
+
+	info := &ServerInfo{Version: representation.SystemInfo.Version}
+	if len(representation.Features) > 0 {
+		for _, feature := range representation.Features {
+			if feature.Enabled {
+				info.Capabilities.EnabledFeatures = append(info.Capabilities.EnabledFeatures, featureName(feature.Name))
+			} else {
+				info.Capabilities.DisabledFeatures = append(info.Capabilities.DisabledFeatures, featureName(feature.Name))
+			}
+		}
+	} else {
+		for _, feature := range representation.ProfileInfo.DisabledFeatures {
+			info.Capabilities.DisabledFeatures = append(info.Capabilities.DisabledFeatures, featureName(feature))
+		}
+	}
+	sort.Strings(info.Capabilities.EnabledFeatures)
+	sort.Strings(info.Capabilities.DisabledFeatures)
+
+	if len(representation.ProtocolMapperTypes) > 0 {
+		info.Capabilities.ProtocolMapperTypes = map[string][]string{}
+	}
+	for protocol, mapperTypes := range representation.ProtocolMapperTypes {
+		ids := make([]string, 0, len(mapperTypes))
+		for _, mapperType := range mapperTypes {
+			ids = append(ids, mapperType.ID)
+		}
+		sort.Strings(ids)
+		info.Capabilities.ProtocolMapperTypes[protocol] = ids
+	}
+
+	info.Capabilities.Authenticators = providerIDs(representation, "authenticator")
+	info.Capabilities.ClientAuthenticators = providerIDs(representation, "client-authenticator")
+	return info, nil
+}
+
+func providerIDs(representation *serverInfoRepresentation, spi string) []string {
+	var ids []string
+	for id := range representation.Providers[spi].Providers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// featureName converts the name of a feature as reported by older Keycloaks, e.g. TOKEN_EXCHANGE, to token-exchange
+func featureName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
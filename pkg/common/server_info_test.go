@@ -0,0 +1,40 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerInfo_parseServerInfo_Features(t *testing.T) {
+	// given
+	// keycloak 22 and newer report all features, the disabled ones are also part of the profile info
+	body := []byte(`{
+		"systemInfo": {"version": "24.0.5"},
+		"profileInfo": {"disabledFeatures": ["TOKEN_EXCHANGE"]},
+		"features": [
+			{"name": "AUTHORIZATION", "type": "DEFAULT", "enabled": true},
+			{"name": "TOKEN_EXCHANGE", "type": "PREVIEW", "enabled": false},
+			{"name": "ACCOUNT3", "type": "DEFAULT", "enabled": true}
+		]
+	}`)
+
+	// when
+	info, err := parseServerInfo(body)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "24.0.5", info.Version)
+	assert.Equal(t, []string{"account3", "authorization"}, info.Capabilities.EnabledFeatures)
+	assert.Equal(t, []string{"token-exchange"}, info.Capabilities.DisabledFeatures)
+	assert.Nil(t, info.Capabilities.ProtocolMapperTypes)
+	assert.Nil(t, info.Capabilities.Authenticators)
+}
+
+func TestServerInfo_parseServerInfo_Invalid(t *testing.T) {
+	// when
+	_, err := parseServerInfo([]byte("<html>"))
+
+	// then
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/christianwoehrle/keycloakclient-controller/version"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const tracerName = "github.com/christianwoehrle/keycloakclient-controller"
+
+// Attributes of the spans
+const (
+	NamespaceAttribute = attribute.Key("keycloak.resource.namespace")
+	NameAttribute      = attribute.Key("keycloak.resource.name")
+	KindAttribute      = attribute.Key("keycloak.resource.kind")
+	RealmAttribute     = attribute.Key("keycloak.realm")
+	KeycloakAttribute  = attribute.Key("keycloak.instance")
+	ActionAttribute    = attribute.Key("keycloak.action")
+)
+
+// TracingOptions configure the export of the traces
+type TracingOptions struct {
+	// Endpoint of the OTLP gRPC receiver the traces are exported to, tracing is disabled without endpoint
+	Endpoint string
+	// Insecure disables TLS for the connection to the endpoint
+	Insecure bool
+	// SampleRatio is the ratio of the traces that are sampled, unless the parent span is sampled
+	SampleRatio float64
+	// ServiceName is the name of the service the traces are reported for
+	ServiceName string
+}
+
+// SetupTracing registers the tracer provider exporting the traces to the OTLP endpoint of the options. The returned
+// function flushes the remaining spans and stops the export.
+func SetupTracing(ctx context.Context, options TracingOptions) (func(context.Context) error, error) {
+	if options.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporterOptions := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(options.Endpoint)}
+	if options.Insecure {
+		exporterOptions = append(exporterOptions, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOptions...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot create the trace exporter for %v", options.Endpoint)
+	}
+
+	return SetupTracerProvider(exporter, options).Shutdown, nil
+}
+
+// SetupTracerProvider registers a tracer provider exporting the traces with the given exporter, e.g. an in-memory
+// exporter in tests
+func SetupTracerProvider(exporter sdktrace.SpanExporter, options TracingOptions) *sdktrace.TracerProvider {
+	serviceName := options.ServiceName
+	if serviceName == "" {
+		serviceName = "keycloakclient-controller"
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(version.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider
+}
+
+// StartSpan starts a span with the tracer of the controller, without a registered tracer provider the span does nothing
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records the error, if any, and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ResourceAttributes are the attributes identifying a resource
+func ResourceAttributes(obj client.Object) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		NamespaceAttribute.String(obj.GetNamespace()),
+		NameAttribute.String(obj.GetName()),
+		KindAttribute.String(resourceKind(obj)),
+	}
+}
+
+// KeycloakInstanceAttribute is the attribute identifying the Keycloak or ClusterKeycloak a resource is reconciled in
+func KeycloakInstanceAttribute(kc v1alpha1.Keycloak) attribute.KeyValue {
+	return KeycloakAttribute.String(keycloakMetricsName(kc))
+}
+
+// tracingRequester records a span for each request to the Keycloak API of a Keycloak
+type tracingRequester struct {
+	requester Requester
+	keycloak  string
+}
+
+func newTracingRequester(requester Requester, keycloak string) Requester {
+	return &tracingRequester{requester: requester, keycloak: keycloak}
+}
+
+func (i *tracingRequester) Do(req *http.Request) (*http.Response, error) {
+	path := pathTemplate(req.URL.Path)
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), fmt.Sprintf("%v %v", req.Method, path),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.HTTPURLKey.String(req.URL.String()),
+			semconv.HTTPRouteKey.String(path),
+			KeycloakAttribute.String(i.keycloak),
+		))
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	res, err := i.requester.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return res, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+	// not found is an expected answer when the controller looks up resources, only server errors fail the span
+	if res.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, res.Status)
+	}
+	return res, nil
+}
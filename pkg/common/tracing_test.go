@@ -0,0 +1,97 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func spanByName(spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	return tracetest.SpanStub{}
+}
+
+func TestTracing_Test_RunAll(t *testing.T) {
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+	provider := SetupTracerProvider(exporter, TracingOptions{SampleRatio: 1})
+	defer provider.Shutdown(context.Background())
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/auth/", req.URL.Path)
+		// the trace is propagated to keycloak
+		assert.NotEmpty(t, req.Header.Get("traceparent"))
+		w.WriteHeader(200)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	keycloakClient := &Client{
+		requester: newTracingRequester(&http.Client{}, "keycloak/keycloak"),
+		URL:       server.URL,
+	}
+	cr := &v1alpha1.KeycloakClient{ObjectMeta: v12.ObjectMeta{Namespace: "my-app", Name: "orders"}}
+	ctx, span := StartSpan(context.Background(), "Reconcile KeycloakClient")
+	runner := NewClusterAndKeycloakActionRunner(ctx, nil, nil, cr, keycloakClient)
+
+	// when
+	err := runner.RunAll(DesiredClusterState{PingAction{}, failingAction{}})
+	EndSpan(span, err)
+	assert.NoError(t, provider.ForceFlush(context.Background()))
+
+	// then
+	assert.Error(t, err)
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 4)
+
+	reconcile := spanByName(spans, "Reconcile KeycloakClient")
+	assert.Equal(t, codes.Error, reconcile.Status.Code)
+
+	ping := spanByName(spans, "PingAction")
+	assert.Equal(t, reconcile.SpanContext.SpanID(), ping.Parent.SpanID())
+	assert.Equal(t, "my-app", spanAttribute(ping, NamespaceAttribute).AsString())
+	assert.Equal(t, "orders", spanAttribute(ping, NameAttribute).AsString())
+	assert.Equal(t, "KeycloakClient", spanAttribute(ping, KindAttribute).AsString())
+	assert.Equal(t, codes.Unset, ping.Status.Code)
+
+	// the requests of an action are children of its span
+	request := spanByName(spans, "GET /auth")
+	assert.Equal(t, ping.SpanContext.SpanID(), request.Parent.SpanID())
+	assert.Equal(t, "keycloak/keycloak", spanAttribute(request, KeycloakAttribute).AsString())
+	assert.Equal(t, int64(200), spanAttribute(request, "http.status_code").AsInt64())
+
+	failing := spanByName(spans, "failingAction")
+	assert.Equal(t, reconcile.SpanContext.SpanID(), failing.Parent.SpanID())
+	assert.Equal(t, codes.Error, failing.Status.Code)
+	assert.Equal(t, "failed", failing.Status.Description)
+}
+
+func TestTracing_Test_SetupTracing_Without_Endpoint(t *testing.T) {
+	// when
+	shutdown, err := SetupTracing(context.Background(), TracingOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
 	"github.com/christianwoehrle/keycloakclient-controller/pkg/model"
@@ -17,9 +18,14 @@ type UserState struct {
 	AvailableClientRoles map[string][]*v1alpha1.KeycloakUserRole
 	AvailableRealmRoles  []*v1alpha1.KeycloakUserRole
 	Clients              []*v1alpha1.KeycloakAPIClient
+	Groups               []v1alpha1.KeycloakAPIGroup
+	FederatedIdentities  []v1alpha1.FederatedIdentity
 	Secret               *v1.Secret
-	Keycloak             v1alpha1.Keycloak
-	Context              context.Context
+	// Initial password of a KeycloakUser taken from its password secret
+	InitialPassword string
+	Realm           *v1alpha1.KeycloakRealm
+	Keycloak        v1alpha1.Keycloak
+	Context         context.Context
 }
 
 func NewUserState(keycloak v1alpha1.Keycloak) *UserState {
@@ -30,7 +36,7 @@ func NewUserState(keycloak v1alpha1.Keycloak) *UserState {
 	}
 }
 
-func (i *UserState) ReadWithExistingAPIUser(keycloakClient KeycloakInterface, userClient client.Client, user *v1alpha1.KeycloakAPIUser, realm v1alpha1.KeycloakRealm) error {
+func (i *UserState) ReadWithExistingAPIUser(context context.Context, keycloakClient KeycloakInterface, userClient client.Client, user *v1alpha1.KeycloakAPIUser, realm v1alpha1.KeycloakRealm) error {
 	// Don't continue if the user could not be found
 	if user == nil {
 		return nil
@@ -38,12 +44,12 @@ func (i *UserState) ReadWithExistingAPIUser(keycloakClient KeycloakInterface, us
 
 	i.User = user
 
-	var err = i.readRealmRoles(keycloakClient, realm.Spec.Realm.Realm)
+	var err = i.readRealmRoles(context, keycloakClient, realm.Spec.Realm.Realm)
 	if err != nil {
 		return err
 	}
 
-	err = i.readClientRoles(keycloakClient, realm.Spec.Realm.Realm)
+	err = i.readClientRoles(context, keycloakClient, realm.Spec.Realm.Realm)
 	if err != nil {
 		return err
 	}
@@ -51,16 +57,109 @@ func (i *UserState) ReadWithExistingAPIUser(keycloakClient KeycloakInterface, us
 	return i.readSecretState(userClient, &realm)
 }
 
-func (i *UserState) readRealmRoles(client KeycloakInterface, realm string) error {
+// Read reads the state of the user of a KeycloakUser, the user is matched by username
+func (i *UserState) Read(context context.Context, cr *v1alpha1.KeycloakUser, realm *v1alpha1.KeycloakRealm, keycloakClient KeycloakInterface, userClient client.Client) error {
+	i.Context = context
+	i.Realm = realm
+	realmName := realm.Spec.Realm.Realm
+
+	err := i.readInitialPassword(userClient, cr)
+	if err != nil {
+		return err
+	}
+
+	err = i.readUserSecretState(userClient, cr)
+	if err != nil {
+		return err
+	}
+
+	user, err := keycloakClient.GetUserByUsername(context, cr.Spec.User.UserName, realmName)
+	if err != nil {
+		return err
+	}
+
+	// Don't continue if the user could not be found
+	if user == nil {
+		cr.Spec.User.ID = ""
+		return nil
+	}
+
+	i.User = user
+	cr.Spec.User.ID = user.ID
+
+	err = i.readRealmRoles(context, keycloakClient, realmName)
+	if err != nil {
+		return err
+	}
+
+	err = i.readClientRoles(context, keycloakClient, realmName)
+	if err != nil {
+		return err
+	}
+
+	i.Groups, err = keycloakClient.ListUserGroups(context, user.ID, realmName)
+	if err != nil {
+		return err
+	}
+
+	i.FederatedIdentities, err = keycloakClient.GetUserFederatedIdentities(context, user.ID, realmName)
+	return err
+}
+
+func (i *UserState) readInitialPassword(userClient client.Client, cr *v1alpha1.KeycloakUser) error {
+	selector := cr.Spec.InitialPasswordSecret
+	if selector == nil {
+		return nil
+	}
+
+	secret := &v1.Secret{}
+	err := userClient.Get(i.Context, client.ObjectKey{Name: selector.Name, Namespace: cr.Namespace}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) && selector.Optional != nil && *selector.Optional {
+			return nil
+		}
+		return err
+	}
+
+	password, ok := secret.Data[selector.Key]
+	if !ok {
+		if selector.Optional != nil && *selector.Optional {
+			return nil
+		}
+		return fmt.Errorf("key %v not found in initial password secret %v/%v", selector.Key, cr.Namespace, selector.Name)
+	}
+
+	i.InitialPassword = string(password)
+	return nil
+}
+
+func (i *UserState) readUserSecretState(userClient client.Client, cr *v1alpha1.KeycloakUser) error {
+	key := model.UserCredentialSecretSelector(cr, i.Realm, &i.Keycloak)
+	secret := &v1.Secret{}
+
+	// Try to find the user credential secret
+	err := userClient.Get(i.Context, key, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	i.Secret = secret
+	return nil
+}
+
+func (i *UserState) readRealmRoles(context context.Context, client KeycloakInterface, realm string) error {
 	// Get all the realm roles of this user
-	roles, err := client.ListUserRealmRoles(realm, i.User.ID)
+	roles, err := client.ListUserRealmRoles(context, realm, i.User.ID)
 	if err != nil {
 		return err
 	}
 	i.RealmRoles = roles
 
 	// Get the roles that are still available to this user
-	availableRoles, err := client.ListAvailableUserRealmRoles(realm, i.User.ID)
+	availableRoles, err := client.ListAvailableUserRealmRoles(context, realm, i.User.ID)
 	if err != nil {
 		return err
 	}
@@ -69,8 +168,8 @@ func (i *UserState) readRealmRoles(client KeycloakInterface, realm string) error
 	return nil
 }
 
-func (i *UserState) readClientRoles(client KeycloakInterface, realm string) error {
-	clients, err := client.ListClients(realm)
+func (i *UserState) readClientRoles(context context.Context, client KeycloakInterface, realm string) error {
+	clients, err := client.ListClients(context, realm)
 	if err != nil {
 		return err
 	}
@@ -78,14 +177,14 @@ func (i *UserState) readClientRoles(client KeycloakInterface, realm string) erro
 
 	for _, c := range clients {
 		// Get all client roles of this user
-		roles, err := client.ListUserClientRoles(realm, c.ID, i.User.ID)
+		roles, err := client.ListUserClientRoles(context, realm, c.ID, i.User.ID)
 		if err != nil {
 			return err
 		}
 		i.ClientRoles[c.ClientID] = roles
 
 		// Get the roles that are still available to this user
-		availableRoles, err := client.ListAvailableUserClientRoles(realm, c.ID, i.User.ID)
+		availableRoles, err := client.ListAvailableUserClientRoles(context, realm, c.ID, i.User.ID)
 		if err != nil {
 			return err
 		}
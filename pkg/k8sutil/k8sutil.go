@@ -37,7 +37,13 @@ var ErrRunLocal = fmt.Errorf("operator run mode forced to local")
 var ErrWatchNamespaceEnvVar = fmt.Errorf("watch namespace env var must be set")
 
 // GetOperatorNamespace returns the namespace the operator should be running in.
+// The OPERATOR_NAMESPACE env variable takes precedence, which allows to run the operator locally.
 func GetOperatorNamespace() (string, error) {
+	var operatorNamespaceEnvVar = "OPERATOR_NAMESPACE"
+
+	if ns, found := os.LookupEnv(operatorNamespaceEnvVar); found && ns != "" {
+		return ns, nil
+	}
 	if isRunModeLocal() {
 		return "", ErrRunLocal
 	}
@@ -0,0 +1,324 @@
+package model
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ClientAuthenticatorJWT  = "client-jwt"
+	ClientAuthenticatorX509 = "client-x509"
+
+	// attributes of clients authenticating with a signed JWT or an X.509 certificate in Keycloak
+	JWTCredentialCertificateAttribute = "jwt.credential.certificate"
+	UseJWKSURLAttribute               = "use.jwks.url"
+	UseJWKSStringAttribute            = "use.jwks.string"
+	JWKSStringAttribute               = "jwks.string"
+	X509SubjectDNAttribute            = "x509.subjectdn"
+	X509AllowRegexAttribute           = "x509.allow.regex.pattern.comparison"
+	JWTSigningAlgorithmAttribute      = "token.endpoint.auth.signing.alg"
+
+	// keys of the generated Secret in addition to tls.crt and tls.key
+	ClientAuthenticationKeyIDKey               = "kid"
+	ClientAuthenticationPreviousCertificateKey = "previous.crt"
+
+	clientAuthenticationKeySize             = 2048
+	clientAuthenticationCertificateValidity = 365 * 24 * time.Hour
+	defaultClientAuthenticationAlgorithm    = "RS256"
+)
+
+// ApplyClientAuthenticationKey returns a copy of the client with the certificate of the Secret registered, depending on
+// the client authenticator. The client authenticator defaults to client-jwt. The previous certificate of a rotated
+// key is registered as well while it overlaps with the current one, as long as the keys are registered as JWKS.
+func ApplyClientAuthenticationKey(client *v1alpha1.KeycloakAPIClient, key *v1alpha1.ClientAuthenticationKey, secret *v1.Secret, now time.Time) (*v1alpha1.KeycloakAPIClient, error) {
+	applied := client.DeepCopy()
+	if key == nil {
+		return applied, nil
+	}
+
+	certificate, err := parseCertificate(secret.Data[v1.TLSCertKey])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid certificate in client authentication key secret %v", key.SecretName)
+	}
+	certificates := []*x509.Certificate{certificate}
+	if previous := previousClientAuthenticationCertificate(key, secret, certificate, now); previous != nil {
+		certificates = append(certificates, previous)
+	}
+
+	if applied.ClientAuthenticatorType == "" {
+		applied.ClientAuthenticatorType = ClientAuthenticatorJWT
+	}
+	if applied.Attributes == nil {
+		applied.Attributes = map[string]string{}
+	}
+
+	switch applied.ClientAuthenticatorType {
+	case ClientAuthenticatorJWT:
+		applied.Attributes[UseJWKSURLAttribute] = "false"
+		if key.Algorithm != "" {
+			applied.Attributes[JWTSigningAlgorithmAttribute] = key.Algorithm
+		}
+		if key.UseJWKS {
+			jwks, err := JWKS(certificates...)
+			if err != nil {
+				return nil, err
+			}
+			applied.Attributes[UseJWKSStringAttribute] = "true"
+			applied.Attributes[JWKSStringAttribute] = jwks
+		} else {
+			applied.Attributes[UseJWKSStringAttribute] = "false"
+			applied.Attributes[JWTCredentialCertificateAttribute] = base64.StdEncoding.EncodeToString(certificate.Raw)
+		}
+	case ClientAuthenticatorX509:
+		applied.Attributes[X509SubjectDNAttribute] = certificate.Subject.String()
+		applied.Attributes[X509AllowRegexAttribute] = "false"
+	default:
+		return nil, errors.Errorf("client authenticator %v doesn't use a client authentication key", applied.ClientAuthenticatorType)
+	}
+	return applied, nil
+}
+
+func parseCertificate(certificatePEM []byte) (*x509.Certificate, error) {
+	for block, rest := pem.Decode(certificatePEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+	return nil, errors.New("no certificate found")
+}
+
+type jsonWebKey struct {
+	Kty string   `json:"kty"`
+	Kid string   `json:"kid"`
+	Use string   `json:"use"`
+	Alg string   `json:"alg"`
+	N   string   `json:"n,omitempty"`
+	E   string   `json:"e,omitempty"`
+	Crv string   `json:"crv,omitempty"`
+	X   string   `json:"x,omitempty"`
+	Y   string   `json:"y,omitempty"`
+	X5c []string `json:"x5c,omitempty"`
+}
+
+// JWKS returns the JSON Web Key Set with the public keys of the certificates. The key IDs are the RFC 7638
+// thumbprints of the keys.
+func JWKS(certificates ...*x509.Certificate) (string, error) {
+	keys := []jsonWebKey{}
+	for _, certificate := range certificates {
+		key, err := toJSONWebKey(certificate)
+		if err != nil {
+			return "", err
+		}
+		keys = append(keys, key)
+	}
+
+	data, err := json.Marshal(map[string][]jsonWebKey{"keys": keys})
+	if err != nil {
+		return "", errors.Wrap(err, "cannot encode jwks")
+	}
+	return string(data), nil
+}
+
+func toJSONWebKey(certificate *x509.Certificate) (jsonWebKey, error) {
+	key := jsonWebKey{
+		Use: "sig",
+		X5c: []string{base64.StdEncoding.EncodeToString(certificate.Raw)},
+	}
+
+	var thumbprint string
+	switch publicKey := certificate.PublicKey.(type) {
+	case *rsa.PublicKey:
+		key.Kty = "RSA"
+		key.Alg = "RS256"
+		key.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		key.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		thumbprint = `{"e":"` + key.E + `","kty":"RSA","n":"` + key.N + `"}`
+	case *ecdsa.PublicKey:
+		params := publicKey.Curve.Params()
+		size := (params.BitSize + 7) / 8
+		key.Kty = "EC"
+		key.Crv = params.Name
+		key.Alg = map[string]string{"P-256": "ES256", "P-384": "ES384", "P-521": "ES512"}[params.Name]
+		key.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size)))
+		key.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size)))
+		thumbprint = `{"crv":"` + key.Crv + `","kty":"EC","x":"` + key.X + `","y":"` + key.Y + `"}`
+	default:
+		return key, errors.Errorf("unsupported public key of certificate %v", certificate.Subject)
+	}
+
+	sum := sha256.Sum256([]byte(thumbprint))
+	key.Kid = base64.RawURLEncoding.EncodeToString(sum[:])
+	return key, nil
+}
+
+// previousClientAuthenticationCertificate returns the certificate of the previous key of a rotated Secret while the
+// overlap since the rotation lasts, the current certificate was issued at the rotation
+func previousClientAuthenticationCertificate(key *v1alpha1.ClientAuthenticationKey, secret *v1.Secret, current *x509.Certificate, now time.Time) *x509.Certificate {
+	previousPEM, ok := secret.Data[ClientAuthenticationPreviousCertificateKey]
+	if !ok || key.RotationOverlap == nil || !now.Before(current.NotBefore.Add(key.RotationOverlap.Duration)) {
+		return nil
+	}
+	previous, err := parseCertificate(previousPEM)
+	if err != nil {
+		return nil
+	}
+	return previous
+}
+
+// ClientAuthenticationKeyRotationDue checks whether the generated key of the Secret needs to be replaced, because its
+// rotation period passed or its certificate expires
+func ClientAuthenticationKeyRotationDue(key *v1alpha1.ClientAuthenticationKey, secret *v1.Secret, now time.Time) bool {
+	certificate, err := parseCertificate(secret.Data[v1.TLSCertKey])
+	if err != nil {
+		return true
+	}
+	if key.RotationPeriod != nil && !now.Before(certificate.NotBefore.Add(key.RotationPeriod.Duration)) {
+		return true
+	}
+	return !now.Before(certificate.NotAfter)
+}
+
+// ClientAuthenticationKeyRequeueAfter returns the time until the generated key of the Secret needs to be rotated or
+// the overlap of the previous key ends, whichever comes first, or 0 when nothing is due
+func ClientAuthenticationKeyRequeueAfter(key *v1alpha1.ClientAuthenticationKey, secret *v1.Secret, now time.Time) time.Duration {
+	certificate, err := parseCertificate(secret.Data[v1.TLSCertKey])
+	if err != nil || !key.Generate {
+		return 0
+	}
+
+	events := []time.Time{certificate.NotAfter}
+	if key.RotationPeriod != nil {
+		events = append(events, certificate.NotBefore.Add(key.RotationPeriod.Duration))
+	}
+	if previousClientAuthenticationCertificate(key, secret, certificate, now) != nil {
+		events = append(events, certificate.NotBefore.Add(key.RotationOverlap.Duration))
+	}
+
+	var requeueAfter time.Duration
+	for _, event := range events {
+		if wait := event.Sub(now); wait > 0 && (requeueAfter == 0 || wait < requeueAfter) {
+			requeueAfter = wait
+		}
+	}
+	return requeueAfter
+}
+
+// GenerateClientAuthenticationSecret returns a kubernetes.io/tls Secret with a new private key and a self-signed
+// certificate for the client
+func GenerateClientAuthenticationSecret(cr *v1alpha1.KeycloakClient, now time.Time) (*v1.Secret, error) {
+	secret := &v1.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      cr.Spec.ClientAuthenticationKey.SecretName,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app": ApplicationName,
+			},
+		},
+		Type: v1.SecretTypeTLS,
+	}
+	err := generateClientAuthenticationKey(cr, secret, now)
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// RotateClientAuthenticationSecret returns a copy of the Secret with a new private key and certificate, the
+// certificate of the replaced key is kept as previous certificate
+func RotateClientAuthenticationSecret(cr *v1alpha1.KeycloakClient, currentState *v1.Secret, now time.Time) (*v1.Secret, error) {
+	rotated := currentState.DeepCopy()
+	previous := rotated.Data[v1.TLSCertKey]
+	err := generateClientAuthenticationKey(cr, rotated, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(previous) > 0 {
+		rotated.Data[ClientAuthenticationPreviousCertificateKey] = previous
+	}
+	return rotated, nil
+}
+
+func generateClientAuthenticationKey(cr *v1alpha1.KeycloakClient, secret *v1.Secret, now time.Time) error {
+	key := cr.Spec.ClientAuthenticationKey
+	algorithm := key.Algorithm
+	if algorithm == "" {
+		algorithm = defaultClientAuthenticationAlgorithm
+	}
+
+	var privateKey crypto.Signer
+	var err error
+	switch algorithm {
+	case "ES256":
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ES384":
+		privateKey, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "RS256":
+		privateKey, err = rsa.GenerateKey(rand.Reader, clientAuthenticationKeySize)
+	default:
+		return errors.Errorf("unsupported client authentication key algorithm %v", algorithm)
+	}
+	if err != nil {
+		return errors.Wrap(err, "cannot generate private key")
+	}
+
+	// the certificate stays valid until the overlap after the next rotation ends
+	validity := clientAuthenticationCertificateValidity
+	if key.RotationPeriod != nil {
+		validity = key.RotationPeriod.Duration
+		if key.RotationOverlap != nil {
+			validity += key.RotationOverlap.Duration
+		}
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return errors.Wrap(err, "cannot generate serial number")
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: cr.Spec.Client.ClientID},
+		NotBefore:    now,
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		return errors.Wrap(err, "cannot create certificate")
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return errors.Wrap(err, "cannot create certificate")
+	}
+	jwk, err := toJSONWebKey(certificate)
+	if err != nil {
+		return err
+	}
+	encodedKey, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode private key")
+	}
+
+	secret.Data = map[string][]byte{
+		v1.TLSCertKey:                pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		v1.TLSPrivateKeyKey:          pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encodedKey}),
+		ClientAuthenticationKeyIDKey: []byte(jwk.Kid),
+	}
+	return nil
+}
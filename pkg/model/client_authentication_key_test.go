@@ -0,0 +1,188 @@
+package model
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testClientAuthenticationClient(key *v1alpha1.ClientAuthenticationKey) *v1alpha1.KeycloakClient {
+	return &v1alpha1.KeycloakClient{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "orders",
+			Namespace: "my-app",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client:                  &v1alpha1.KeycloakAPIClient{ClientID: "orders"},
+			ClientAuthenticationKey: key,
+		},
+	}
+}
+
+func testClientAuthenticationSecret(t *testing.T) *v1.Secret {
+	cr := testClientAuthenticationClient(&v1alpha1.ClientAuthenticationKey{SecretName: "orders-key", Generate: true})
+	secret, err := GenerateClientAuthenticationSecret(cr, time.Now())
+	assert.NoError(t, err)
+	return secret
+}
+
+func TestClientAuthenticationKey_Test_GenerateClientAuthenticationSecret(t *testing.T) {
+	// when
+	secret := testClientAuthenticationSecret(t)
+
+	// then
+	assert.Equal(t, "orders-key", secret.Name)
+	assert.Equal(t, "my-app", secret.Namespace)
+	assert.Equal(t, v1.SecretTypeTLS, secret.Type)
+
+	certificate, err := parseCertificate(secret.Data[v1.TLSCertKey])
+	assert.NoError(t, err)
+	assert.Equal(t, "orders", certificate.Subject.CommonName)
+
+	block, _ := pem.Decode(secret.Data[v1.TLSPrivateKeyKey])
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.NoError(t, err)
+	assert.True(t, key.(*rsa.PrivateKey).PublicKey.Equal(certificate.PublicKey))
+
+	jwk, err := toJSONWebKey(certificate)
+	assert.NoError(t, err)
+	assert.Equal(t, jwk.Kid, string(secret.Data[ClientAuthenticationKeyIDKey]))
+}
+
+func TestClientAuthenticationKey_Test_ApplyClientAuthenticationKey(t *testing.T) {
+	// given
+	secret := testClientAuthenticationSecret(t)
+	certificate, err := parseCertificate(secret.Data[v1.TLSCertKey])
+	assert.NoError(t, err)
+	client := &v1alpha1.KeycloakAPIClient{ClientID: "orders"}
+	x509Client := &v1alpha1.KeycloakAPIClient{ClientID: "orders", ClientAuthenticatorType: ClientAuthenticatorX509}
+
+	// when
+	now := time.Now()
+	invalid := &v1.Secret{Data: map[string][]byte{v1.TLSCertKey: []byte("invalid")}}
+	jwtApplied, jwtErr := ApplyClientAuthenticationKey(client, &v1alpha1.ClientAuthenticationKey{SecretName: "orders-key"}, secret, now)
+	jwksApplied, jwksErr := ApplyClientAuthenticationKey(client, &v1alpha1.ClientAuthenticationKey{SecretName: "orders-key", UseJWKS: true}, secret, now)
+	x509Applied, x509Err := ApplyClientAuthenticationKey(x509Client, &v1alpha1.ClientAuthenticationKey{SecretName: "orders-key"}, secret, now)
+	_, invalidErr := ApplyClientAuthenticationKey(client, &v1alpha1.ClientAuthenticationKey{SecretName: "orders-key"}, invalid, now)
+
+	// then
+	assert.NoError(t, jwtErr)
+	assert.Equal(t, ClientAuthenticatorJWT, jwtApplied.ClientAuthenticatorType)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(certificate.Raw), jwtApplied.Attributes[JWTCredentialCertificateAttribute])
+	assert.Equal(t, "false", jwtApplied.Attributes[UseJWKSURLAttribute])
+	assert.Equal(t, "false", jwtApplied.Attributes[UseJWKSStringAttribute])
+
+	assert.NoError(t, jwksErr)
+	assert.Equal(t, "true", jwksApplied.Attributes[UseJWKSStringAttribute])
+	assert.Contains(t, jwksApplied.Attributes[JWKSStringAttribute], `"kty":"RSA"`)
+	assert.NotContains(t, jwksApplied.Attributes, JWTCredentialCertificateAttribute)
+
+	assert.NoError(t, x509Err)
+	assert.Equal(t, "CN=orders", x509Applied.Attributes[X509SubjectDNAttribute])
+	assert.Equal(t, "false", x509Applied.Attributes[X509AllowRegexAttribute])
+
+	assert.Error(t, invalidErr)
+	// the client itself is not changed
+	assert.Empty(t, client.ClientAuthenticatorType)
+	assert.Empty(t, client.Attributes)
+}
+
+func TestClientAuthenticationKey_Test_JWKS_Thumbprint(t *testing.T) {
+	// given
+	// the key of the example of RFC 7638, section 3.1
+	n, _ := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	publicKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+	signer, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, publicKey, signer)
+	assert.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	// when
+	jwks, err := JWKS(certificate)
+
+	// then
+	assert.NoError(t, err)
+	keys := map[string][]map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(jwks), &keys))
+	assert.Len(t, keys["keys"], 1)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", keys["keys"][0]["kid"])
+	assert.Equal(t, "AQAB", keys["keys"][0]["e"])
+	assert.Equal(t, "RS256", keys["keys"][0]["alg"])
+}
+
+func TestClientAuthenticationKey_Test_Rotation(t *testing.T) {
+	// given
+	key := &v1alpha1.ClientAuthenticationKey{
+		SecretName:      "orders-key",
+		Generate:        true,
+		Algorithm:       "ES256",
+		UseJWKS:         true,
+		RotationPeriod:  &v12.Duration{Duration: 30 * 24 * time.Hour},
+		RotationOverlap: &v12.Duration{Duration: 24 * time.Hour},
+	}
+	cr := testClientAuthenticationClient(key)
+	issued := time.Now().Add(-31 * 24 * time.Hour)
+	secret, err := GenerateClientAuthenticationSecret(cr, issued)
+	assert.NoError(t, err)
+	client := &v1alpha1.KeycloakAPIClient{ClientID: "orders"}
+
+	// when
+	now := time.Now()
+	due := ClientAuthenticationKeyRotationDue(key, secret, now)
+	rotated, rotateErr := RotateClientAuthenticationSecret(cr, secret, now)
+	rotatedDue := ClientAuthenticationKeyRotationDue(key, rotated, now)
+	duringOverlap, overlapErr := ApplyClientAuthenticationKey(client, key, rotated, now.Add(time.Hour))
+	afterOverlap, afterErr := ApplyClientAuthenticationKey(client, key, rotated, now.Add(25*time.Hour))
+
+	// then
+	assert.True(t, due)
+	assert.NoError(t, rotateErr)
+	assert.False(t, rotatedDue)
+	assert.Equal(t, secret.Data[v1.TLSCertKey], rotated.Data[ClientAuthenticationPreviousCertificateKey])
+	assert.NotEqual(t, secret.Data[v1.TLSPrivateKeyKey], rotated.Data[v1.TLSPrivateKeyKey])
+
+	block, _ := pem.Decode(rotated.Data[v1.TLSPrivateKeyKey])
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.NoError(t, err)
+	assert.IsType(t, &ecdsa.PrivateKey{}, privateKey)
+
+	// the previous key stays registered while the overlap lasts
+	assert.NoError(t, overlapErr)
+	keys := map[string][]map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(duringOverlap.Attributes[JWKSStringAttribute]), &keys))
+	assert.Len(t, keys["keys"], 2)
+	assert.Equal(t, string(rotated.Data[ClientAuthenticationKeyIDKey]), keys["keys"][0]["kid"])
+	assert.Equal(t, string(secret.Data[ClientAuthenticationKeyIDKey]), keys["keys"][1]["kid"])
+	assert.Equal(t, "ES256", keys["keys"][0]["alg"])
+	assert.Equal(t, "ES256", duringOverlap.Attributes[JWTSigningAlgorithmAttribute])
+
+	assert.NoError(t, afterErr)
+	keys = map[string][]map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(afterOverlap.Attributes[JWKSStringAttribute]), &keys))
+	assert.Len(t, keys["keys"], 1)
+
+	// the client is reconciled again when the overlap ends
+	requeueAfter := ClientAuthenticationKeyRequeueAfter(key, rotated, now)
+	assert.True(t, requeueAfter > 23*time.Hour && requeueAfter <= 24*time.Hour)
+}
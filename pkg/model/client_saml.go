@@ -0,0 +1,296 @@
+package model
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	SAMLProtocol = "saml"
+
+	// attributes of SAML clients in Keycloak
+	SAMLAssertionConsumerURLPostAttribute       = "saml_assertion_consumer_url_post"
+	SAMLAssertionConsumerURLRedirectAttribute   = "saml_assertion_consumer_url_redirect"
+	SAMLSingleLogoutServiceURLPostAttribute     = "saml_single_logout_service_url_post"
+	SAMLSingleLogoutServiceURLRedirectAttribute = "saml_single_logout_service_url_redirect"
+	SAMLNameIDFormatAttribute                   = "saml_name_id_format"
+	SAMLForceNameIDFormatAttribute              = "saml_force_name_id_format"
+	SAMLServerSignatureAttribute                = "saml.server.signature"
+	SAMLAssertionSignatureAttribute             = "saml.assertion.signature"
+	SAMLSignatureAlgorithmAttribute             = "saml.signature.algorithm"
+	SAMLClientSignatureAttribute                = "saml.client.signature"
+	SAMLEncryptAttribute                        = "saml.encrypt"
+	SAMLForcePostBindingAttribute               = "saml.force.post.binding"
+	SAMLSigningCertificateAttribute             = "saml.signing.certificate"
+	SAMLSigningPrivateKeyAttribute              = "saml.signing.private.key"
+	SAMLEncryptionCertificateAttribute          = "saml.encryption.certificate"
+
+	// Key of the SAML metadata of the identity provider in the published ConfigMap
+	SAMLIdentityProviderMetadataKey = "idp-metadata.xml"
+	// Installation provider of Keycloak for the SAML metadata of the identity provider
+	SAMLIdentityProviderDescriptorProvider = "saml-idp-descriptor"
+
+	samlBindingPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlBindingRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	samlKeyUseSigning   = "signing"
+	samlKeyUseEncrypt   = "encryption"
+)
+
+// name ID formats of the SAML metadata and the corresponding format of Keycloak
+var samlNameIDFormats = map[string]string{
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified":  "username",
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress": "email",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:transient":    "transient",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:persistent":   "persistent",
+}
+
+// SAMLServiceProviderMetadata are the settings of a client taken from the SAML metadata of the service provider
+type SAMLServiceProviderMetadata struct {
+	EntityID                     string
+	AssertionConsumerURLPost     string
+	AssertionConsumerURLRedirect string
+	SingleLogoutURLPost          string
+	SingleLogoutURLRedirect      string
+	NameIDFormat                 string
+	AuthnRequestsSigned          *bool
+	WantAssertionsSigned         *bool
+	// base64 encoded DER certificates as used by Keycloak
+	SigningCertificate    string
+	EncryptionCertificate string
+}
+
+// SAMLKeyMaterial are the base64 encoded DER certificates and PKCS #8 private key of a SAML client as used by Keycloak
+type SAMLKeyMaterial struct {
+	SigningCertificate    string
+	SigningPrivateKey     string
+	EncryptionCertificate string
+}
+
+type samlEntityDescriptor struct {
+	EntityID        string                 `xml:"entityID,attr"`
+	SPSSODescriptor *samlSPSSODescriptor   `xml:"SPSSODescriptor"`
+	Descriptors     []samlEntityDescriptor `xml:"EntityDescriptor"`
+}
+
+type samlSPSSODescriptor struct {
+	AuthnRequestsSigned       *bool               `xml:"AuthnRequestsSigned,attr"`
+	WantAssertionsSigned      *bool               `xml:"WantAssertionsSigned,attr"`
+	KeyDescriptors            []samlKeyDescriptor `xml:"KeyDescriptor"`
+	SingleLogoutServices      []samlEndpoint      `xml:"SingleLogoutService"`
+	NameIDFormats             []string            `xml:"NameIDFormat"`
+	AssertionConsumerServices []samlEndpoint      `xml:"AssertionConsumerService"`
+}
+
+type samlKeyDescriptor struct {
+	Use          string   `xml:"use,attr"`
+	Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+type samlEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// ParseSAMLServiceProviderMetadata reads the settings of a client from the SAML metadata of a service provider. The
+// metadata is an EntityDescriptor or an EntitiesDescriptor with the descriptor of the service provider as first entry.
+func ParseSAMLServiceProviderMetadata(data []byte) (*SAMLServiceProviderMetadata, error) {
+	descriptor := samlEntityDescriptor{}
+	err := xml.Unmarshal(data, &descriptor)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse saml metadata")
+	}
+	for descriptor.SPSSODescriptor == nil && len(descriptor.Descriptors) > 0 {
+		descriptor = descriptor.Descriptors[0]
+	}
+	sp := descriptor.SPSSODescriptor
+	if sp == nil {
+		return nil, errors.New("saml metadata contains no service provider")
+	}
+
+	metadata := &SAMLServiceProviderMetadata{
+		EntityID:                     descriptor.EntityID,
+		AssertionConsumerURLPost:     samlEndpointLocation(sp.AssertionConsumerServices, samlBindingPost),
+		AssertionConsumerURLRedirect: samlEndpointLocation(sp.AssertionConsumerServices, samlBindingRedirect),
+		SingleLogoutURLPost:          samlEndpointLocation(sp.SingleLogoutServices, samlBindingPost),
+		SingleLogoutURLRedirect:      samlEndpointLocation(sp.SingleLogoutServices, samlBindingRedirect),
+		AuthnRequestsSigned:          sp.AuthnRequestsSigned,
+		WantAssertionsSigned:         sp.WantAssertionsSigned,
+	}
+	for _, format := range sp.NameIDFormats {
+		if nameIDFormat, ok := samlNameIDFormats[removeWhitespace(format)]; ok {
+			metadata.NameIDFormat = nameIDFormat
+			break
+		}
+	}
+	for _, key := range sp.KeyDescriptors {
+		if len(key.Certificates) == 0 {
+			continue
+		}
+		certificate := removeWhitespace(key.Certificates[0])
+		// keys without use are used for signing and encryption
+		if (key.Use == "" || key.Use == samlKeyUseSigning) && metadata.SigningCertificate == "" {
+			metadata.SigningCertificate = certificate
+		}
+		if (key.Use == "" || key.Use == samlKeyUseEncrypt) && metadata.EncryptionCertificate == "" {
+			metadata.EncryptionCertificate = certificate
+		}
+	}
+	return metadata, nil
+}
+
+func samlEndpointLocation(endpoints []samlEndpoint, binding string) string {
+	for _, endpoint := range endpoints {
+		if endpoint.Binding == binding {
+			return endpoint.Location
+		}
+	}
+	return ""
+}
+
+// removeWhitespace removes all whitespace, the values in the metadata are usually wrapped and indented
+func removeWhitespace(value string) string {
+	return strings.Join(strings.Fields(value), "")
+}
+
+// SAMLCertificate returns the first PEM encoded certificate of the data as base64 encoded DER
+func SAMLCertificate(data []byte) (string, error) {
+	certificate, err := parseCertificate(data)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot parse certificate")
+	}
+	return base64.StdEncoding.EncodeToString(certificate.Raw), nil
+}
+
+// SAMLPrivateKey returns the PEM encoded PKCS #1, PKCS #8 or EC private key of the data as base64 encoded PKCS #8
+func SAMLPrivateKey(data []byte) (string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", errors.New("no private key found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "cannot parse private key")
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot encode private key")
+	}
+	return base64.StdEncoding.EncodeToString(der), nil
+}
+
+// ApplyClientSAML returns a copy of the client with the SAML settings added to its attributes. The settings take
+// precedence over the attributes of the client, the metadata of the service provider is only used for the attributes
+// that are neither set in the settings nor in the client.
+func ApplyClientSAML(client *v1alpha1.KeycloakAPIClient, saml *v1alpha1.KeycloakClientSAML, metadata *SAMLServiceProviderMetadata, keys SAMLKeyMaterial) *v1alpha1.KeycloakAPIClient {
+	applied := client.DeepCopy()
+	if saml == nil {
+		return applied
+	}
+	if applied.Protocol == "" {
+		applied.Protocol = SAMLProtocol
+	}
+	if applied.Attributes == nil {
+		applied.Attributes = map[string]string{}
+	}
+
+	if metadata != nil {
+		setMissingAttribute(applied.Attributes, SAMLAssertionConsumerURLPostAttribute, metadata.AssertionConsumerURLPost)
+		setMissingAttribute(applied.Attributes, SAMLAssertionConsumerURLRedirectAttribute, metadata.AssertionConsumerURLRedirect)
+		setMissingAttribute(applied.Attributes, SAMLSingleLogoutServiceURLPostAttribute, metadata.SingleLogoutURLPost)
+		setMissingAttribute(applied.Attributes, SAMLSingleLogoutServiceURLRedirectAttribute, metadata.SingleLogoutURLRedirect)
+		setMissingAttribute(applied.Attributes, SAMLNameIDFormatAttribute, metadata.NameIDFormat)
+		setMissingAttribute(applied.Attributes, SAMLClientSignatureAttribute, formatOptionalBool(metadata.AuthnRequestsSigned))
+		setMissingAttribute(applied.Attributes, SAMLAssertionSignatureAttribute, formatOptionalBool(metadata.WantAssertionsSigned))
+		setMissingAttribute(applied.Attributes, SAMLSigningCertificateAttribute, metadata.SigningCertificate)
+		setMissingAttribute(applied.Attributes, SAMLEncryptionCertificateAttribute, metadata.EncryptionCertificate)
+	}
+
+	setAttribute(applied.Attributes, SAMLAssertionConsumerURLPostAttribute, saml.AssertionConsumerURLPost)
+	setAttribute(applied.Attributes, SAMLAssertionConsumerURLRedirectAttribute, saml.AssertionConsumerURLRedirect)
+	setAttribute(applied.Attributes, SAMLSingleLogoutServiceURLPostAttribute, saml.SingleLogoutServiceURLPost)
+	setAttribute(applied.Attributes, SAMLSingleLogoutServiceURLRedirectAttribute, saml.SingleLogoutServiceURLRedirect)
+	setAttribute(applied.Attributes, SAMLNameIDFormatAttribute, saml.NameIDFormat)
+	setAttribute(applied.Attributes, SAMLForceNameIDFormatAttribute, formatOptionalBool(saml.ForceNameIDFormat))
+	setAttribute(applied.Attributes, SAMLServerSignatureAttribute, formatOptionalBool(saml.SignDocuments))
+	setAttribute(applied.Attributes, SAMLAssertionSignatureAttribute, formatOptionalBool(saml.SignAssertions))
+	setAttribute(applied.Attributes, SAMLSignatureAlgorithmAttribute, saml.SignatureAlgorithm)
+	setAttribute(applied.Attributes, SAMLClientSignatureAttribute, formatOptionalBool(saml.ClientSignatureRequired))
+	setAttribute(applied.Attributes, SAMLEncryptAttribute, formatOptionalBool(saml.EncryptAssertions))
+	setAttribute(applied.Attributes, SAMLForcePostBindingAttribute, formatOptionalBool(saml.ForcePostBinding))
+	setAttribute(applied.Attributes, SAMLSigningCertificateAttribute, keys.SigningCertificate)
+	setAttribute(applied.Attributes, SAMLSigningPrivateKeyAttribute, keys.SigningPrivateKey)
+	setAttribute(applied.Attributes, SAMLEncryptionCertificateAttribute, keys.EncryptionCertificate)
+	return applied
+}
+
+func setAttribute(attributes map[string]string, key, value string) {
+	if value != "" {
+		attributes[key] = value
+	}
+}
+
+func setMissingAttribute(attributes map[string]string, key, value string) {
+	if _, ok := attributes[key]; !ok {
+		setAttribute(attributes, key, value)
+	}
+}
+
+func formatOptionalBool(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
+
+// SAMLIdentityProviderMetadataConfigMap is the ConfigMap the SAML metadata of the identity provider is published to
+func SAMLIdentityProviderMetadataConfigMap(cr *v1alpha1.KeycloakClient, metadata []byte) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      cr.Spec.SAML.IdentityProviderMetadataConfigMap,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app": ApplicationName,
+			},
+		},
+		Data: map[string]string{
+			SAMLIdentityProviderMetadataKey: string(metadata),
+		},
+	}
+}
+
+func SAMLIdentityProviderMetadataConfigMapSelector(cr *v1alpha1.KeycloakClient) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      cr.Spec.SAML.IdentityProviderMetadataConfigMap,
+		Namespace: cr.Namespace,
+	}
+}
+
+func SAMLIdentityProviderMetadataConfigMapReconciled(currentState *v1.ConfigMap, metadata []byte) *v1.ConfigMap {
+	reconciled := currentState.DeepCopy()
+	if reconciled.Data == nil {
+		reconciled.Data = map[string]string{}
+	}
+	reconciled.Data[SAMLIdentityProviderMetadataKey] = string(metadata)
+	return reconciled
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSPMetadata = `<?xml version="1.0"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="https://sp.example.com/saml">
+  <md:SPSSODescriptor AuthnRequestsSigned="true" WantAssertionsSigned="false" protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo>
+        <ds:X509Data>
+          <ds:X509Certificate>
+            MIIB
+            signing
+          </ds:X509Certificate>
+        </ds:X509Data>
+      </ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:KeyDescriptor use="encryption">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>MIIBencryption</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://sp.example.com/saml/slo"/>
+    <md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</md:NameIDFormat>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/saml/acs" index="0"/>
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>`
+
+func TestClientSAML_Test_ParseSAMLServiceProviderMetadata(t *testing.T) {
+	// when
+	metadata, err := ParseSAMLServiceProviderMetadata([]byte(testSPMetadata))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "https://sp.example.com/saml", metadata.EntityID)
+	assert.Equal(t, "https://sp.example.com/saml/acs", metadata.AssertionConsumerURLPost)
+	assert.Equal(t, "", metadata.AssertionConsumerURLRedirect)
+	assert.Equal(t, "https://sp.example.com/saml/slo", metadata.SingleLogoutURLRedirect)
+	assert.Equal(t, "email", metadata.NameIDFormat)
+	assert.True(t, *metadata.AuthnRequestsSigned)
+	assert.False(t, *metadata.WantAssertionsSigned)
+	assert.Equal(t, "MIIBsigning", metadata.SigningCertificate)
+	assert.Equal(t, "MIIBencryption", metadata.EncryptionCertificate)
+}
+
+func TestClientSAML_Test_ParseSAMLServiceProviderMetadata_Without_ServiceProvider(t *testing.T) {
+	// when
+	_, err := ParseSAMLServiceProviderMetadata([]byte(`<EntityDescriptor entityID="idp"><IDPSSODescriptor/></EntityDescriptor>`))
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestClientSAML_Test_KeyMaterial(t *testing.T) {
+	// given
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sp.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &rsaKey.PublicKey, rsaKey)
+	assert.NoError(t, err)
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	assert.NoError(t, err)
+
+	certificatePEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})
+	rsaKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	ecKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER})
+
+	// when
+	encodedCertificate, certificateErr := SAMLCertificate(certificatePEM)
+	encodedRSAKey, rsaErr := SAMLPrivateKey(rsaKeyPEM)
+	encodedECKey, ecErr := SAMLPrivateKey(ecKeyPEM)
+	_, missingErr := SAMLCertificate(rsaKeyPEM)
+
+	// then
+	assert.NoError(t, certificateErr)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(certificate), encodedCertificate)
+
+	// private keys are converted to PKCS #8
+	assert.NoError(t, rsaErr)
+	der, _ := base64.StdEncoding.DecodeString(encodedRSAKey)
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	assert.NoError(t, err)
+	assert.True(t, rsaKey.Equal(key))
+
+	assert.NoError(t, ecErr)
+	der, _ = base64.StdEncoding.DecodeString(encodedECKey)
+	key, err = x509.ParsePKCS8PrivateKey(der)
+	assert.NoError(t, err)
+	assert.True(t, ecKey.Equal(key))
+
+	assert.Error(t, missingErr)
+}
+
+func TestClientSAML_Test_ApplyClientSAML(t *testing.T) {
+	// given
+	signDocuments := true
+	client := &v1alpha1.KeycloakAPIClient{
+		ClientID: "https://sp.example.com/saml",
+		Attributes: map[string]string{
+			SAMLNameIDFormatAttribute:       "username",
+			SAMLServerSignatureAttribute:    "false",
+			SAMLSignatureAlgorithmAttribute: "RSA_SHA512",
+		},
+	}
+	saml := &v1alpha1.KeycloakClientSAML{
+		SingleLogoutServiceURLPost: "https://sp.example.com/logout",
+		SignDocuments:              &signDocuments,
+	}
+	metadata, err := ParseSAMLServiceProviderMetadata([]byte(testSPMetadata))
+	assert.NoError(t, err)
+	keys := SAMLKeyMaterial{SigningCertificate: "MIIBsecret", SigningPrivateKey: "MIIEkey"}
+
+	// when
+	applied := ApplyClientSAML(client, saml, metadata, keys)
+
+	// then
+	assert.Equal(t, SAMLProtocol, applied.Protocol)
+	// the settings win over the attributes, the attributes over the metadata
+	assert.Equal(t, "true", applied.Attributes[SAMLServerSignatureAttribute])
+	assert.Equal(t, "https://sp.example.com/logout", applied.Attributes[SAMLSingleLogoutServiceURLPostAttribute])
+	assert.Equal(t, "username", applied.Attributes[SAMLNameIDFormatAttribute])
+	assert.Equal(t, "RSA_SHA512", applied.Attributes[SAMLSignatureAlgorithmAttribute])
+	assert.Equal(t, "https://sp.example.com/saml/acs", applied.Attributes[SAMLAssertionConsumerURLPostAttribute])
+	assert.Equal(t, "https://sp.example.com/saml/slo", applied.Attributes[SAMLSingleLogoutServiceURLRedirectAttribute])
+	assert.Equal(t, "true", applied.Attributes[SAMLClientSignatureAttribute])
+	assert.Equal(t, "false", applied.Attributes[SAMLAssertionSignatureAttribute])
+	// the key material of the secret wins over the certificate of the metadata
+	assert.Equal(t, "MIIBsecret", applied.Attributes[SAMLSigningCertificateAttribute])
+	assert.Equal(t, "MIIEkey", applied.Attributes[SAMLSigningPrivateKeyAttribute])
+	assert.Equal(t, "MIIBencryption", applied.Attributes[SAMLEncryptionCertificateAttribute])
+	assert.NotContains(t, applied.Attributes, SAMLEncryptAttribute)
+	// the client itself is not changed
+	assert.Equal(t, "false", client.Attributes[SAMLServerSignatureAttribute])
+	assert.Empty(t, client.Protocol)
+}
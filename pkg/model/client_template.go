@@ -0,0 +1,136 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// fields of a client that are never taken from a template, they identify the client
+var clientTemplateIgnoredFields = []string{"id", "clientId"}
+
+// map fields of a client that are merged with the map of the template, the entries of the client win
+var clientTemplateMergedFields = []string{"attributes", "access", "authenticationFlowBindingOverrides"}
+
+// list fields of a client the missing entries of the template are appended to, protocol mappers are matched by name
+var clientTemplateAppendedFields = []string{"redirectUris", "webOrigins", "defaultRoles", "defaultClientScopes", "optionalClientScopes", "protocolMappers"}
+
+// MergeClientTemplate returns the client with the defaults of the template applied. The client is given by its fields
+// as stored in the resource, so fields that are set to false or 0 can be told apart from fields that are not set.
+// Fields that are not set in the client are taken from the template, maps are merged and the entries of some lists of
+// the template are appended, all other fields of the client replace the fields of the template.
+func MergeClientTemplate(clientFields map[string]interface{}, template *v1alpha1.KeycloakAPIClient) (*v1alpha1.KeycloakAPIClient, error) {
+	clientFields = runtime.DeepCopyJSON(clientFields)
+	templateFields, err := toClientFields(template)
+	if err != nil {
+		return nil, err
+	}
+
+	for field, templateValue := range templateFields {
+		clientValue, set := clientFields[field]
+		switch {
+		case containsField(clientTemplateIgnoredFields, field) || isEmptyField(templateValue):
+			continue
+		case !set || clientValue == nil:
+			clientFields[field] = templateValue
+		case containsField(clientTemplateMergedFields, field):
+			clientFields[field] = mergeTemplateMap(clientValue, templateValue)
+		case containsField(clientTemplateAppendedFields, field):
+			clientFields[field] = appendTemplateList(clientValue, templateValue)
+		}
+	}
+
+	data, err := json.Marshal(clientFields)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot apply client template")
+	}
+	merged := &v1alpha1.KeycloakAPIClient{}
+	err = json.Unmarshal(data, merged)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot apply client template")
+	}
+	return merged, nil
+}
+
+func toClientFields(client *v1alpha1.KeycloakAPIClient) (map[string]interface{}, error) {
+	data, err := json.Marshal(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot apply client template")
+	}
+	fields := map[string]interface{}{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot apply client template")
+	}
+	return fields, nil
+}
+
+func isEmptyField(value interface{}) bool {
+	switch typed := value.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(typed) == 0
+	case map[string]interface{}:
+		return len(typed) == 0
+	default:
+		return reflect.ValueOf(value).IsZero()
+	}
+}
+
+func containsField(fields []string, field string) bool {
+	for _, item := range fields {
+		if item == field {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeTemplateMap(clientValue, templateValue interface{}) interface{} {
+	clientMap, ok := clientValue.(map[string]interface{})
+	templateMap, templateOk := templateValue.(map[string]interface{})
+	if !ok || !templateOk {
+		return clientValue
+	}
+	for key, value := range templateMap {
+		if _, ok := clientMap[key]; !ok {
+			clientMap[key] = value
+		}
+	}
+	return clientMap
+}
+
+func appendTemplateList(clientValue, templateValue interface{}) interface{} {
+	clientList, ok := clientValue.([]interface{})
+	templateList, templateOk := templateValue.([]interface{})
+	if !ok || !templateOk {
+		return clientValue
+	}
+	for _, templateItem := range templateList {
+		found := false
+		for _, clientItem := range clientList {
+			if templateListItemMatches(clientItem, templateItem) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			clientList = append(clientList, templateItem)
+		}
+	}
+	return clientList
+}
+
+// templateListItemMatches compares the items of lists, objects like protocol mappers are matched by name
+func templateListItemMatches(clientItem, templateItem interface{}) bool {
+	clientObject, ok := clientItem.(map[string]interface{})
+	templateObject, templateOk := templateItem.(map[string]interface{})
+	if ok && templateOk {
+		return clientObject["name"] == templateObject["name"]
+	}
+	return reflect.DeepEqual(clientItem, templateItem)
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientTemplate_Test_MergeClientTemplate(t *testing.T) {
+	// given
+	// the client as stored in the resource
+	client := map[string]interface{}{
+		"clientId":            "orders",
+		"protocol":            "saml",
+		"redirectUris":        []interface{}{"https://orders.example.com/*"},
+		"defaultClientScopes": []interface{}{"profile", "orders"},
+		"attributes":          map[string]interface{}{"pkce.code.challenge.method": "plain"},
+		"protocolMappers": []interface{}{
+			map[string]interface{}{"name": "audience", "protocolMapper": "oidc-audience-mapper", "config": map[string]interface{}{"included.client.audience": "orders"}},
+		},
+	}
+	template := &v1alpha1.KeycloakAPIClient{
+		ClientID:                "template",
+		Protocol:                "openid-connect",
+		ClientAuthenticatorType: "client-secret",
+		StandardFlowEnabled:     true,
+		WebOrigins:              []string{"+"},
+		DefaultClientScopes:     []string{"profile", "email"},
+		Attributes:              map[string]string{"pkce.code.challenge.method": "S256", "post.logout.redirect.uris": "+"},
+		ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+			{Name: "audience", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"included.client.audience": "backend"}},
+			{Name: "groups", ProtocolMapper: "oidc-group-membership-mapper"},
+		},
+	}
+
+	// when
+	merged, err := MergeClientTemplate(client, template)
+
+	// then
+	// fields of the client win, empty fields are taken from the template, maps are merged and lists appended
+	assert.NoError(t, err)
+	assert.Equal(t, "orders", merged.ClientID)
+	assert.Equal(t, "saml", merged.Protocol)
+	assert.Equal(t, "client-secret", merged.ClientAuthenticatorType)
+	assert.True(t, merged.StandardFlowEnabled)
+	assert.Equal(t, []string{"https://orders.example.com/*"}, merged.RedirectUris)
+	assert.Equal(t, []string{"+"}, merged.WebOrigins)
+	assert.Equal(t, []string{"profile", "orders", "email"}, merged.DefaultClientScopes)
+	assert.Equal(t, map[string]string{"pkce.code.challenge.method": "plain", "post.logout.redirect.uris": "+"}, merged.Attributes)
+	assert.Len(t, merged.ProtocolMappers, 2)
+	assert.Equal(t, "orders", merged.ProtocolMappers[0].Config["included.client.audience"])
+	assert.Equal(t, "groups", merged.ProtocolMappers[1].Name)
+	assert.Equal(t, []interface{}{"profile", "orders"}, client["defaultClientScopes"])
+}
+
+func TestClientTemplate_Test_MergeClientTemplate_False(t *testing.T) {
+	// given
+	client := map[string]interface{}{
+		"clientId":                  "orders",
+		"standardFlowEnabled":       false,
+		"publicClient":              false,
+		"directAccessGrantsEnabled": false,
+	}
+	template := &v1alpha1.KeycloakAPIClient{
+		ClientID:                  "template",
+		StandardFlowEnabled:       true,
+		PublicClient:              true,
+		DirectAccessGrantsEnabled: true,
+		ServiceAccountsEnabled:    true,
+	}
+
+	// when
+	merged, err := MergeClientTemplate(client, template)
+
+	// then
+	// flags the client turns off explicitly stay off, flags the client doesn't set are taken from the template
+	assert.NoError(t, err)
+	assert.False(t, merged.StandardFlowEnabled)
+	assert.False(t, merged.PublicClient)
+	assert.False(t, merged.DirectAccessGrantsEnabled)
+	assert.True(t, merged.ServiceAccountsEnabled)
+}
@@ -9,6 +9,8 @@ const (
 	ClientSecretName                 = ApplicationName + "-client-secret"
 	ClientSecretClientIDProperty     = "CLIENT_ID"
 	ClientSecretClientSecretProperty = "CLIENT_SECRET"
+	UserSecretUsernameProperty       = "username"
+	UserSecretPasswordProperty       = "password"
 )
 
 var PodLabels = map[string]string{}
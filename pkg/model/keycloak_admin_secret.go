@@ -7,6 +7,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// KeycloakAdminSecretPrefix is the prefix of the names of the Secrets with the admin credentials of keycloaks
+const KeycloakAdminSecretPrefix = "credential-"
+
 func KeycloakAdminSecret(cr *v1alpha1.Keycloak) *v1.Secret {
 	return &v1.Secret{
 		ObjectMeta: v12.ObjectMeta{
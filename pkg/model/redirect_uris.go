@@ -0,0 +1,108 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	defaultRedirectPath = "/*"
+)
+
+// HTTPRouteGroupKind is the kind of the Gateway API HTTPRoutes redirect URIs are derived from, they are read in the
+// version the cluster prefers as older Gateway API releases don't serve v1
+var HTTPRouteGroupKind = schema.GroupKind{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}
+
+// IngressOrigins returns the origins of the hosts of an ingress. Without a scheme, https is used for the hosts with
+// TLS configured and http for all others. Wildcard hosts are left out.
+func IngressOrigins(ingress *networkingv1.Ingress, scheme string) []string {
+	var origins []string
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host == "" || strings.HasPrefix(rule.Host, "*") {
+			continue
+		}
+		hostScheme := scheme
+		if hostScheme == "" {
+			hostScheme = "http"
+			if ingressHasTLS(ingress, rule.Host) {
+				hostScheme = "https"
+			}
+		}
+		origins = appendMissing(origins, hostScheme+"://"+rule.Host)
+	}
+	return origins
+}
+
+func ingressHasTLS(ingress *networkingv1.Ingress, host string) bool {
+	for _, tls := range ingress.Spec.TLS {
+		if len(tls.Hosts) == 0 {
+			return true
+		}
+		for _, tlsHost := range tls.Hosts {
+			if tlsHost == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HTTPRouteOrigins returns the origins of the hostnames of a Gateway API HTTPRoute, https is used without a scheme.
+// Wildcard hostnames are left out.
+func HTTPRouteOrigins(route *unstructured.Unstructured, scheme string) []string {
+	if scheme == "" {
+		scheme = "https"
+	}
+	hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+
+	var origins []string
+	for _, hostname := range hostnames {
+		if hostname == "" || strings.HasPrefix(hostname, "*") {
+			continue
+		}
+		origins = appendMissing(origins, scheme+"://"+hostname)
+	}
+	return origins
+}
+
+// ApplyRedirectURIOrigins returns a copy of the client with the redirect URIs and web origins derived from the origins
+// added. The first origin becomes the root URL and the base path the base URL, unless they are set in the client.
+func ApplyRedirectURIOrigins(client *v1alpha1.KeycloakAPIClient, sources *v1alpha1.RedirectURISources, origins []string) *v1alpha1.KeycloakAPIClient {
+	applied := client.DeepCopy()
+	if sources == nil || len(origins) == 0 {
+		return applied
+	}
+
+	redirectPaths := sources.RedirectPaths
+	if len(redirectPaths) == 0 {
+		redirectPaths = []string{defaultRedirectPath}
+	}
+
+	for _, origin := range origins {
+		for _, path := range redirectPaths {
+			applied.RedirectUris = appendMissing(applied.RedirectUris, origin+path)
+		}
+		applied.WebOrigins = appendMissing(applied.WebOrigins, origin)
+	}
+
+	if applied.RootURL == "" {
+		applied.RootURL = origins[0]
+	}
+	if applied.BaseURL == "" {
+		applied.BaseURL = sources.BasePath
+	}
+	return applied
+}
+
+func appendMissing(list []string, value string) []string {
+	for _, item := range list {
+		if item == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
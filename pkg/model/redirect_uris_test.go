@@ -0,0 +1,102 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRedirectURIs_Test_IngressOrigins(t *testing.T) {
+	// given
+	ingress := &networkingv1.Ingress{
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{Host: "orders.example.com"},
+				{Host: "orders.internal"},
+				{Host: "*.example.com"},
+				{},
+				{Host: "orders.example.com"},
+			},
+			TLS: []networkingv1.IngressTLS{
+				{Hosts: []string{"orders.example.com"}},
+			},
+		},
+	}
+
+	// when
+	origins := IngressOrigins(ingress, "")
+	httpsOrigins := IngressOrigins(ingress, "https")
+
+	// then
+	// hosts with tls use https, wildcard and empty hosts are left out
+	assert.Equal(t, []string{"https://orders.example.com", "http://orders.internal"}, origins)
+	assert.Equal(t, []string{"https://orders.example.com", "https://orders.internal"}, httpsOrigins)
+}
+
+func TestRedirectURIs_Test_HTTPRouteOrigins(t *testing.T) {
+	// given
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"hostnames": []interface{}{"orders.example.com", "*.example.com", "shop.example.com"},
+		},
+	}}
+
+	// when
+	origins := HTTPRouteOrigins(route, "")
+
+	// then
+	assert.Equal(t, []string{"https://orders.example.com", "https://shop.example.com"}, origins)
+}
+
+func TestRedirectURIs_Test_ApplyRedirectURIOrigins(t *testing.T) {
+	// given
+	client := &v1alpha1.KeycloakAPIClient{
+		ClientID:     "orders",
+		RedirectUris: []string{"http://localhost:8080/*"},
+		WebOrigins:   []string{"+"},
+	}
+	sources := &v1alpha1.RedirectURISources{
+		RedirectPaths: []string{"/callback", "/silent-renew"},
+		BasePath:      "/app",
+	}
+	origins := []string{"https://orders.example.com", "https://shop.example.com"}
+
+	// when
+	applied := ApplyRedirectURIOrigins(client, sources, origins)
+
+	// then
+	assert.Equal(t, []string{
+		"http://localhost:8080/*",
+		"https://orders.example.com/callback",
+		"https://orders.example.com/silent-renew",
+		"https://shop.example.com/callback",
+		"https://shop.example.com/silent-renew",
+	}, applied.RedirectUris)
+	assert.Equal(t, []string{"+", "https://orders.example.com", "https://shop.example.com"}, applied.WebOrigins)
+	assert.Equal(t, "https://orders.example.com", applied.RootURL)
+	assert.Equal(t, "/app", applied.BaseURL)
+	// the client itself is not changed
+	assert.Equal(t, []string{"http://localhost:8080/*"}, client.RedirectUris)
+	assert.Empty(t, client.RootURL)
+}
+
+func TestRedirectURIs_Test_ApplyRedirectURIOrigins_Keeps_Client_URLs(t *testing.T) {
+	// given
+	client := &v1alpha1.KeycloakAPIClient{
+		ClientID: "orders",
+		RootURL:  "https://orders.example.org",
+		BaseURL:  "/home",
+	}
+	sources := &v1alpha1.RedirectURISources{}
+
+	// when
+	applied := ApplyRedirectURIOrigins(client, sources, []string{"https://orders.example.com"})
+
+	// then
+	assert.Equal(t, []string{"https://orders.example.com/*"}, applied.RedirectUris)
+	assert.Equal(t, "https://orders.example.org", applied.RootURL)
+	assert.Equal(t, "/home", applied.BaseURL)
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// FeatureAuthorization is the Keycloak feature required for the authorization services of clients
+	FeatureAuthorization = "authorization"
+	// ProtocolOpenIDConnect is the protocol of clients and protocol mappers that don't set one
+	ProtocolOpenIDConnect = "openid-connect"
+)
+
+// FeatureDisabled checks whether the keycloak reported the feature as disabled
+func FeatureDisabled(info *v1alpha1.KeycloakServerInfo, feature string) bool {
+	return info != nil && containsField(info.DisabledFeatures, feature)
+}
+
+// ValidateClientCapabilities checks that the features and providers the client needs are available in the keycloak. The
+// authorization services need the authorization feature, the protocol mappers and the client authenticator need to be
+// provided by the keycloak. Nothing is checked as long as the capabilities of the keycloak are unknown.
+func ValidateClientCapabilities(client *v1alpha1.KeycloakAPIClient, info *v1alpha1.KeycloakServerInfo) error {
+	if client == nil || info == nil {
+		return nil
+	}
+
+	var problems []string
+	if (client.AuthorizationServicesEnabled || client.AuthorizationSettings != nil) && FeatureDisabled(info, FeatureAuthorization) {
+		problems = append(problems, "authorization services need the disabled feature "+FeatureAuthorization)
+	}
+
+	if client.ClientAuthenticatorType != "" && len(info.ClientAuthenticators) > 0 &&
+		!containsField(info.ClientAuthenticators, client.ClientAuthenticatorType) {
+		problems = append(problems, fmt.Sprintf("client authenticator %v is not available", client.ClientAuthenticatorType))
+	}
+
+	for _, mapper := range client.ProtocolMappers {
+		if mapper.ProtocolMapper == "" || len(info.ProtocolMapperTypes) == 0 {
+			continue
+		}
+		protocol := mapper.Protocol
+		if protocol == "" {
+			protocol = client.Protocol
+		}
+		if protocol == "" {
+			protocol = ProtocolOpenIDConnect
+		}
+		if !containsField(info.ProtocolMapperTypes[protocol], mapper.ProtocolMapper) {
+			problems = append(problems, fmt.Sprintf("protocol mapper type %v of mapper %v is not available for protocol %v",
+				mapper.ProtocolMapper, mapper.Name, protocol))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("client %v is not supported: %v", client.ClientID, strings.Join(problems, "; "))
+	}
+	return nil
+}
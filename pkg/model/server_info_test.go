@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func getServerInfo() *v1alpha1.KeycloakServerInfo {
+	return &v1alpha1.KeycloakServerInfo{
+		DisabledFeatures: []string{"authorization", "token-exchange"},
+		ProtocolMapperTypes: map[string][]string{
+			"openid-connect": {"oidc-audience-mapper", "oidc-usermodel-attribute-mapper"},
+			"saml":           {"saml-role-list-mapper"},
+		},
+		ClientAuthenticators: []string{"client-jwt", "client-secret"},
+	}
+}
+
+func TestServerInfo_ValidateClientCapabilities(t *testing.T) {
+	// given
+	client := &v1alpha1.KeycloakAPIClient{
+		ClientID:                "orders",
+		ClientAuthenticatorType: "client-secret",
+		ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+			{Name: "audience", ProtocolMapper: "oidc-audience-mapper"},
+			{Name: "roles", Protocol: "saml", ProtocolMapper: "saml-role-list-mapper"},
+		},
+	}
+
+	// when
+	err := ValidateClientCapabilities(client, getServerInfo())
+
+	// then
+	// mappers without protocol use openid-connect
+	assert.NoError(t, err)
+}
+
+func TestServerInfo_ValidateClientCapabilities_Unsupported(t *testing.T) {
+	// given
+	client := &v1alpha1.KeycloakAPIClient{
+		ClientID:                     "orders",
+		Protocol:                     "saml",
+		ClientAuthenticatorType:      "client-x509",
+		AuthorizationServicesEnabled: true,
+		ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+			{Name: "audience", ProtocolMapper: "oidc-audience-mapper"},
+		},
+	}
+
+	// when
+	err := ValidateClientCapabilities(client, getServerInfo())
+
+	// then
+	// mappers without protocol use the protocol of the client
+	assert.EqualError(t, err, "client orders is not supported: "+
+		"authorization services need the disabled feature authorization; "+
+		"client authenticator client-x509 is not available; "+
+		"protocol mapper type oidc-audience-mapper of mapper audience is not available for protocol saml")
+}
+
+func TestServerInfo_ValidateClientCapabilities_Unknown(t *testing.T) {
+	// given
+	client := &v1alpha1.KeycloakAPIClient{
+		ClientID:                     "orders",
+		ClientAuthenticatorType:      "client-x509",
+		AuthorizationServicesEnabled: true,
+		ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+			{Name: "custom", ProtocolMapper: "custom-mapper"},
+		},
+	}
+
+	// when
+	errUnknown := ValidateClientCapabilities(client, nil)
+	errEmpty := ValidateClientCapabilities(client, &v1alpha1.KeycloakServerInfo{})
+
+	// then
+	// nothing is checked before the server info was queried
+	assert.NoError(t, errUnknown)
+	assert.NoError(t, errEmpty)
+}
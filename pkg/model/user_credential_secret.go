@@ -0,0 +1,38 @@
+package model
+
+import (
+	"github.com/christianwoehrle/keycloakclient-controller/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UserCredentialSecret contains the username and the initial password of a KeycloakUser,
+// the password is omitted if it is unknown
+func UserCredentialSecret(cr *v1alpha1.KeycloakUser, realm *v1alpha1.KeycloakRealm, keycloak *v1alpha1.Keycloak, password string) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      GetRealmUserSecretName(keycloak.Namespace, realm.Spec.Realm.Realm, cr.Spec.User.UserName),
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app": ApplicationName,
+			},
+		},
+		Data: map[string][]byte{
+			UserSecretUsernameProperty: []byte(cr.Spec.User.UserName),
+		},
+	}
+
+	if password != "" {
+		secret.Data[UserSecretPasswordProperty] = []byte(password)
+	}
+
+	return secret
+}
+
+func UserCredentialSecretSelector(cr *v1alpha1.KeycloakUser, realm *v1alpha1.KeycloakRealm, keycloak *v1alpha1.Keycloak) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      GetRealmUserSecretName(keycloak.Namespace, realm.Spec.Realm.Realm, cr.Spec.User.UserName),
+		Namespace: cr.Namespace,
+	}
+}
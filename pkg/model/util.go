@@ -3,8 +3,10 @@ package model
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -148,7 +150,7 @@ func roleMatches(a v1alpha1.RoleRepresentation, b v1alpha1.RoleRepresentation) b
 
 // FIXME Find a better way to refactor this code with role difference part above
 // returned clientScopes are always from a
-func ClientScopeDifferenceIntersection(a []v1alpha1.KeycloakClientScope, b []v1alpha1.KeycloakClientScope) (d []v1alpha1.KeycloakClientScope, i []v1alpha1.KeycloakClientScope) {
+func ClientScopeDifferenceIntersection(a []v1alpha1.KeycloakAPIClientScope, b []v1alpha1.KeycloakAPIClientScope) (d []v1alpha1.KeycloakAPIClientScope, i []v1alpha1.KeycloakAPIClientScope) {
 	for _, clientScope := range a {
 		if hasMatchingClientScope(b, clientScope) {
 			i = append(i, clientScope)
@@ -159,7 +161,7 @@ func ClientScopeDifferenceIntersection(a []v1alpha1.KeycloakClientScope, b []v1a
 	return d, i
 }
 
-func hasMatchingClientScope(clientScopes []v1alpha1.KeycloakClientScope, otherClientScope v1alpha1.KeycloakClientScope) bool {
+func hasMatchingClientScope(clientScopes []v1alpha1.KeycloakAPIClientScope, otherClientScope v1alpha1.KeycloakAPIClientScope) bool {
 	for _, clientScope := range clientScopes {
 		if clientScopeMatches(clientScope, otherClientScope) {
 			return true
@@ -168,15 +170,15 @@ func hasMatchingClientScope(clientScopes []v1alpha1.KeycloakClientScope, otherCl
 	return false
 }
 
-func clientScopeMatches(a v1alpha1.KeycloakClientScope, b v1alpha1.KeycloakClientScope) bool {
+func clientScopeMatches(a v1alpha1.KeycloakAPIClientScope, b v1alpha1.KeycloakAPIClientScope) bool {
 	if a.ID != "" && b.ID != "" {
 		return a.ID == b.ID
 	}
 	return a.Name == b.Name
 }
 
-func FilterClientScopesByNames(clientScopes []v1alpha1.KeycloakClientScope, names []string) (filteredScopes []v1alpha1.KeycloakClientScope) {
-	hashMap := make(map[string]v1alpha1.KeycloakClientScope)
+func FilterClientScopesByNames(clientScopes []v1alpha1.KeycloakAPIClientScope, names []string) (filteredScopes []v1alpha1.KeycloakAPIClientScope) {
+	hashMap := make(map[string]v1alpha1.KeycloakAPIClientScope)
 
 	for _, scope := range clientScopes {
 		hashMap[scope.Name] = scope
@@ -191,6 +193,80 @@ func FilterClientScopesByNames(clientScopes []v1alpha1.KeycloakClientScope, name
 	return filteredScopes
 }
 
+// returned protocol mappers are always from a, protocol mappers are matched by name
+func ProtocolMapperDifferenceIntersection(a []v1alpha1.KeycloakProtocolMapper, b []v1alpha1.KeycloakProtocolMapper) (d []v1alpha1.KeycloakProtocolMapper, i []v1alpha1.KeycloakProtocolMapper) {
+	for _, mapper := range a {
+		if FindProtocolMapperByName(b, mapper.Name) != nil {
+			i = append(i, mapper)
+		} else {
+			d = append(d, mapper)
+		}
+	}
+	return d, i
+}
+
+func FindProtocolMapperByName(mappers []v1alpha1.KeycloakProtocolMapper, name string) *v1alpha1.KeycloakProtocolMapper {
+	for i := range mappers {
+		if mappers[i].Name == name {
+			return &mappers[i]
+		}
+	}
+	return nil
+}
+
+// ProtocolMapperEquals compares the configuration of two protocol mappers, ignoring their IDs
+func ProtocolMapperEquals(a v1alpha1.KeycloakProtocolMapper, b v1alpha1.KeycloakProtocolMapper) bool {
+	if a.Name != b.Name || a.Protocol != b.Protocol || a.ProtocolMapper != b.ProtocolMapper ||
+		a.ConsentRequired != b.ConsentRequired || a.ConsentText != b.ConsentText {
+		return false
+	}
+	if len(a.Config) != len(b.Config) {
+		return false
+	}
+	for key, value := range a.Config {
+		if other, ok := b.Config[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+// SortAuthenticationExecutions returns the executions in the order they are executed by Keycloak,
+// executions with the same priority keep their order
+func SortAuthenticationExecutions(executions []v1alpha1.KeycloakAuthenticationExecution) []v1alpha1.KeycloakAuthenticationExecution {
+	sorted := make([]v1alpha1.KeycloakAuthenticationExecution, len(executions))
+	copy(sorted, executions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+	return sorted
+}
+
+// AuthorizationPolicyRole is a role of the config of a role policy. Keycloak returns the ID of the role, in a resource
+// the role may be given by the name of a realm role or by clientId/name of a client role as well
+type AuthorizationPolicyRole struct {
+	ID       string `json:"id"`
+	Required bool   `json:"required"`
+}
+
+// AuthorizationPolicyRoles returns the roles of the config of a role policy
+func AuthorizationPolicyRoles(config map[string]string) []AuthorizationPolicyRole {
+	var roles []AuthorizationPolicyRole
+	if err := json.Unmarshal([]byte(config["roles"]), &roles); err != nil {
+		return nil
+	}
+	return roles
+}
+
+// AuthorizationPolicyClients returns the clients of the config of a client policy
+func AuthorizationPolicyClients(config map[string]string) []string {
+	var clients []string
+	if err := json.Unmarshal([]byte(config["clients"]), &clients); err != nil {
+		return nil
+	}
+	return clients
+}
+
 func SanitizeResourceNameWithAlphaNum(text string) string {
 	// we only want letters and numbers
 	reg := []rune(SanitizeResourceName(text))
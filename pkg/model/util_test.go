@@ -124,12 +124,12 @@ func TestKeycloakClientReconciler_Test_Role_DifferenceIntersection(t *testing.T)
 
 func TestKeycloakClientReconciler_Test_ClientScope_DifferenceIntersection(t *testing.T) {
 	// given
-	a := []v1alpha1.KeycloakClientScope{
+	a := []v1alpha1.KeycloakAPIClientScope{
 		{Name: "a"},
 		{ID: "ignored", Name: "b"},
 		{ID: "cID", Name: "c"},
 	}
-	b := []v1alpha1.KeycloakClientScope{
+	b := []v1alpha1.KeycloakAPIClientScope{
 		{Name: "b"},
 		{ID: "cID", Name: "differentName"},
 		{Name: "d"},
@@ -139,13 +139,71 @@ func TestKeycloakClientReconciler_Test_ClientScope_DifferenceIntersection(t *tes
 	difference, intersection := ClientScopeDifferenceIntersection(a, b)
 
 	// then
-	expectedDifference := []v1alpha1.KeycloakClientScope{
+	expectedDifference := []v1alpha1.KeycloakAPIClientScope{
 		{Name: "a"},
 	}
-	expectedIntersection := []v1alpha1.KeycloakClientScope{
+	expectedIntersection := []v1alpha1.KeycloakAPIClientScope{
 		{ID: "ignored", Name: "b"},
 		{ID: "cID", Name: "c"},
 	}
 	assert.Equal(t, expectedDifference, difference)
 	assert.Equal(t, expectedIntersection, intersection)
 }
+
+func TestKeycloakClientReconciler_Test_ProtocolMapper_DifferenceIntersection(t *testing.T) {
+	// given
+	a := []v1alpha1.KeycloakProtocolMapper{
+		{Name: "a"},
+		{ID: "bID", Name: "b"},
+	}
+	b := []v1alpha1.KeycloakProtocolMapper{
+		{ID: "otherID", Name: "b"},
+		{Name: "c"},
+	}
+
+	// when
+	difference, intersection := ProtocolMapperDifferenceIntersection(a, b)
+
+	// then
+	assert.Equal(t, []v1alpha1.KeycloakProtocolMapper{{Name: "a"}}, difference)
+	assert.Equal(t, []v1alpha1.KeycloakProtocolMapper{{ID: "bID", Name: "b"}}, intersection)
+}
+
+func TestKeycloakClientReconciler_Test_ProtocolMapper_Equals(t *testing.T) {
+	// given
+	a := v1alpha1.KeycloakProtocolMapper{
+		ID:             "aID",
+		Name:           "audience",
+		Protocol:       "openid-connect",
+		ProtocolMapper: "oidc-audience-mapper",
+		Config:         map[string]string{"included.client.audience": "backend"},
+	}
+	b := *a.DeepCopy()
+	b.ID = "bID"
+	c := *a.DeepCopy()
+	c.Config = map[string]string{"included.client.audience": "frontend"}
+
+	// then
+	assert.True(t, ProtocolMapperEquals(a, b))
+	assert.False(t, ProtocolMapperEquals(a, c))
+}
+
+func TestUtil_Test_SortAuthenticationExecutions(t *testing.T) {
+	// given
+	executions := []v1alpha1.KeycloakAuthenticationExecution{
+		{Authenticator: "c", Priority: 20},
+		{Authenticator: "a"},
+		{Authenticator: "b"},
+		{Authenticator: "d", Priority: 10},
+	}
+
+	// when
+	sorted := SortAuthenticationExecutions(executions)
+
+	// then
+	assert.Equal(t, "a", sorted[0].Authenticator)
+	assert.Equal(t, "b", sorted[1].Authenticator)
+	assert.Equal(t, "d", sorted[2].Authenticator)
+	assert.Equal(t, "c", sorted[3].Authenticator)
+	assert.Equal(t, "c", executions[0].Authenticator)
+}
@@ -443,7 +443,7 @@ func keycloakClientDefaultRolesTest() error {
 }
 
 func getClientRoleID(authenticatedClient common.KeycloakInterface, clientName, roleName string) (string, error) {
-	retrievedRoles, err := authenticatedClient.ListClientRoles(clientName, realmName)
+	retrievedRoles, err := authenticatedClient.ListClientRoles(context.TODO(), clientName, realmName)
 	if err != nil {
 		return "", err
 	}
@@ -461,7 +461,7 @@ func getRole(retrievedRoles []keycloakv1alpha1.RoleRepresentation, roleName stri
 
 func waitForClientRoles(keycloakCR keycloakv1alpha1.Keycloak, clientCR *keycloakv1alpha1.KeycloakClient, expected []keycloakv1alpha1.RoleRepresentation) error {
 	return WaitForConditionWithClient(keycloakCR, func(authenticatedClient common.KeycloakInterface) error {
-		roles, err := authenticatedClient.ListClientRoles(clientCR.Spec.Client.ID, realmName)
+		roles, err := authenticatedClient.ListClientRoles(context.TODO(), clientCR.Spec.Client.ID, realmName)
 		if err != nil {
 			return err
 		}
@@ -500,12 +500,12 @@ func waitForDefaultClientRoles(keycloakCR keycloakv1alpha1.Keycloak, clientCR *k
 		fmt.Println("waitForDefaultClientRoles")
 		fail := false
 
-		realm, err := authenticatedClient.GetRealm(realmName)
+		realm, err := authenticatedClient.GetRealm(context.TODO(), realmName)
 		if err != nil {
 			return err
 		}
 
-		defaultRoles, err := authenticatedClient.ListRealmRoleClientRoleComposites(realmName, realm.Spec.Realm.DefaultRole.ID, clientCR.Spec.Client.ID)
+		defaultRoles, err := authenticatedClient.ListRealmRoleClientRoleComposites(context.TODO(), realmName, realm.Spec.Realm.DefaultRole.ID, clientCR.Spec.Client.ID)
 		if err != nil {
 			return err
 		}
@@ -669,7 +669,7 @@ func keycloakClientScopeMappingsTest() error {
 		return err
 	}
 
-	retrievedMappings, err := authenticatedClient.ListScopeMappings(testKeycloakClientCRName, realmName)
+	retrievedMappings, err := authenticatedClient.ListScopeMappings(context.TODO(), testKeycloakClientCRName, realmName)
 	if err != nil {
 		return err
 	}
@@ -738,13 +738,13 @@ func keycloakClientServiceAccountRealmRolesTest() error {
 
 func assertServiceAccountRoles(keycloakCR keycloakv1alpha1.Keycloak, clientID string, expectedRealmRoles []string, expectedClientRoles map[string][]string) {
 	err := WaitForConditionWithClient(keycloakCR, func(authenticatedClient common.KeycloakInterface) error {
-		serviceAccountUser, err := authenticatedClient.GetServiceAccountUser(realmName, clientID)
+		serviceAccountUser, err := authenticatedClient.GetServiceAccountUser(context.TODO(), realmName, clientID)
 		if err != nil {
 			return err
 		}
 
 		// get realm role names
-		actualRealmRoles, err := authenticatedClient.ListUserRealmRoles(realmName, serviceAccountUser.ID)
+		actualRealmRoles, err := authenticatedClient.ListUserRealmRoles(context.TODO(), realmName, serviceAccountUser.ID)
 		if err != nil {
 			return err
 		}
@@ -756,7 +756,7 @@ func assertServiceAccountRoles(keycloakCR keycloakv1alpha1.Keycloak, clientID st
 		// get role names for all specified clients
 		var actualClientRolesNames = map[string][]string{}
 		for k := range expectedClientRoles {
-			roles, err := authenticatedClient.ListUserClientRoles(realmName, k, serviceAccountUser.ID)
+			roles, err := authenticatedClient.ListUserClientRoles(context.TODO(), realmName, k, serviceAccountUser.ID)
 			if err != nil {
 				return err
 			}
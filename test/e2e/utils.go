@@ -105,7 +105,7 @@ func WaitForConditionWithClient(keycloakCR keycloakv1alpha1.Keycloak, cond Clien
 
 func MakeAuthenticatedClient(keycloakCR keycloakv1alpha1.Keycloak) (common.KeycloakInterface, error) {
 	keycloakFactory := common.LocalConfigKeycloakFactory{}
-	return keycloakFactory.AuthenticatedClient(keycloakCR, true)
+	return keycloakFactory.AuthenticatedClient(context.TODO(), keycloakCR, true)
 }
 
 func WaitForKeycloakToBeReady(namespace string, name string) error {